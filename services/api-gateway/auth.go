@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"ride-sharing/shared/auth"
+	"ride-sharing/shared/env"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authenticator checks who calls the routes of the gateway from the JWT of the request.
+// Without a verifier, e.g. in local development, callers are trusted to say who they are in their requests.
+type authenticator struct {
+	verifier *auth.Verifier
+}
+
+// newAuthenticator configures authentication from the environment:
+//
+//	AUTH_MODE                 jwt (default) or disabled
+//	AUTH_JWT_ALGORITHM        HS256 (default) or RS256
+//	AUTH_JWT_SECRET           HS256 secret
+//	AUTH_JWT_KEY_FILE         HS256 secret or RS256 PEM public key, read from a file
+//	AUTH_JWKS_URL             RS256 keys published by the identity provider
+//	AUTH_JWKS_REFRESH_MINUTES how often the JWKS is fetched again
+//	AUTH_JWT_ISSUER           expected iss claim
+//	AUTH_JWT_AUDIENCE         expected aud claim
+//	AUTH_JWT_ROLE_CLAIM       claim holding the role, rider, driver or admin
+//	AUTH_JWT_LEEWAY_SECONDS   clock skew tolerated on exp and nbf
+func newAuthenticator() (*authenticator, error) {
+	switch mode := env.GetString("AUTH_MODE", "jwt"); mode {
+	case "disabled":
+		log.Println("Authentication is disabled, callers are trusted with the user id they send")
+		return &authenticator{}, nil
+	case "jwt":
+	default:
+		return nil, fmt.Errorf("unknown auth mode %q", mode)
+	}
+
+	cfg := auth.DefaultConfig()
+	cfg.Algorithm = auth.Algorithm(env.GetString("AUTH_JWT_ALGORITHM", string(cfg.Algorithm)))
+	cfg.Issuer = env.GetString("AUTH_JWT_ISSUER", "")
+	cfg.Audience = env.GetString("AUTH_JWT_AUDIENCE", "")
+	cfg.RoleClaim = env.GetString("AUTH_JWT_ROLE_CLAIM", cfg.RoleClaim)
+	cfg.Leeway = time.Duration(env.GetInt("AUTH_JWT_LEEWAY_SECONDS", int(cfg.Leeway.Seconds()))) * time.Second
+
+	keyFile := env.GetString("AUTH_JWT_KEY_FILE", "")
+	switch cfg.Algorithm {
+	case auth.HS256:
+		cfg.Secret = []byte(env.GetString("AUTH_JWT_SECRET", ""))
+		if keyFile != "" {
+			secret, err := os.ReadFile(keyFile)
+			if err != nil {
+				return nil, err
+			}
+			cfg.Secret = []byte(strings.TrimSpace(string(secret)))
+		}
+	case auth.RS256:
+		if jwksURL := env.GetString("AUTH_JWKS_URL", ""); jwksURL != "" {
+			cfg.JWKS = auth.NewJWKS(jwksURL, time.Duration(env.GetInt("AUTH_JWKS_REFRESH_MINUTES", 60))*time.Minute)
+		} else if keyFile != "" {
+			key, err := auth.LoadPublicKeyFile(keyFile)
+			if err != nil {
+				return nil, err
+			}
+			cfg.PublicKey = key
+		}
+	}
+
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth configuration: %w", err)
+	}
+
+	return &authenticator{verifier: verifier}, nil
+}
+
+// require lets through the callers with one of the roles, with their identity in the request context.
+func (a *authenticator) require(handler http.HandlerFunc, roles ...auth.Role) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.verifier == nil {
+			handler(w, r)
+			return
+		}
+
+		token := bearerToken(r)
+		if token == "" {
+			writeGRPCError(w, status.Error(codes.Unauthenticated, "missing bearer token"))
+			return
+		}
+
+		identity, err := a.verifier.Verify(r.Context(), token)
+		if err != nil {
+			log.Printf("Rejected token for %s: %v", r.URL.Path, err)
+			writeGRPCError(w, status.Error(codes.Unauthenticated, "invalid or expired token"))
+			return
+		}
+
+		if !slices.Contains(roles, identity.Role) {
+			writeGRPCError(w, status.Errorf(codes.PermissionDenied, "%s users can't access this route", identity.Role))
+			return
+		}
+
+		handler(w, r.WithContext(auth.NewContext(r.Context(), identity)))
+	}
+}
+
+func bearerToken(r *http.Request) string {
+	if header, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(header)
+	}
+
+	// Browsers can't set headers on WebSocket handshakes, the token goes in the URL instead
+	if websocket.IsWebSocketUpgrade(r) {
+		return r.URL.Query().Get("access_token")
+	}
+
+	return ""
+}
+
+// callerID returns the user ID of the caller of an authenticated route.
+// The user ID a caller sends is only trusted when authentication is disabled, otherwise it has to match its token.
+func callerID(r *http.Request, claimed string) (string, error) {
+	identity := auth.FromContext(r.Context())
+	if identity == nil {
+		if claimed == "" {
+			return "", status.Error(codes.InvalidArgument, "user id is required")
+		}
+		return claimed, nil
+	}
+
+	if claimed != "" && claimed != identity.UserID {
+		return "", status.Error(codes.PermissionDenied, "user id does not match the token")
+	}
+
+	return identity.UserID, nil
+}
+
+// checkDriverAccess lets drivers see their own data only, admins can see any driver's.
+func checkDriverAccess(r *http.Request, driverID string) error {
+	identity := auth.FromContext(r.Context())
+	if identity == nil || identity.Role == auth.RoleAdmin || identity.UserID == driverID {
+		return nil
+	}
+
+	return status.Error(codes.PermissionDenied, "drivers can only access their own data")
+}
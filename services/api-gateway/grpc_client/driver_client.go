@@ -3,6 +3,7 @@ package grpcclient
 import (
 	"os"
 
+	"ride-sharing/shared/auth"
 	pb "ride-sharing/shared/proto/driver"
 	"ride-sharing/shared/tracing"
 
@@ -21,10 +22,9 @@ func NewDriverServiceClient() (*driverServiceClient, error) {
 		driverServiceURL = "driver-service:9092"
 	}
 
-	dialOpts := append(
-		tracing.DialOptionsWithTracing(),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	// The identity of the request the call is made for goes along with it
+	dialOpts := append(tracing.DialOptionsWithTracing(), auth.DialOptions()...)
+	dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 
 	conn, err := grpc.NewClient(driverServiceURL, dialOpts...)
 	if err != nil {
@@ -0,0 +1,47 @@
+package grpcclient
+
+import (
+	"os"
+	"ride-sharing/shared/auth"
+	pb "ride-sharing/shared/proto/payment"
+	"ride-sharing/shared/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type paymentServiceClient struct {
+	Client pb.PaymentServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewPaymentServiceClient() (*paymentServiceClient, error) {
+	paymentServiceURL := os.Getenv("PAYMENT_SERVICE_URL")
+	if paymentServiceURL == "" {
+		paymentServiceURL = "payment-service:9004"
+	}
+
+	// The identity of the request the call is made for goes along with it
+	dialOpts := append(tracing.DialOptionsWithTracing(), auth.DialOptions()...)
+	dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+
+	conn, err := grpc.NewClient(paymentServiceURL, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := pb.NewPaymentServiceClient(conn)
+
+	return &paymentServiceClient{
+		Client: client,
+		conn:   conn,
+	}, nil
+}
+
+func (c *paymentServiceClient) Close() {
+	if c.conn != nil {
+		if err := c.conn.Close(); err != nil {
+			return
+		}
+	}
+}
@@ -2,6 +2,7 @@ package grpcclient
 
 import (
 	"os"
+	"ride-sharing/shared/auth"
 	pb "ride-sharing/shared/proto/trip"
 	"ride-sharing/shared/tracing"
 
@@ -20,10 +21,9 @@ func NewTripServiceClient() (*tripServiceClient, error) {
 		tripServiceURL = "trip-service:9093"
 	}
 
-	dialOpts := append(
-		tracing.DialOptionsWithTracing(),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	// The identity of the request the call is made for goes along with it
+	dialOpts := append(tracing.DialOptionsWithTracing(), auth.DialOptions()...)
+	dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 
 	conn, err := grpc.NewClient(tripServiceURL, dialOpts...)
 	if err != nil {
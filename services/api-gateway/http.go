@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -9,6 +10,8 @@ import (
 	"ride-sharing/shared/contracts"
 	"ride-sharing/shared/env"
 	"ride-sharing/shared/messaging"
+	"ride-sharing/shared/proto/driver"
+	"ride-sharing/shared/proto/payment"
 	"ride-sharing/shared/tracing"
 
 	"github.com/stripe/stripe-go/v81"
@@ -31,10 +34,12 @@ func handleTripPreview(w http.ResponseWriter, r *http.Request) {
 
 	defer r.Body.Close()
 
-	if reqBody.UserID == "" {
-		http.Error(w, "user id is required", http.StatusBadRequest)
+	userID, err := callerID(r, reqBody.UserID)
+	if err != nil {
+		writeGRPCError(w, err)
 		return
 	}
+	reqBody.UserID = userID
 
 	tripService, err := grpcclient.NewTripServiceClient()
 	if err != nil {
@@ -46,7 +51,7 @@ func handleTripPreview(w http.ResponseWriter, r *http.Request) {
 	tripPreview, err := tripService.Client.PreviewTrip(ctx, reqBody.ToProto())
 	if err != nil {
 		log.Printf("failed to preview trip: %v", err)
-		http.Error(w, "failed to preview trip", http.StatusInternalServerError)
+		writeGRPCError(w, err)
 		return
 	}
 
@@ -67,10 +72,12 @@ func handleTripStart(w http.ResponseWriter, r *http.Request) {
 
 	defer r.Body.Close()
 
-	if reqBody.UserID == "" {
-		http.Error(w, "user id is required", http.StatusBadRequest)
+	userID, err := callerID(r, reqBody.UserID)
+	if err != nil {
+		writeGRPCError(w, err)
 		return
 	}
+	reqBody.UserID = userID
 
 	tripService, err := grpcclient.NewTripServiceClient()
 	if err != nil {
@@ -82,7 +89,7 @@ func handleTripStart(w http.ResponseWriter, r *http.Request) {
 	trip, err := tripService.Client.CreateTrip(ctx, reqBody.ToProto())
 	if err != nil {
 		log.Printf("failed to start trip: %v", err)
-		http.Error(w, "failed to start trip", http.StatusInternalServerError)
+		writeGRPCError(w, err)
 		return
 	}
 
@@ -91,6 +98,42 @@ func handleTripStart(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusCreated, apiRes)
 }
 
+func handleTripRating(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "handleTripRating")
+	defer span.End()
+
+	var reqBody rateTripRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		http.Error(w, "failed to parse JSON data", http.StatusBadRequest)
+		return
+	}
+
+	defer r.Body.Close()
+
+	userID, err := callerID(r, reqBody.UserID)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	reqBody.UserID = userID
+
+	tripService, err := grpcclient.NewTripServiceClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer tripService.Close()
+
+	rated, err := tripService.Client.RateTrip(ctx, reqBody.ToProto(r.PathValue("tripID")))
+	if err != nil {
+		log.Printf("failed to rate trip: %v", err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, contracts.APIResponse{Data: rated.Trip})
+}
+
 func handleStripeWebhook(w http.ResponseWriter, r *http.Request, rabbitmq *messaging.Rabbitmq) {
 	ctx, span := tracer.Start(r.Context(), "handleTripStart")
 	defer span.End()
@@ -163,3 +206,95 @@ func handleStripeWebhook(w http.ResponseWriter, r *http.Request, rabbitmq *messa
 		}
 	}
 }
+
+func handleDriverStatement(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "handleDriverStatement")
+	defer span.End()
+
+	if err := checkDriverAccess(r, r.PathValue("driverID")); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	paymentService, err := grpcclient.NewPaymentServiceClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer paymentService.Close()
+
+	statement, err := paymentService.Client.GetDriverStatement(ctx, driverStatementRequest(r))
+	if err != nil {
+		log.Printf("failed to get driver statement: %v", err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, contracts.APIResponse{Data: statement})
+}
+
+func handleDriverStats(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "handleDriverStats")
+	defer span.End()
+
+	if err := checkDriverAccess(r, r.PathValue("driverID")); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	driverService, err := grpcclient.NewDriverServiceClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer driverService.Close()
+
+	stats, err := driverService.Client.GetDriverStats(ctx, &driver.GetDriverStatsRequest{
+		DriverID: r.PathValue("driverID"),
+	})
+	if err != nil {
+		log.Printf("failed to get driver stats: %v", err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, contracts.APIResponse{Data: stats})
+}
+
+func handleDriverStatementCSV(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "handleDriverStatementCSV")
+	defer span.End()
+
+	if err := checkDriverAccess(r, r.PathValue("driverID")); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	paymentService, err := grpcclient.NewPaymentServiceClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer paymentService.Close()
+
+	export, err := paymentService.Client.ExportDriverStatement(ctx, driverStatementRequest(r))
+	if err != nil {
+		log.Printf("failed to export driver statement: %v", err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(export.Csv)
+}
+
+// driverStatementRequest reads the statement a driver asks for, e.g. /drivers/{driverID}/statement?period=daily&date=2025-01-31
+func driverStatementRequest(r *http.Request) *payment.GetDriverStatementRequest {
+	return &payment.GetDriverStatementRequest{
+		DriverID: r.PathValue("driverID"),
+		Period:   r.URL.Query().Get("period"),
+		Date:     r.URL.Query().Get("date"),
+	}
+}
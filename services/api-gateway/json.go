@@ -3,6 +3,10 @@ package main
 import (
 	"encoding/json"
 	"net/http"
+	"ride-sharing/shared/contracts"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func writeJSON(w http.ResponseWriter, status int, data any) error {
@@ -11,3 +15,38 @@ func writeJSON(w http.ResponseWriter, status int, data any) error {
 
 	return json.NewEncoder(w).Encode(data)
 }
+
+// writeGRPCError translates an error returned by a downstream gRPC service into an API error response.
+func writeGRPCError(w http.ResponseWriter, err error) error {
+	st := status.Convert(err)
+
+	return writeJSON(w, httpStatusFromCode(st.Code()), contracts.APIResponse{
+		Error: &contracts.APIError{
+			Code:    st.Code().String(),
+			Message: st.Message(),
+		},
+	})
+}
+
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+
+	return http.StatusInternalServerError
+}
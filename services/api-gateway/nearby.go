@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	grpcclient "ride-sharing/services/api-gateway/grpc_client"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+	"ride-sharing/shared/proto/driver"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// nearbyDriversWatch bridges the nearby drivers stream of driver service to the WebSocket of a rider.
+// A rider has at most one watch, starting another one stops the previous.
+type nearbyDriversWatch struct {
+	userID        string
+	notifications *messaging.NotificationDispatcher
+	mu            sync.Mutex
+	cancel        context.CancelFunc
+}
+
+func newNearbyDriversWatch(userID string, notifications *messaging.NotificationDispatcher) *nearbyDriversWatch {
+	return &nearbyDriversWatch{
+		userID:        userID,
+		notifications: notifications,
+	}
+}
+
+// Start opens the stream of nearby drivers and forwards its updates until the watch is stopped or replaced.
+// It returns the status of the failure when the stream cannot be opened, so the rider is not told they watch.
+func (w *nearbyDriversWatch) Start(ctx context.Context, data json.RawMessage) error {
+	var payload contracts.WSWatchNearbyDriversData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid watch: %v", err)
+	}
+
+	req := &driver.WatchNearbyDriversRequest{
+		RadiusMeters: payload.RadiusMeters,
+		PackageSlug:  payload.PackageSlug,
+	}
+
+	switch {
+	case payload.Viewport != nil:
+		req.Viewport = &driver.Viewport{
+			SouthWest: &driver.Location{Latitude: payload.Viewport.SouthWest.Latitude, Longitude: payload.Viewport.SouthWest.Longitude},
+			NorthEast: &driver.Location{Latitude: payload.Viewport.NorthEast.Latitude, Longitude: payload.Viewport.NorthEast.Longitude},
+		}
+	case payload.Center != nil:
+		req.Center = &driver.Location{Latitude: payload.Center.Latitude, Longitude: payload.Center.Longitude}
+	default:
+		return status.Error(codes.InvalidArgument, "invalid watch: either a viewport or a center is required")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	w.mu.Lock()
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.cancel = cancel
+	w.mu.Unlock()
+
+	driverService, err := grpcclient.NewDriverServiceClient()
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	stream, err := driverService.Client.WatchNearbyDrivers(ctx, req)
+	var first *driver.NearbyDriversUpdate
+	if err == nil {
+		// Driver service starts every watch with a snapshot, or rejects it with the status of the stream
+		first, err = stream.Recv()
+	}
+	if err != nil {
+		driverService.Close()
+		cancel()
+		return err
+	}
+
+	go func() {
+		defer driverService.Close()
+		w.run(ctx, stream, first)
+	}()
+
+	return nil
+}
+
+func (w *nearbyDriversWatch) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel != nil {
+		w.cancel()
+		w.cancel = nil
+	}
+}
+
+func (w *nearbyDriversWatch) run(ctx context.Context, stream grpc.ServerStreamingClient[driver.NearbyDriversUpdate], update *driver.NearbyDriversUpdate) {
+	for {
+		// The watch may have been replaced while the update was on its way
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Updates are superseded by the next ones, a reconnecting rider watches again rather than replays them
+		if err := w.notifications.DeliverEphemeral(w.userID, contracts.WSMessage{
+			Type: contracts.DriverEventNearby,
+			Data: update,
+		}); err != nil {
+			log.Printf("Failed to send nearby drivers to rider %s: %v", w.userID, err)
+			return
+		}
+
+		var err error
+		update, err = stream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Nearby drivers stream of rider %s ended: %v", w.userID, err)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"ride-sharing/shared/env"
+	"ride-sharing/shared/presence"
+
+	"github.com/google/uuid"
+)
+
+// newPresenceRegistry creates the registry of the instances users are connected to from the environment.
+// The in-memory registry is enough for a single instance, running several needs Redis.
+func newPresenceRegistry() (presence.Registry, error) {
+	switch backend := env.GetString("PRESENCE_BACKEND", "memory"); backend {
+	case "memory":
+		return presence.NewMemoryRegistry(), nil
+	case "redis":
+		cfg := presence.DefaultRedisConfig()
+		cfg.Addr = env.GetString("REDIS_ADDR", cfg.Addr)
+		cfg.Password = env.GetString("REDIS_PASSWORD", cfg.Password)
+		cfg.DB = env.GetInt("REDIS_DB", cfg.DB)
+		cfg.Timeout = time.Duration(env.GetInt("REDIS_TIMEOUT_MS", int(cfg.Timeout.Milliseconds()))) * time.Millisecond
+		return presence.NewRedisRegistry(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown PRESENCE_BACKEND %q, expected memory or redis", backend)
+	}
+}
+
+// instanceID identifies this gateway among the running ones, the pod name on Kubernetes.
+func instanceID() string {
+	if id := env.GetString("GATEWAY_INSTANCE_ID", ""); id != "" {
+		return id
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		id := uuid.New().String()
+		log.Printf("Failed to get the hostname, using %s as instance ID: %v", id, err)
+		return id
+	}
+
+	return hostname
+}
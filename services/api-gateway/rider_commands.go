@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	grpcclient "ride-sharing/services/api-gateway/grpc_client"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+	pbp "ride-sharing/shared/proto/payment"
+	pb "ride-sharing/shared/proto/trip"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// handleRiderCommand runs a command a rider sent over the WebSocket and answers it with an ack carrying
+// its result, or with an error carrying the gRPC status code of the failure.
+func handleRiderCommand(ctx context.Context, notifications *messaging.NotificationDispatcher, userID string, msg contracts.WSRiderMessage, nearbyDrivers *nearbyDriversWatch) {
+	result, err := runRiderCommand(ctx, userID, msg, nearbyDrivers)
+
+	answer := contracts.WSMessage{
+		Type: contracts.RiderEventCommandAck,
+		Data: contracts.WSCommandAckData{
+			RequestID: msg.RequestID,
+			Command:   msg.Type,
+			Result:    result,
+		},
+	}
+	if err != nil {
+		log.Printf("Command %s of rider %s failed: %v", msg.Type, userID, err)
+
+		st := status.Convert(err)
+		answer = contracts.WSMessage{
+			Type: contracts.RiderEventCommandError,
+			Data: contracts.WSCommandErrorData{
+				RequestID: msg.RequestID,
+				Command:   msg.Type,
+				Code:      st.Code().String(),
+				Message:   st.Message(),
+			},
+		}
+	}
+
+	// Answers are kept for replay, a rider whose socket dropped right after a command still learns how it went
+	notifications.Deliver(userID, answer)
+}
+
+func runRiderCommand(ctx context.Context, userID string, msg contracts.WSRiderMessage, nearbyDrivers *nearbyDriversWatch) (any, error) {
+	switch msg.Type {
+	case contracts.RiderCmdTripCancel:
+		cancelled, err := cancelTrip(ctx, userID, roleRider, msg.Data)
+		if err != nil {
+			return nil, err
+		}
+		return cancelled.Trip, nil
+	case contracts.RiderCmdUpdatePickup:
+		return updatePickup(ctx, userID, msg.Data)
+	case contracts.RiderCmdAddStop:
+		return addStop(ctx, userID, msg.Data)
+	case contracts.RiderCmdRateDriver:
+		return rateDriver(ctx, userID, msg.Data)
+	case contracts.RiderCmdRequestReceipt:
+		return requestReceipt(ctx, userID, msg.Data)
+	case contracts.RiderCmdWatchNearbyDrivers:
+		if err := nearbyDrivers.Start(ctx, msg.Data); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	case contracts.RiderCmdUnwatchNearbyDrivers:
+		nearbyDrivers.Stop()
+		return nil, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown message type: %s", msg.Type)
+	}
+}
+
+// decodeCommand unmarshals the payload of a command, a malformed payload is the rider's mistake.
+func decodeCommand(data json.RawMessage, payload any) error {
+	if err := json.Unmarshal(data, payload); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid payload: %v", err)
+	}
+
+	return nil
+}
+
+func updatePickup(ctx context.Context, userID string, data json.RawMessage) (*pb.Trip, error) {
+	var payload contracts.WSUpdatePickupData
+	if err := decodeCommand(data, &payload); err != nil {
+		return nil, err
+	}
+
+	tripService, err := grpcclient.NewTripServiceClient()
+	if err != nil {
+		return nil, err
+	}
+	defer tripService.Close()
+
+	// The trip service tells the assigned driver through trip.event.updated
+	updated, err := tripService.Client.UpdatePickup(ctx, &pb.UpdatePickupRequest{
+		TripID: payload.TripID,
+		UserID: userID,
+		Pickup: &pb.Coordinate{Latitude: payload.Pickup.Latitude, Longitude: payload.Pickup.Longitude},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.Trip, nil
+}
+
+func addStop(ctx context.Context, userID string, data json.RawMessage) (*pb.Trip, error) {
+	var payload contracts.WSAddStopData
+	if err := decodeCommand(data, &payload); err != nil {
+		return nil, err
+	}
+
+	tripService, err := grpcclient.NewTripServiceClient()
+	if err != nil {
+		return nil, err
+	}
+	defer tripService.Close()
+
+	updated, err := tripService.Client.AddStop(ctx, &pb.AddStopRequest{
+		TripID:   payload.TripID,
+		UserID:   userID,
+		Location: &pb.Coordinate{Latitude: payload.Location.Latitude, Longitude: payload.Location.Longitude},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.Trip, nil
+}
+
+func rateDriver(ctx context.Context, userID string, data json.RawMessage) (*pb.Trip, error) {
+	var payload contracts.WSRateDriverData
+	if err := decodeCommand(data, &payload); err != nil {
+		return nil, err
+	}
+
+	tripService, err := grpcclient.NewTripServiceClient()
+	if err != nil {
+		return nil, err
+	}
+	defer tripService.Close()
+
+	rated, err := tripService.Client.RateTrip(ctx, &pb.RateTripRequest{
+		TripID:  payload.TripID,
+		UserID:  userID,
+		Rating:  payload.Rating,
+		Comment: payload.Comment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rated.Trip, nil
+}
+
+func requestReceipt(ctx context.Context, userID string, data json.RawMessage) (*pbp.TripReceipt, error) {
+	var payload contracts.WSRequestReceiptData
+	if err := decodeCommand(data, &payload); err != nil {
+		return nil, err
+	}
+
+	paymentService, err := grpcclient.NewPaymentServiceClient()
+	if err != nil {
+		return nil, err
+	}
+	defer paymentService.Close()
+
+	return paymentService.Client.GetTripReceipt(ctx, &pbp.GetTripReceiptRequest{
+		TripID: payload.TripID,
+		UserID: userID,
+	})
+}
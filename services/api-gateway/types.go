@@ -6,10 +6,17 @@ import (
 	pb "ride-sharing/shared/proto/trip"
 )
 
+// Roles of the users connected to the gateway
+const (
+	roleRider  = "rider"
+	roleDriver = "driver"
+)
+
 type previewTripRequest struct {
-	UserID      string           `json:"userID`
+	UserID      string           `json:"userID"`
 	Pickup      types.Coordinate `json:"pickup"`
 	Destination types.Coordinate `json:"destination"`
+	PromoCode   string           `json:"promoCode,omitempty"`
 }
 
 func (p *previewTripRequest) ToProto() *pb.PreviewTripRequest {
@@ -23,6 +30,22 @@ func (p *previewTripRequest) ToProto() *pb.PreviewTripRequest {
 			Latitude:  p.Destination.Latitude,
 			Longitude: p.Destination.Longitude,
 		},
+		PromoCode: p.PromoCode,
+	}
+}
+
+type rateTripRequest struct {
+	UserID  string `json:"userID"`
+	Rating  int32  `json:"rating"`
+	Comment string `json:"comment,omitempty"`
+}
+
+func (r *rateTripRequest) ToProto(tripID string) *pb.RateTripRequest {
+	return &pb.RateTripRequest{
+		TripID:  tripID,
+		UserID:  r.UserID,
+		Rating:  r.Rating,
+		Comment: r.Comment,
 	}
 }
 
@@ -1,20 +1,29 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	grpcclient "ride-sharing/services/api-gateway/grpc_client"
 	"ride-sharing/shared/contracts"
 	"ride-sharing/shared/messaging"
 	"ride-sharing/shared/proto/driver"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc/status"
+
+	pb "ride-sharing/shared/proto/trip"
 )
 
 var (
 	connManager = messaging.NewConnectionManager()
 )
 
-func handleRidersWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *messaging.Rabbitmq) {
+func handleRidersWebSocket(w http.ResponseWriter, r *http.Request, notifications *messaging.NotificationDispatcher) {
 	conn, err := connManager.Upgrade(w, r)
 	if err != nil {
 		log.Printf("WebSocket upgrade failed: %v", err)
@@ -23,30 +32,20 @@ func handleRidersWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *mes
 
 	defer conn.Close()
 
-	userID := r.URL.Query().Get("userID")
-	if userID == "" {
-		log.Println("No user id provided")
+	userID, err := callerID(r, r.URL.Query().Get("userID"))
+	if err != nil {
+		log.Printf("Rejected connection: %v", err)
 		return
 	}
 
 	connManager.Add(userID, conn)
-	defer connManager.Remove(userID)
+	defer connManager.Remove(userID, conn)
 
-	// Initialize queue consumers
-	queues := []string{
-		messaging.NotifyDriverNoDriversFoundQueue,
-		messaging.NotifyDriverAssignedQueue,
-		messaging.NotifyPaymentSessionCreatedQueue,
-	}
+	// Send what the rider missed while disconnected
+	notifications.Connected(r.Context(), userID, lastSeq(r))
 
-	for _, q := range queues {
-		consumer := messaging.NewQueueConsumer(rabbitmq, connManager, q)
-
-		if err := consumer.Start(); err != nil {
-			log.Printf("Failed to start consumer for queue: %s: %v", q, err)
-			return
-		}
-	}
+	nearbyDrivers := newNearbyDriversWatch(userID, notifications)
+	defer nearbyDrivers.Stop()
 
 	for {
 		_, message, err := conn.ReadMessage()
@@ -55,11 +54,17 @@ func handleRidersWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *mes
 			break
 		}
 
-		log.Printf("Received message: %s", message)
+		var riderMsg contracts.WSRiderMessage
+		if err := json.Unmarshal(message, &riderMsg); err != nil {
+			log.Printf("Failed to unmarshal message: %v", err)
+			continue
+		}
+
+		handleRiderCommand(r.Context(), notifications, userID, riderMsg, nearbyDrivers)
 	}
 }
 
-func handleDriversWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *messaging.Rabbitmq) {
+func handleDriversWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *messaging.Rabbitmq, notifications *messaging.NotificationDispatcher) {
 	conn, err := connManager.Upgrade(w, r)
 	if err != nil {
 		log.Printf("WebSocket upgrade failed: %v", err)
@@ -68,9 +73,9 @@ func handleDriversWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *me
 
 	defer conn.Close()
 
-	userID := r.URL.Query().Get("userID")
-	if userID == "" {
-		log.Println("No user id provided")
+	userID, err := callerID(r, r.URL.Query().Get("userID"))
+	if err != nil {
+		log.Printf("Rejected connection: %v", err)
 		return
 	}
 
@@ -81,9 +86,11 @@ func handleDriversWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *me
 	}
 
 	connManager.Add(userID, conn)
-	defer connManager.Remove(userID)
+	defer connManager.Remove(userID, conn)
 
 	ctx := r.Context()
+	// lastLocationAt is when the last location of this driver was forwarded
+	var lastLocationAt time.Time
 
 	driverService, err := grpcclient.NewDriverServiceClient()
 	if err != nil {
@@ -92,8 +99,6 @@ func handleDriversWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *me
 
 	// Closing connections
 	defer func() {
-		connManager.Remove(userID)
-
 		driverService.Client.UnRegisterDriver(ctx, &driver.RegisterDriverRequest{
 			DriverID:    userID,
 			PackageSlug: packageSlug,
@@ -110,10 +115,16 @@ func handleDriversWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *me
 	})
 	if err != nil {
 		log.Printf("Failed to register driver: %v", err)
+		// Tell the app why it was disconnected, e.g. the driver is unknown or not approved
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(
+			websocket.ClosePolicyViolation,
+			fmt.Sprintf("registration failed: %s", status.Code(err)),
+		))
 		return
 	}
 
-	if err := connManager.SendMessage(userID, contracts.WSMessage{
+	// The registration is sent on every connection, there is no point in replaying it
+	if err := notifications.DeliverEphemeral(userID, contracts.WSMessage{
 		Type: contracts.DriverCmdRegister,
 		Data: driverData.Driver,
 	}); err != nil {
@@ -121,19 +132,8 @@ func handleDriversWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *me
 		return
 	}
 
-	// Initialize queue consumers
-	queues := []string{
-		messaging.DriverCmdTripRequestQueue,
-	}
-
-	for _, q := range queues {
-		consumer := messaging.NewQueueConsumer(rabbitmq, connManager, q)
-
-		if err := consumer.Start(); err != nil {
-			log.Printf("Failed to start consumer for queue: %s: %v", q, err)
-			return
-		}
-	}
+	// Send what the driver missed while disconnected, after the registration the app waits for
+	notifications.Connected(ctx, userID, lastSeq(r))
 
 	for {
 		_, message, err := conn.ReadMessage()
@@ -156,9 +156,19 @@ func handleDriversWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *me
 		// Handle the different message type
 		switch driverMsg.Type {
 		case contracts.DriverCmdLocation:
-			// Handle driver localtion update in the future
-			continue
-		case contracts.DriverCmdTripAccept, contracts.DriverCmdTripDecline:
+			// Apps may report positions faster than we need them, extra updates are dropped
+			now := time.Now()
+			if now.Sub(lastLocationAt) < driverLocationMinInterval {
+				continue
+			}
+
+			if err := forwardDriverLocation(ctx, rabbitmq, userID, now, driverMsg.Data); err != nil {
+				log.Printf("Failed to forward location of driver %s: %v", userID, err)
+				continue
+			}
+			lastLocationAt = now
+		case contracts.DriverCmdTripAccept, contracts.DriverCmdTripDecline,
+			contracts.DriverCmdTripArrive, contracts.DriverCmdTripStart, contracts.DriverCmdTripEnd:
 			// Forward the message to RabbitMQ
 			if err := rabbitmq.PublishMessage(ctx, driverMsg.Type, contracts.AmqpMessage{
 				OwnerID: userID,
@@ -166,8 +176,76 @@ func handleDriversWebSocket(w http.ResponseWriter, r *http.Request, rabbitmq *me
 			}); err != nil {
 				log.Printf("Failed to pusblish message to RabbitMQ: %v", err)
 			}
+		case contracts.DriverCmdTripCancel:
+			if _, err := cancelTrip(ctx, userID, roleDriver, driverMsg.Data); err != nil {
+				log.Printf("Failed to cancel trip: %v", err)
+			}
 		default:
 			log.Printf("Unknown message type: %s", driverMsg.Type)
 		}
 	}
 }
+
+// lastSeq is the sequence number of the last message a reconnecting client got, nil on a first connection.
+func lastSeq(r *http.Request) *uint64 {
+	value := r.URL.Query().Get("lastSeq")
+	if value == "" {
+		return nil
+	}
+
+	seq, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		log.Printf("Ignoring invalid lastSeq %q: %v", value, err)
+		return nil
+	}
+
+	return &seq
+}
+
+func forwardDriverLocation(ctx context.Context, rabbitmq *messaging.Rabbitmq, driverID string, receivedAt time.Time, data json.RawMessage) error {
+	var payload contracts.WSDriverLocationData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+
+	lat, lon := payload.Location.Latitude, payload.Location.Longitude
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return fmt.Errorf("invalid coordinates: %f, %f", lat, lon)
+	}
+
+	location, err := json.Marshal(messaging.DriverLocationData{
+		DriverID:   driverID,
+		Latitude:   lat,
+		Longitude:  lon,
+		RecordedAt: receivedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return rabbitmq.PublishMessage(ctx, contracts.DriverCmdLocation, contracts.AmqpMessage{
+		OwnerID: driverID,
+		Data:    location,
+	})
+}
+
+func cancelTrip(ctx context.Context, userID, role string, data json.RawMessage) (*pb.CancelTripResponse, error) {
+	var payload contracts.WSTripCancelData
+	if err := decodeCommand(data, &payload); err != nil {
+		return nil, err
+	}
+
+	tripService, err := grpcclient.NewTripServiceClient()
+	if err != nil {
+		return nil, err
+	}
+	defer tripService.Close()
+
+	// The trip service notifies both parties through trip.event.cancelled
+	return tripService.Client.CancelTrip(ctx, &pb.CancelTripRequest{
+		TripID: payload.TripID,
+		UserID: userID,
+		Role:   role,
+		Reason: payload.Reason,
+	})
+}
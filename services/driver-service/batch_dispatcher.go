@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math"
+	"ride-sharing/services/driver-service/internal/matching"
+	"ride-sharing/shared/messaging"
+	"sync"
+	"time"
+)
+
+// BatchConfig controls batch matching, where the trips requested in a short window
+// are matched together to the available drivers instead of one at a time.
+type BatchConfig struct {
+	// Areas are the slugs of the service areas matched in batches, the others are dispatched one trip at a time
+	Areas map[string]bool
+	// Window is how long trip requests are collected before being matched
+	Window time.Duration
+	// CandidatesPerTrip is how many of the closest drivers are considered for each trip
+	CandidatesPerTrip int
+	// AverageSpeedKmh turns pickup distances into ETAs
+	AverageSpeedKmh float64
+}
+
+func DefaultBatchConfig() *BatchConfig {
+	return &BatchConfig{
+		Areas:             map[string]bool{},
+		Window:            2 * time.Second,
+		CandidatesPerTrip: 10,
+		AverageSpeedKmh:   25,
+	}
+}
+
+// batchDispatcher picks the first driver offered each trip of a batch so that the total pickup ETA is minimal,
+// then hands the trips to the dispatcher, which deals with declines and timeouts as usual.
+type batchDispatcher struct {
+	ctx        context.Context
+	service    *Service
+	dispatcher *dispatcher
+	config     *BatchConfig
+	radius     float64
+
+	mu sync.Mutex
+	// pending holds the trips waiting for the next batch of their service area
+	pending map[string][]messaging.TripEventData
+}
+
+// NewBatchDispatcher uses ctx for the batches matched when their window closes.
+func NewBatchDispatcher(ctx context.Context, service *Service, dispatcher *dispatcher, config *BatchConfig, radiusMeters float64) *batchDispatcher {
+	return &batchDispatcher{
+		ctx:        ctx,
+		service:    service,
+		dispatcher: dispatcher,
+		config:     config,
+		radius:     radiusMeters,
+		pending:    make(map[string][]messaging.TripEventData),
+	}
+}
+
+// Dispatch queues the trip for the next batch of its area, or dispatches it right away
+// when batch matching is off in the area.
+func (b *batchDispatcher) Dispatch(ctx context.Context, event messaging.TripEventData) error {
+	area := event.Trip.GetSelectedFare().GetServiceArea()
+	if !b.config.Areas[area] || tripPickup(event.Trip) == nil {
+		return b.dispatcher.Dispatch(ctx, event)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// The first trip of a batch opens its window
+	if len(b.pending[area]) == 0 {
+		time.AfterFunc(b.config.Window, func() {
+			b.flush(area)
+		})
+	}
+	b.pending[area] = append(b.pending[area], event)
+
+	return nil
+}
+
+func (b *batchDispatcher) flush(area string) {
+	b.mu.Lock()
+	trips := b.pending[area]
+	delete(b.pending, area)
+	b.mu.Unlock()
+
+	if len(trips) == 0 {
+		return
+	}
+
+	assignment := b.match(trips)
+
+	matched := 0
+	for i, event := range trips {
+		var err error
+		if driverID := assignment[i]; driverID != "" {
+			matched++
+			err = b.dispatcher.DispatchTo(b.ctx, event, driverID)
+		} else {
+			err = b.dispatcher.Dispatch(b.ctx, event)
+		}
+
+		if err != nil {
+			log.Printf("Failed to dispatch trip %s: %v", event.Trip.Id, err)
+		}
+	}
+
+	log.Printf("Batch of area %s matched %d of %d trips", area, matched, len(trips))
+}
+
+// match returns the driver to offer each trip first, empty for the trips left without one.
+func (b *batchDispatcher) match(trips []messaging.TripEventData) []string {
+	var drivers []string
+	driverIndex := make(map[string]int)
+	etas := make([]map[int]float64, len(trips))
+
+	for i, event := range trips {
+		etas[i] = make(map[int]float64)
+
+		candidates := b.service.FindAvailableDrivers(event.Trip.SelectedFare.PackageSlug, tripPickup(event.Trip), b.radius, b.config.CandidatesPerTrip, nil)
+		for _, candidate := range candidates {
+			j, ok := driverIndex[candidate.ID]
+			if !ok {
+				j = len(drivers)
+				driverIndex[candidate.ID] = j
+				drivers = append(drivers, candidate.ID)
+			}
+
+			etas[i][j] = matching.PickupETA(candidate.DistanceMeters, b.config.AverageSpeedKmh).Seconds()
+		}
+	}
+
+	assignment := make([]string, len(trips))
+	if len(drivers) == 0 {
+		return assignment
+	}
+
+	// Drivers that are not among the closest candidates of a trip cannot be given that trip
+	cost := make([][]float64, len(trips))
+	for i := range cost {
+		cost[i] = make([]float64, len(drivers))
+		for j := range cost[i] {
+			eta, ok := etas[i][j]
+			if !ok {
+				eta = math.Inf(1)
+			}
+			cost[i][j] = eta
+		}
+	}
+
+	for i, j := range matching.Solve(cost) {
+		if j != matching.Unmatched {
+			assignment[i] = drivers[j]
+		}
+	}
+
+	return assignment
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"ride-sharing/services/driver-service/internal/geoindex"
+	"ride-sharing/services/driver-service/internal/matching"
+	"ride-sharing/shared/messaging"
+	pbt "ride-sharing/shared/proto/trip"
+)
+
+// Bounding box of San Francisco
+const (
+	simMinLat, simMaxLat = 37.70, 37.82
+	simMinLon, simMaxLon = -122.52, -122.36
+)
+
+const (
+	simDrivers  = 300
+	simRequests = 60
+	simRadius   = 5000
+)
+
+func simPoint(rng *rand.Rand) (float64, float64) {
+	return simMinLat + rng.Float64()*(simMaxLat-simMinLat), simMinLon + rng.Float64()*(simMaxLon-simMinLon)
+}
+
+type batchSimulation struct {
+	batch *batchDispatcher
+	trips []messaging.TripEventData
+	// drivers holds the latitude and longitude of every driver
+	drivers map[string][2]float64
+}
+
+// simulateBatch spreads available drivers and requested trips over San Francisco.
+func simulateBatch(rng *rand.Rand) *batchSimulation {
+	index := geoindex.New(geoindex.DefaultCellSizeDegrees)
+	drivers := make(map[string][2]float64, simDrivers)
+	for i := 0; i < simDrivers; i++ {
+		id := fmt.Sprintf("driver-%d", i)
+		lat, lon := simPoint(rng)
+		index.Upsert(id, "sedan", lat, lon)
+		drivers[id] = [2]float64{lat, lon}
+	}
+
+	trips := make([]messaging.TripEventData, simRequests)
+	for i := range trips {
+		lat, lon := simPoint(rng)
+		trips[i] = messaging.TripEventData{Trip: &pbt.Trip{
+			Id:           fmt.Sprintf("trip-%d", i),
+			Pickup:       &pbt.Coordinate{Latitude: lat, Longitude: lon},
+			SelectedFare: &pbt.RideFare{PackageSlug: "sedan"},
+		}}
+	}
+
+	return &batchSimulation{
+		batch:   NewBatchDispatcher(nil, NewService(index, nil, nil), nil, DefaultBatchConfig(), simRadius),
+		trips:   trips,
+		drivers: drivers,
+	}
+}
+
+// matchGreedy is how trips were matched before batching: in order, to the closest free driver.
+func (sim *batchSimulation) matchGreedy() []string {
+	taken := make(map[string]bool)
+	assignment := make([]string, len(sim.trips))
+
+	for i, event := range sim.trips {
+		closest := sim.batch.service.FindAvailableDrivers("sedan", tripPickup(event.Trip), simRadius, 1, func(id string) bool { return taken[id] })
+		if len(closest) > 0 {
+			taken[closest[0].ID] = true
+			assignment[i] = closest[0].ID
+		}
+	}
+
+	return assignment
+}
+
+// pickupETAs sums the pickup ETAs of the matched trips.
+func (sim *batchSimulation) pickupETAs(assignment []string) (int, time.Duration) {
+	matched, total := 0, time.Duration(0)
+	for i, driverID := range assignment {
+		if driverID == "" {
+			continue
+		}
+
+		pickup := tripPickup(sim.trips[i].Trip)
+		position := sim.drivers[driverID]
+		distance := geoindex.Distance(pickup.Latitude, pickup.Longitude, position[0], position[1])
+
+		matched++
+		total += matching.PickupETA(distance, sim.batch.config.AverageSpeedKmh)
+	}
+
+	return matched, total
+}
+
+func TestBatchMatchingCutsPickupETAs(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	var greedyMatched, batchMatched int
+	var greedyETA, batchETA time.Duration
+	for i := 0; i < 20; i++ {
+		sim := simulateBatch(rng)
+
+		matched, eta := sim.pickupETAs(sim.matchGreedy())
+		greedyMatched, greedyETA = greedyMatched+matched, greedyETA+eta
+
+		matched, eta = sim.pickupETAs(sim.batch.match(sim.trips))
+		batchMatched, batchETA = batchMatched+matched, batchETA+eta
+	}
+
+	if batchMatched < greedyMatched {
+		t.Fatalf("batch matching matched %d trips, greedy matching %d", batchMatched, greedyMatched)
+	}
+	if batchETA >= greedyETA {
+		t.Fatalf("batch matching took %s of pickups, greedy matching %s", batchETA, greedyETA)
+	}
+
+	t.Logf("pickup ETAs: greedy %s, batch %s", greedyETA/time.Duration(greedyMatched), batchETA/time.Duration(batchMatched))
+}
+
+// BenchmarkBatchMatch measures matching a batch, and reports the average pickup ETA of both ways of matching:
+//
+//	go test -bench BatchMatch ./services/driver-service
+func BenchmarkBatchMatch(b *testing.B) {
+	sim := simulateBatch(rand.New(rand.NewPCG(1, 2)))
+
+	b.ResetTimer()
+	var assignment []string
+	for i := 0; i < b.N; i++ {
+		assignment = sim.batch.match(sim.trips)
+	}
+	b.StopTimer()
+
+	matched, eta := sim.pickupETAs(assignment)
+	b.ReportMetric((eta / time.Duration(matched)).Seconds(), "eta-s/trip")
+
+	matched, eta = sim.pickupETAs(sim.matchGreedy())
+	b.ReportMetric((eta / time.Duration(matched)).Seconds(), "greedy-eta-s/trip")
+}
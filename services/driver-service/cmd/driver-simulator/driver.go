@@ -0,0 +1,387 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+
+	"ride-sharing/services/driver-service/internal/simulation"
+	"ride-sharing/shared/auth"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+	pb "ride-sharing/shared/proto/driver"
+	pbt "ride-sharing/shared/proto/trip"
+
+	"github.com/gorilla/websocket"
+	"github.com/mmcloughlin/geohash"
+)
+
+const reconnectDelay = 5 * time.Second
+
+// phase is what a virtual driver is busy with
+type phase int
+
+const (
+	phaseRoaming phase = iota
+	phaseToPickup
+	phaseBoarding
+	phaseOnTrip
+)
+
+// offer is a trip request the driver answers once decideAt is reached
+type offer struct {
+	trip     *pbt.Trip
+	decision string
+	decideAt time.Time
+}
+
+// virtualDriver drives around and answers offers like a driver using the app would.
+// All its state is owned by the goroutine running the session.
+type virtualDriver struct {
+	id          string
+	packageSlug string
+	cfg         *config
+	stats       *stats
+	rng         *rand.Rand
+	// speed is the driver's own speed in meters per second
+	speed float64
+
+	conn     *websocket.Conn
+	driver   *pb.Driver
+	phase    phase
+	path     *simulation.Path
+	trip     *pbt.Trip
+	pending  *offer
+	boardAt  time.Time
+	lastMove time.Time
+}
+
+func newVirtualDriver(id, packageSlug string, cfg *config, stats *stats, rng *rand.Rand) *virtualDriver {
+	route := simulation.PredefinedRoute(rng)
+
+	return &virtualDriver{
+		id:          id,
+		packageSlug: packageSlug,
+		cfg:         cfg,
+		stats:       stats,
+		rng:         rng,
+		speed:       cfg.speedKmh * (0.8 + 0.4*rng.Float64()) / 3.6,
+		path:        simulation.NewPath(route[0], route[1:]),
+	}
+}
+
+// Run keeps the driver connected to the gateway until ctx is done.
+func (d *virtualDriver) Run(ctx context.Context) {
+	// Spread the connections so the drivers don't all report at the same time
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(time.Duration(d.rng.Int64N(int64(d.cfg.updateInterval)))):
+	}
+
+	for {
+		if err := d.session(ctx); err != nil {
+			log.Printf("Driver %s disconnected: %v", d.id, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (d *virtualDriver) session(ctx context.Context) error {
+	endpoint := fmt.Sprintf("%s/ws/drivers?userID=%s&packageSlug=%s",
+		d.cfg.gatewayURL, url.QueryEscape(d.id), url.QueryEscape(d.packageSlug))
+
+	header := http.Header{}
+	if d.cfg.jwtSecret != "" {
+		token, err := auth.SignHS256(&auth.Identity{UserID: d.id, Role: auth.RoleDriver}, []byte(d.cfg.jwtSecret), time.Hour)
+		if err != nil {
+			return err
+		}
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, endpoint, header)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	d.conn = conn
+	d.stats.online.Add(1)
+	defer d.stats.online.Add(-1)
+
+	// The reader is the only one touching the connection besides this goroutine, which does all the writes
+	incoming := make(chan contracts.WSMessage)
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			var msg struct {
+				Type string          `json:"type"`
+				Data json.RawMessage `json:"data"`
+			}
+			if err := conn.ReadJSON(&msg); err != nil {
+				readErr <- err
+				return
+			}
+
+			select {
+			case incoming <- contracts.WSMessage{Type: msg.Type, Data: msg.Data}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(d.cfg.updateInterval)
+	defer ticker.Stop()
+
+	d.lastMove = time.Now()
+	if err := d.sendLocation(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return nil
+		case err := <-readErr:
+			return err
+		case msg := <-incoming:
+			if err := d.handleMessage(msg); err != nil {
+				return err
+			}
+		case now := <-ticker.C:
+			if err := d.tick(now); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (d *virtualDriver) handleMessage(msg contracts.WSMessage) error {
+	data, _ := msg.Data.(json.RawMessage)
+
+	switch msg.Type {
+	case contracts.DriverCmdRegister:
+		var driver pb.Driver
+		if err := json.Unmarshal(data, &driver); err != nil {
+			return fmt.Errorf("invalid registration: %w", err)
+		}
+		d.driver = &driver
+
+	case contracts.DriverCmdTripRequest:
+		var payload messaging.TripEventData
+		if err := json.Unmarshal(data, &payload); err != nil || payload.Trip == nil {
+			log.Printf("Driver %s got an invalid trip request: %v", d.id, err)
+			return nil
+		}
+		d.stats.offers.Add(1)
+
+		if d.phase != phaseRoaming || d.pending != nil {
+			d.stats.ignored.Add(1)
+			return nil
+		}
+
+		d.pending = &offer{
+			trip:     payload.Trip,
+			decision: d.decide(),
+			decideAt: time.Now().Add(time.Duration(d.rng.Int64N(int64(d.cfg.maxThinkTime) + 1))),
+		}
+
+	case contracts.TripEventCancelled:
+		var payload messaging.TripCancelledData
+		if err := json.Unmarshal(data, &payload); err != nil || payload.Trip == nil {
+			return nil
+		}
+
+		if d.pending != nil && d.pending.trip.Id == payload.Trip.Id {
+			d.pending = nil
+		}
+
+		if d.trip != nil && d.trip.Id == payload.Trip.Id {
+			d.stats.cancelled.Add(1)
+			d.roam()
+		}
+	}
+
+	return nil
+}
+
+// decide picks the answer to an offer according to the configured probabilities, empty to let it expire.
+func (d *virtualDriver) decide() string {
+	switch r := d.rng.Float64(); {
+	case r < d.cfg.acceptRate:
+		return contracts.DriverCmdTripAccept
+	case r < d.cfg.acceptRate+d.cfg.declineRate:
+		return contracts.DriverCmdTripDecline
+	default:
+		return ""
+	}
+}
+
+func (d *virtualDriver) tick(now time.Time) error {
+	if d.pending != nil && !now.Before(d.pending.decideAt) {
+		if err := d.answer(d.pending); err != nil {
+			return err
+		}
+		d.pending = nil
+	}
+
+	elapsed := now.Sub(d.lastMove)
+	d.lastMove = now
+
+	switch d.phase {
+	case phaseBoarding:
+		if now.Before(d.boardAt) {
+			break
+		}
+
+		if err := d.sendTripCommand(contracts.DriverCmdTripStart); err != nil {
+			return err
+		}
+		d.phase = phaseOnTrip
+		d.path = simulation.NewPath(d.path.Position(), tripRoute(d.trip))
+
+	default:
+		d.path.Advance(d.speed * elapsed.Seconds())
+		if d.path.Done() {
+			if err := d.arrived(now); err != nil {
+				return err
+			}
+		}
+	}
+
+	return d.sendLocation()
+}
+
+func (d *virtualDriver) answer(o *offer) error {
+	if o.decision == "" {
+		d.stats.ignored.Add(1)
+		return nil
+	}
+
+	if err := d.send(o.decision, messaging.DriverTripResponseData{
+		Driver:  d.driver,
+		TripID:  o.trip.Id,
+		RiderID: o.trip.UserID,
+	}); err != nil {
+		return err
+	}
+
+	if o.decision == contracts.DriverCmdTripDecline {
+		d.stats.declined.Add(1)
+		return nil
+	}
+
+	d.stats.accepted.Add(1)
+	d.trip = o.trip
+	d.phase = phaseToPickup
+
+	// An accepted offer may have expired meanwhile, the trip is then cancelled or goes to someone else
+	// and the driver simply keeps driving to the pickup until it notices
+	pickup := tripPickup(o.trip)
+	d.path = simulation.NewPath(d.path.Position(), []simulation.Point{pickup})
+
+	return nil
+}
+
+// arrived is called when the driver reached the end of its path.
+func (d *virtualDriver) arrived(now time.Time) error {
+	switch d.phase {
+	case phaseToPickup:
+		if err := d.sendTripCommand(contracts.DriverCmdTripArrive); err != nil {
+			return err
+		}
+		d.phase = phaseBoarding
+		d.boardAt = now.Add(d.cfg.boardingTime)
+
+	case phaseOnTrip:
+		if err := d.sendTripCommand(contracts.DriverCmdTripEnd); err != nil {
+			return err
+		}
+		d.stats.completed.Add(1)
+		d.roam()
+
+	default:
+		d.roam()
+	}
+
+	return nil
+}
+
+// roam sends the driver on a new route, either along a predefined one or wandering around.
+func (d *virtualDriver) roam() {
+	d.phase = phaseRoaming
+	d.trip = nil
+
+	position := d.path.Position()
+	if d.rng.IntN(2) == 0 {
+		d.path = simulation.NewPath(position, simulation.PredefinedRoute(d.rng))
+		return
+	}
+
+	d.path = simulation.NewPath(position, simulation.RandomRoute(d.rng, position, 5, 800))
+}
+
+func (d *virtualDriver) sendTripCommand(messageType string) error {
+	return d.send(messageType, messaging.DriverTripResponseData{
+		Driver:  d.driver,
+		TripID:  d.trip.Id,
+		RiderID: d.trip.UserID,
+	})
+}
+
+func (d *virtualDriver) sendLocation() error {
+	position := d.path.Position()
+
+	var data contracts.WSDriverLocationData
+	data.Location.Latitude = position.Latitude
+	data.Location.Longitude = position.Longitude
+	data.Geohash = geohash.Encode(position.Latitude, position.Longitude)
+
+	return d.send(contracts.DriverCmdLocation, data)
+}
+
+func (d *virtualDriver) send(messageType string, data any) error {
+	return d.conn.WriteJSON(contracts.WSMessage{Type: messageType, Data: data})
+}
+
+func tripPickup(trip *pbt.Trip) simulation.Point {
+	if pickup := trip.GetPickup(); pickup != nil {
+		return simulation.Point{Latitude: pickup.Latitude, Longitude: pickup.Longitude}
+	}
+
+	route := tripRoute(trip)
+	if len(route) == 0 {
+		return simulation.Point{}
+	}
+
+	return route[0]
+}
+
+// tripRoute returns the points of the trip's route.
+// Route coordinates hold OSRM's [longitude, latitude] pairs in (latitude, longitude).
+func tripRoute(trip *pbt.Trip) []simulation.Point {
+	geometry := trip.GetRoute().GetGeometry()
+	if len(geometry) == 0 {
+		return nil
+	}
+
+	points := make([]simulation.Point, len(geometry[0].Coordinates))
+	for i, coord := range geometry[0].Coordinates {
+		points[i] = simulation.Point{Latitude: coord.Longitude, Longitude: coord.Latitude}
+	}
+
+	return points
+}
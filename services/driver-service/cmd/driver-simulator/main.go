@@ -0,0 +1,157 @@
+// Command driver-simulator connects virtual drivers to the API gateway as the driver app would.
+// They roam along the predefined and random routes, report their location, answer the trips they are offered
+// according to the configured probabilities and drive the accepted ones to completion.
+//
+// Virtual drivers need approved profiles in the driver registry, generate them once and seed driver service with them:
+//
+//	go run ./services/driver-service/cmd/driver-simulator -drivers 50 -profiles sim-drivers.json
+//	DRIVERS_FILE=sim-drivers.json ...
+//	go run ./services/driver-service/cmd/driver-simulator -drivers 50 -gateway ws://localhost:8081
+//
+// When the gateway verifies HS256 tokens, pass its secret with -jwt-secret for drivers to sign their own.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"ride-sharing/services/driver-service/internal/registry"
+)
+
+type config struct {
+	gatewayURL     string
+	speedKmh       float64
+	updateInterval time.Duration
+	acceptRate     float64
+	declineRate    float64
+	maxThinkTime   time.Duration
+	boardingTime   time.Duration
+	// jwtSecret signs the tokens drivers authenticate with, when the gateway verifies HS256 tokens
+	jwtSecret string
+}
+
+type stats struct {
+	online    atomic.Int64
+	offers    atomic.Int64
+	accepted  atomic.Int64
+	declined  atomic.Int64
+	ignored   atomic.Int64
+	completed atomic.Int64
+	cancelled atomic.Int64
+}
+
+func main() {
+	drivers := flag.Int("drivers", 10, "number of virtual drivers")
+	packages := flag.String("packages", "sedan,suv,van,luxury", "comma separated packages the drivers are spread over")
+	idPrefix := flag.String("id-prefix", "sim-driver", "prefix of the virtual driver ids")
+	profilesPath := flag.String("profiles", "", "write the profiles of the virtual drivers to this JSON file and exit")
+	seed := flag.Uint64("seed", uint64(time.Now().UnixNano()), "random seed")
+	statsInterval := flag.Duration("stats", 10*time.Second, "how often statistics are logged")
+
+	cfg := &config{}
+	flag.StringVar(&cfg.gatewayURL, "gateway", "ws://localhost:8081", "API gateway WebSocket base URL")
+	flag.Float64Var(&cfg.speedKmh, "speed", 30, "average driving speed in km/h, each driver deviates from it by up to 20%")
+	flag.DurationVar(&cfg.updateInterval, "update", 2*time.Second, "how often drivers report their location")
+	flag.Float64Var(&cfg.acceptRate, "accept", 0.8, "probability of accepting an offered trip")
+	flag.Float64Var(&cfg.declineRate, "decline", 0.1, "probability of declining an offered trip, the remaining offers are left to expire")
+	flag.DurationVar(&cfg.maxThinkTime, "think", 3*time.Second, "maximum time drivers take to answer an offer")
+	flag.DurationVar(&cfg.boardingTime, "boarding", 5*time.Second, "time between arriving at the pickup and starting the trip")
+	flag.StringVar(&cfg.jwtSecret, "jwt-secret", os.Getenv("AUTH_JWT_SECRET"), "HS256 secret signing the tokens of the drivers, leave empty when the gateway has authentication disabled")
+	flag.Parse()
+
+	if cfg.acceptRate < 0 || cfg.declineRate < 0 || cfg.acceptRate+cfg.declineRate > 1 {
+		log.Fatalf("accept and decline probabilities must be positive and add up to at most 1")
+	}
+
+	slugs := strings.Split(*packages, ",")
+	ids := make([]string, *drivers)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%s-%04d", *idPrefix, i+1)
+	}
+
+	if *profilesPath != "" {
+		if err := writeProfiles(*profilesPath, ids, slugs); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Wrote %d driver profiles to %s", len(ids), *profilesPath)
+		return
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	st := &stats{}
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		driver := newVirtualDriver(id, strings.TrimSpace(slugs[i%len(slugs)]), cfg, st, rand.New(rand.NewPCG(*seed, uint64(i))))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			driver.Run(ctx)
+		}()
+	}
+
+	go logStats(ctx, st, *statsInterval)
+
+	log.Printf("Started %d virtual drivers against %s", len(ids), cfg.gatewayURL)
+	wg.Wait()
+}
+
+func logStats(ctx context.Context, st *stats, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			log.Printf("online=%d offers=%d accepted=%d declined=%d ignored=%d completed=%d cancelled=%d",
+				st.online.Load(), st.offers.Load(), st.accepted.Load(), st.declined.Load(),
+				st.ignored.Load(), st.completed.Load(), st.cancelled.Load())
+		}
+	}
+}
+
+// writeProfiles writes approved profiles for the virtual drivers, each with a vehicle for its package.
+func writeProfiles(path string, ids, slugs []string) error {
+	profiles := make([]*registry.Profile, len(ids))
+	for i, id := range ids {
+		slug := strings.TrimSpace(slugs[i%len(slugs)])
+		profiles[i] = &registry.Profile{
+			ID:       id,
+			Name:     fmt.Sprintf("Simulated Driver %d", i+1),
+			PhotoURL: fmt.Sprintf("https://randomuser.me/api/portraits/lego/%d.jpg", i%10),
+			Phone:    fmt.Sprintf("+1415555%04d", i%10000),
+			License:  registry.License{Number: fmt.Sprintf("SIM%06d", i+1), ExpiresAt: time.Now().AddDate(5, 0, 0)},
+			Vehicles: []registry.Vehicle{{
+				Plate:        fmt.Sprintf("SIM%04d", i+1),
+				Make:         "Toyota",
+				Model:        "Prius",
+				Colour:       "White",
+				Seats:        4,
+				PackageSlugs: []string{slug},
+			}},
+			Status: registry.StatusApproved,
+		}
+	}
+
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"ride-sharing/services/driver-service/internal/geoindex"
+	"ride-sharing/services/driver-service/internal/stats"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+	"slices"
+	"sync"
+	"time"
+
+	pb "ride-sharing/shared/proto/driver"
+)
+
+// DispatchConfig controls how drivers are offered a trip
+type DispatchConfig struct {
+	// RadiusMeters is how far from the pickup drivers are searched
+	RadiusMeters float64
+	// CandidatesPerRound is how many of the closest drivers are lined up for each round of offers
+	CandidatesPerRound int
+	// OfferTimeout is how long a driver has to answer before the trip is offered to the next one
+	OfferTimeout time.Duration
+	// MaxRounds is how many times the candidate list is rebuilt before giving up
+	MaxRounds int
+	// MaxDuration is how long a trip is dispatched before giving up
+	MaxDuration time.Duration
+	// RoundInterval is how long to wait for drivers to free up when a round found no candidate
+	RoundInterval time.Duration
+	// QualityWeight is how much the score of drivers weighs against their distance, 0 orders candidates by distance only.
+	// A driver's distance is stretched by QualityWeight times how far its score is from perfect,
+	// e.g. with 1 a driver scoring 0.5 ranks as if it were 1.5 times further away.
+	QualityWeight float64
+}
+
+func DefaultDispatchConfig() *DispatchConfig {
+	return &DispatchConfig{
+		RadiusMeters:       5000,
+		CandidatesPerRound: 5,
+		OfferTimeout:       15 * time.Second,
+		MaxRounds:          3,
+		MaxDuration:        2 * time.Minute,
+		RoundInterval:      10 * time.Second,
+		QualityWeight:      0,
+	}
+}
+
+// dispatcher offers each trip to one driver at a time, closest first,
+// moving on to the next candidate when a driver declines or does not answer in time.
+type dispatcher struct {
+	ctx       context.Context
+	rabbitmq  *messaging.Rabbitmq
+	service   *Service
+	publisher *driverEventPublisher
+	config    *DispatchConfig
+	stats     *stats.Tracker
+
+	mu    sync.Mutex
+	trips map[string]*tripDispatch
+}
+
+type tripDispatch struct {
+	event  messaging.TripEventData
+	pickup *pb.Location
+	// candidates are the drivers of the current round still to be offered the trip, best ranked first
+	candidates []string
+	// tried holds the drivers that declined the trip or let the offer expire
+	tried map[string]bool
+	// current is the driver currently deciding, empty between offers
+	current   string
+	round     int
+	startedAt time.Time
+	timer     *time.Timer
+}
+
+// NewDispatcher uses ctx for the offers made when timers fire, outside of any message handling.
+func NewDispatcher(ctx context.Context, rabbitmq *messaging.Rabbitmq, service *Service, publisher *driverEventPublisher, config *DispatchConfig, driverStats *stats.Tracker) *dispatcher {
+	return &dispatcher{
+		ctx:       ctx,
+		rabbitmq:  rabbitmq,
+		service:   service,
+		publisher: publisher,
+		config:    config,
+		stats:     driverStats,
+		trips:     make(map[string]*tripDispatch),
+	}
+}
+
+// Dispatch starts looking for a driver for the trip, redeliveries of a trip already dispatched are ignored.
+func (d *dispatcher) Dispatch(ctx context.Context, event messaging.TripEventData) error {
+	return d.DispatchTo(ctx, event, "")
+}
+
+// DispatchTo dispatches the trip offering it to the preferred driver first, e.g. the one picked by batch matching.
+// The other candidates follow closest first, as with Dispatch.
+func (d *dispatcher) DispatchTo(ctx context.Context, event messaging.TripEventData, preferredDriverID string) error {
+	trip := event.Trip
+	pickup := tripPickup(trip)
+	if pickup == nil {
+		log.Printf("Trip %s has no pickup location", trip.Id)
+		return d.publishNoDriversFound(ctx, event)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.trips[trip.Id]; ok {
+		return nil
+	}
+
+	dispatch := &tripDispatch{
+		event:     event,
+		pickup:    pickup,
+		tried:     make(map[string]bool),
+		startedAt: time.Now(),
+	}
+	d.trips[trip.Id] = dispatch
+
+	if preferredDriverID != "" {
+		d.startRoundLocked(dispatch)
+		dispatch.candidates = slices.DeleteFunc(dispatch.candidates, func(id string) bool {
+			return id == preferredDriverID
+		})
+		dispatch.candidates = append([]string{preferredDriverID}, dispatch.candidates...)
+	}
+
+	return d.offerNextLocked(ctx, dispatch)
+}
+
+// Declined moves on to the next candidate when the driver deciding turns the trip down.
+func (d *dispatcher) Declined(ctx context.Context, tripID, driverID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dispatch, ok := d.trips[tripID]
+	if !ok || dispatch.current != driverID {
+		return nil
+	}
+
+	log.Printf("Driver %s declined trip %s", driverID, tripID)
+	recordStat(d.stats, driverID, stats.Declined)
+	dispatch.stopTimer()
+	dispatch.tried[driverID] = true
+	dispatch.current = ""
+
+	return d.offerNextLocked(ctx, dispatch)
+}
+
+// Finish stops dispatching the trip, once a driver accepted it or it was cancelled.
+func (d *dispatcher) Finish(tripID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if dispatch, ok := d.trips[tripID]; ok {
+		dispatch.stopTimer()
+		delete(d.trips, tripID)
+	}
+}
+
+// expire is called by the offer timer when the driver did not answer in time.
+func (d *dispatcher) expire(tripID, driverID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dispatch, ok := d.trips[tripID]
+	if !ok || dispatch.current != driverID {
+		return
+	}
+
+	log.Printf("Offer of trip %s to driver %s expired", tripID, driverID)
+	recordStat(d.stats, driverID, stats.TimedOut)
+	dispatch.tried[driverID] = true
+	dispatch.current = ""
+
+	if driver := d.service.DeclineOffer(driverID, tripID); driver != nil {
+		if err := d.publisher.PublishAvailability(d.ctx, driver, true); err != nil {
+			log.Printf("Failed to publish availability of driver %s: %v", driverID, err)
+		}
+	}
+
+	if err := d.offerNextLocked(d.ctx, dispatch); err != nil {
+		log.Printf("Failed to dispatch trip %s: %v", tripID, err)
+	}
+}
+
+// nextRound is called by the round timer once drivers had time to free up.
+func (d *dispatcher) nextRound(tripID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dispatch, ok := d.trips[tripID]
+	if !ok || dispatch.current != "" {
+		return
+	}
+
+	if err := d.offerNextLocked(d.ctx, dispatch); err != nil {
+		log.Printf("Failed to dispatch trip %s: %v", tripID, err)
+	}
+}
+
+// offerNextLocked must be called with the lock held.
+// It offers the trip to the next candidate, starting a new round when the candidates ran out.
+func (d *dispatcher) offerNextLocked(ctx context.Context, dispatch *tripDispatch) error {
+	trip := dispatch.event.Trip
+
+	for {
+		if len(dispatch.candidates) == 0 {
+			if dispatch.round >= d.config.MaxRounds || time.Since(dispatch.startedAt) >= d.config.MaxDuration {
+				log.Printf("No driver took trip %s after %d rounds", trip.Id, dispatch.round)
+				delete(d.trips, trip.Id)
+				return d.publishNoDriversFound(ctx, dispatch.event)
+			}
+
+			d.startRoundLocked(dispatch)
+			if len(dispatch.candidates) == 0 {
+				// Wait for drivers to come online or finish their trips
+				dispatch.timer = time.AfterFunc(d.config.RoundInterval, func() {
+					d.nextRound(trip.Id)
+				})
+				return nil
+			}
+		}
+
+		driverID := dispatch.candidates[0]
+		dispatch.candidates = dispatch.candidates[1:]
+
+		driver, err := d.service.OfferTrip(driverID, trip.Id)
+		if errors.Is(err, ErrInvalidDriverTransition) || errors.Is(err, ErrDriverNotFound) {
+			// The driver was taken or went offline since the round started
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if driver != nil {
+			if err := d.publisher.PublishAvailability(ctx, driver, false); err != nil {
+				log.Printf("Failed to publish availability of driver %s: %v", driverID, err)
+			}
+		}
+
+		if err := d.publishTripRequest(ctx, driverID, dispatch.event); err != nil {
+			// Give the driver back so a redelivery can offer the trip again
+			d.service.DeclineOffer(driverID, trip.Id)
+			delete(d.trips, trip.Id)
+			return err
+		}
+
+		log.Printf("Offered trip %s to driver %s", trip.Id, driverID)
+		recordStat(d.stats, driverID, stats.Offered)
+		dispatch.current = driverID
+		dispatch.timer = time.AfterFunc(d.config.OfferTimeout, func() {
+			d.expire(trip.Id, driverID)
+		})
+
+		return nil
+	}
+}
+
+// startRoundLocked must be called with the lock held.
+// It lines up the closest drivers that did not turn the trip down yet, ranked by their score when QualityWeight is set.
+func (d *dispatcher) startRoundLocked(dispatch *tripDispatch) {
+	trip := dispatch.event.Trip
+
+	limit := d.config.CandidatesPerRound
+	if d.config.QualityWeight > 0 {
+		// Look a bit further so a close driver with a poor score can make room for a better one
+		limit *= 2
+	}
+
+	results := d.service.FindAvailableDrivers(trip.SelectedFare.PackageSlug, dispatch.pickup, d.config.RadiusMeters, limit, func(driverID string) bool {
+		return dispatch.tried[driverID]
+	})
+
+	if d.config.QualityWeight > 0 {
+		cost := make(map[string]float64, len(results))
+		for _, result := range results {
+			cost[result.ID] = result.DistanceMeters * (1 + d.config.QualityWeight*(1-d.stats.Score(result.ID)))
+		}
+		slices.SortStableFunc(results, func(a, b geoindex.Result) int {
+			return cmp.Compare(cost[a.ID], cost[b.ID])
+		})
+		results = results[:min(len(results), d.config.CandidatesPerRound)]
+	}
+
+	dispatch.round++
+	for _, result := range results {
+		dispatch.candidates = append(dispatch.candidates, result.ID)
+	}
+
+	log.Printf("Round %d of trip %s found %d candidates", dispatch.round, trip.Id, len(dispatch.candidates))
+}
+
+func (d *dispatcher) publishTripRequest(ctx context.Context, driverID string, event messaging.TripEventData) error {
+	marshalledEvent, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// Notify the driver about a potential trip
+	if err := d.rabbitmq.PublishMessage(ctx, contracts.DriverCmdTripRequest, contracts.AmqpMessage{
+		OwnerID: driverID,
+		Data:    marshalledEvent,
+	}); err != nil {
+		log.Printf("Failed to pusblish message to exchange: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (d *dispatcher) publishNoDriversFound(ctx context.Context, event messaging.TripEventData) error {
+	marshalledEvent, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// Notify the rider that no drivers are available, and trip service that the trip expired
+	if err := d.rabbitmq.PublishMessage(ctx, contracts.TripEventNoDriversFound, contracts.AmqpMessage{
+		OwnerID: event.Trip.UserID,
+		Data:    marshalledEvent,
+	}); err != nil {
+		log.Printf("Failed to pusblish message to exchange: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (t *tripDispatch) stopTimer() {
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+}
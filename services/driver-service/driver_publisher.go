@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	pb "ride-sharing/shared/proto/driver"
+)
+
+// driverEventPublisher lets the other services follow changes of the driver pool.
+type driverEventPublisher struct {
+	rabbitmq *messaging.Rabbitmq
+}
+
+func NewDriverEventPublisher(rabbitmq *messaging.Rabbitmq) *driverEventPublisher {
+	return &driverEventPublisher{
+		rabbitmq: rabbitmq,
+	}
+}
+
+// PublishAvailability announces that the driver started or stopped being available for trips.
+func (p *driverEventPublisher) PublishAvailability(ctx context.Context, driver *pb.Driver, available bool) error {
+	payload, err := json.Marshal(messaging.DriverAvailabilityData{
+		DriverID:    driver.Id,
+		PackageSlug: driver.PackageSlug,
+		Geohash:     driver.Geohash,
+		Available:   available,
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.rabbitmq.PublishMessage(ctx, contracts.DriverEventAvailability, contracts.AmqpMessage{
+		OwnerID: driver.Id,
+		Data:    payload,
+	})
+}
+
+// PublishOfferAccepted tells trip service to assign the driver, who accepted the trip in time.
+func (p *driverEventPublisher) PublishOfferAccepted(ctx context.Context, driver *pb.Driver, tripID string) error {
+	payload, err := json.Marshal(messaging.DriverTripResponseData{
+		Driver: driver,
+		TripID: tripID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.rabbitmq.PublishMessage(ctx, contracts.DriverEventOfferAccepted, contracts.AmqpMessage{
+		OwnerID: driver.Id,
+		Data:    payload,
+	})
+}
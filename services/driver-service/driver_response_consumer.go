@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"ride-sharing/services/driver-service/internal/stats"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+	"ride-sharing/shared/retry"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// driverResponseConsumer follows the answers of drivers to the trips they were offered.
+type driverResponseConsumer struct {
+	rabbitmq   *messaging.Rabbitmq
+	service    *Service
+	publisher  *driverEventPublisher
+	dispatcher *dispatcher
+	stats      *stats.Tracker
+}
+
+func NewDriverResponseConsumer(rabbitmq *messaging.Rabbitmq, service *Service, publisher *driverEventPublisher, dispatcher *dispatcher, driverStats *stats.Tracker) *driverResponseConsumer {
+	return &driverResponseConsumer{
+		rabbitmq:   rabbitmq,
+		service:    service,
+		publisher:  publisher,
+		dispatcher: dispatcher,
+		stats:      driverStats,
+	}
+}
+
+func (c *driverResponseConsumer) Listen() error {
+	return c.rabbitmq.ConsumeMessages(messaging.DriverOfferResponseQueue, func(ctx context.Context, msg amqp.Delivery) error {
+		var message contracts.AmqpMessage
+		if err := json.Unmarshal(msg.Body, &message); err != nil {
+			log.Printf("Failed to unmarshal message: %v", err)
+			return err
+		}
+
+		var payload messaging.DriverTripResponseData
+		if err := json.Unmarshal(message.Data, &payload); err != nil {
+			log.Printf("Failed to unmarshal message: %v", err)
+			return err
+		}
+
+		// The gateway sets the owner to the connected driver, unlike the payload it can be trusted
+		driverID := message.OwnerID
+
+		switch msg.RoutingKey {
+		case contracts.DriverCmdTripAccept:
+			// Only acceptances of offers still open count, trip service assigns the driver once told so
+			// and confirms with trip.event.driver_assigned
+			driver, err := c.service.AcceptOffer(driverID, payload.TripID)
+			if err != nil {
+				log.Printf("Ignoring trip acceptance: %v", err)
+				return nil
+			}
+			recordStat(c.stats, driverID, stats.Accepted)
+			c.dispatcher.Finish(payload.TripID)
+
+			// The offer is closed now, a redelivered acceptance would be ignored so publishing is retried here
+			if err := retry.WithBackoff(ctx, retry.DefaultConfig(), func() error {
+				return c.publisher.PublishOfferAccepted(ctx, driver, payload.TripID)
+			}); err != nil {
+				log.Printf("Failed to publish acceptance of trip %s by driver %s: %v", payload.TripID, driverID, err)
+			}
+		case contracts.DriverCmdTripDecline:
+			if driver := c.service.DeclineOffer(driverID, payload.TripID); driver != nil {
+				if err := c.publisher.PublishAvailability(ctx, driver, true); err != nil {
+					log.Printf("Failed to publish availability of driver %s: %v", driverID, err)
+				}
+			}
+			return c.dispatcher.Declined(ctx, payload.TripID, driverID)
+		default:
+			log.Printf("unknown driver response: %s", msg.RoutingKey)
+		}
+
+		return nil
+	})
+}
@@ -2,7 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
+	"log"
+	"ride-sharing/services/driver-service/internal/registry"
+	"ride-sharing/services/driver-service/internal/stats"
+	"ride-sharing/shared/auth"
 	pb "ride-sharing/shared/proto/driver"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -10,31 +16,54 @@ import (
 )
 
 type grpcHandler struct {
-	Service *Service
+	Service   *Service
+	publisher *driverEventPublisher
+	nearby    *NearbyConfig
+	stats     *stats.Tracker
 
 	pb.UnimplementedDriverServiceServer
 }
 
-func NewGrpcHandler(server *grpc.Server, service *Service) {
+func NewGrpcHandler(server *grpc.Server, service *Service, publisher *driverEventPublisher, nearby *NearbyConfig, driverStats *stats.Tracker) {
 	handler := &grpcHandler{
-		Service: service,
+		Service:   service,
+		publisher: publisher,
+		nearby:    nearby,
+		stats:     driverStats,
 	}
 	pb.RegisterDriverServiceServer(server, handler)
 }
 
 func (h *grpcHandler) RegisterDriver(ctx context.Context, req *pb.RegisterDriverRequest) (*pb.RegisterDriverResponse, error) {
-	driver, err := h.Service.RegisterDriver(req.GetDriverID(), req.GetPackageSlug())
+	if err := auth.CheckUser(ctx, req.GetDriverID()); err != nil {
+		return nil, err
+	}
+
+	driver, err := h.Service.RegisterDriver(ctx, req.GetDriverID(), req.GetPackageSlug())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to register driver")
+		return nil, status.Errorf(registrationErrorCode(err), "failed to register driver: %v", err)
+	}
+
+	// A driver reconnecting in the middle of a trip is not available
+	if err := h.publisher.PublishAvailability(ctx, driver.Driver, driver.State == DriverStateAvailable); err != nil {
+		log.Printf("Failed to publish availability of driver %s: %v", driver.Driver.Id, err)
 	}
 
 	return &pb.RegisterDriverResponse{
-		Driver: driver,
+		Driver: driver.Driver,
 	}, nil
 }
 
 func (h *grpcHandler) UnRegisterDriver(ctx context.Context, req *pb.RegisterDriverRequest) (*pb.RegisterDriverResponse, error) {
-	h.Service.UnregisterDriver(req.GetDriverID())
+	if err := auth.CheckUser(ctx, req.GetDriverID()); err != nil {
+		return nil, err
+	}
+
+	if driver := h.Service.UnregisterDriver(req.GetDriverID()); driver != nil {
+		if err := h.publisher.PublishAvailability(ctx, driver, false); err != nil {
+			log.Printf("Failed to publish availability of driver %s: %v", driver.Id, err)
+		}
+	}
 
 	return &pb.RegisterDriverResponse{
 		Driver: &pb.Driver{
@@ -42,3 +71,77 @@ func (h *grpcHandler) UnRegisterDriver(ctx context.Context, req *pb.RegisterDriv
 		},
 	}, nil
 }
+
+func (h *grpcHandler) GetDriver(ctx context.Context, req *pb.GetDriverRequest) (*pb.GetDriverResponse, error) {
+	driver, err := h.Service.GetDriver(req.GetDriverID())
+	if err != nil {
+		if errors.Is(err, ErrDriverNotFound) {
+			return nil, status.Errorf(codes.NotFound, "driver %s not found", req.GetDriverID())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get driver: %v", err)
+	}
+
+	return driver.ToProto(), nil
+}
+
+func (h *grpcHandler) WatchNearbyDrivers(req *pb.WatchNearbyDriversRequest, stream grpc.ServerStreamingServer[pb.NearbyDriversUpdate]) error {
+	watch, err := newNearbyWatch(req, h.nearby)
+	if err != nil {
+		if errors.Is(err, ErrInvalidArea) {
+			return status.Errorf(codes.InvalidArgument, "failed to watch nearby drivers: %v", err)
+		}
+		return status.Errorf(codes.Internal, "failed to watch nearby drivers: %v", err)
+	}
+
+	ticker := time.NewTicker(h.nearby.UpdateInterval)
+	defer ticker.Stop()
+
+	for {
+		if update := watch.next(h.Service); update != nil {
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *grpcHandler) GetDriverStats(ctx context.Context, req *pb.GetDriverStatsRequest) (*pb.DriverStats, error) {
+	if err := auth.CheckUser(ctx, req.GetDriverID()); err != nil {
+		return nil, err
+	}
+
+	if req.GetDriverID() == "" {
+		return nil, status.Error(codes.InvalidArgument, "driver is required")
+	}
+
+	return driverStatsToProto(h.stats.Stats(req.GetDriverID()), h.stats.Config()), nil
+}
+
+func (h *grpcHandler) ListAvailableDrivers(ctx context.Context, req *pb.ListAvailableDriversRequest) (*pb.ListAvailableDriversResponse, error) {
+	if err := auth.CheckRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	return &pb.ListAvailableDriversResponse{
+		Drivers: h.Service.ListAvailableDrivers(),
+	}, nil
+}
+
+func registrationErrorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, registry.ErrProfileNotFound):
+		return codes.NotFound
+	case errors.Is(err, registry.ErrProfileNotApproved):
+		return codes.PermissionDenied
+	case errors.Is(err, registry.ErrNoEligibleVehicle):
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,357 @@
+// Package geoindex keeps driver positions in a uniform latitude/longitude grid
+// to answer nearest-driver queries without scanning every driver.
+package geoindex
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+	"sync"
+)
+
+const earthRadiusMeters = 6371000
+
+// DefaultCellSizeDegrees gives cells of roughly 1.1km by 0.9km at San Francisco's latitude.
+const DefaultCellSizeDegrees = 0.01
+
+type cellKey struct {
+	lat, lon int
+}
+
+type item struct {
+	id          string
+	packageSlug string
+	lat, lon    float64
+	cell        cellKey
+}
+
+// Query describes a nearest neighbour search.
+type Query struct {
+	Latitude  float64
+	Longitude float64
+	// PackageSlug restricts the results to a package, empty matches every package
+	PackageSlug string
+	// Limit is the maximum number of results, zero means no limit
+	Limit int
+	// RadiusMeters bounds the search, it must be positive
+	RadiusMeters float64
+	// Exclude skips the items it returns true for, e.g. busy drivers
+	Exclude func(id string) bool
+}
+
+// Result is an item found by a query with its distance to the query point.
+type Result struct {
+	ID             string
+	DistanceMeters float64
+}
+
+// Index is safe for concurrent use.
+type Index struct {
+	cellSize float64
+
+	mu    sync.RWMutex
+	items map[string]*item
+	// cells holds the items of every non-empty cell, per package
+	cells map[string]map[cellKey]map[string]*item
+}
+
+func New(cellSizeDegrees float64) *Index {
+	if cellSizeDegrees <= 0 {
+		cellSizeDegrees = DefaultCellSizeDegrees
+	}
+
+	return &Index{
+		cellSize: cellSizeDegrees,
+		items:    make(map[string]*item),
+		cells:    make(map[string]map[cellKey]map[string]*item),
+	}
+}
+
+// Upsert adds the item or moves it to its new position.
+func (idx *Index) Upsert(id, packageSlug string, lat, lon float64) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	cell := idx.cellOf(lat, lon)
+	if existing, ok := idx.items[id]; ok {
+		if existing.cell == cell && existing.packageSlug == packageSlug {
+			existing.lat, existing.lon = lat, lon
+			return
+		}
+		idx.removeLocked(existing)
+	}
+
+	it := &item{id: id, packageSlug: packageSlug, lat: lat, lon: lon, cell: cell}
+	idx.items[id] = it
+
+	packageCells, ok := idx.cells[packageSlug]
+	if !ok {
+		packageCells = make(map[cellKey]map[string]*item)
+		idx.cells[packageSlug] = packageCells
+	}
+
+	members, ok := packageCells[cell]
+	if !ok {
+		members = make(map[string]*item)
+		packageCells[cell] = members
+	}
+	members[id] = it
+}
+
+func (idx *Index) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if existing, ok := idx.items[id]; ok {
+		idx.removeLocked(existing)
+	}
+}
+
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.items)
+}
+
+// Nearest returns the items closest to the query point, sorted by distance.
+// It visits rings of cells around the point and stops as soon as no unvisited cell can hold a closer item.
+// An empty result is nil.
+func (idx *Index) Nearest(q Query) []Result {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if q.RadiusMeters <= 0 {
+		return nil
+	}
+
+	var packageCells []map[cellKey]map[string]*item
+	if q.PackageSlug != "" {
+		if cells, ok := idx.cells[q.PackageSlug]; ok {
+			packageCells = append(packageCells, cells)
+		}
+	} else {
+		for _, cells := range idx.cells {
+			packageCells = append(packageCells, cells)
+		}
+	}
+
+	if len(packageCells) == 0 {
+		return nil
+	}
+
+	center := idx.cellOf(q.Latitude, q.Longitude)
+	metersPerDegreeLat := math.Pi / 180 * earthRadiusMeters
+	metersPerDegreeLon := metersPerDegreeLat * math.Cos(q.Latitude*math.Pi/180)
+	// How far the query point is from the closest side of its own cell
+	offset := math.Min(
+		math.Min(q.Latitude-float64(center.lat)*idx.cellSize, float64(center.lat+1)*idx.cellSize-q.Latitude)*metersPerDegreeLat,
+		math.Min(q.Longitude-float64(center.lon)*idx.cellSize, float64(center.lon+1)*idx.cellSize-q.Longitude)*metersPerDegreeLon,
+	)
+	cellMeters := idx.cellSize * math.Min(metersPerDegreeLat, metersPerDegreeLon)
+	maxRing := int(math.Ceil(q.RadiusMeters/cellMeters)) + 1
+
+	results := &resultHeap{}
+	for ring := 0; ring <= maxRing; ring++ {
+		for _, cell := range ringCells(center, ring) {
+			for _, cells := range packageCells {
+				for _, it := range cells[cell] {
+					if q.Exclude != nil && q.Exclude(it.id) {
+						continue
+					}
+
+					distance := Distance(q.Latitude, q.Longitude, it.lat, it.lon)
+					if distance > q.RadiusMeters {
+						continue
+					}
+
+					// Keep only the Limit closest items, the farthest one sits on top of the heap
+					if q.Limit > 0 && results.Len() == q.Limit {
+						if distance >= (*results)[0].DistanceMeters {
+							continue
+						}
+						heap.Pop(results)
+					}
+					heap.Push(results, Result{ID: it.id, DistanceMeters: distance})
+				}
+			}
+		}
+
+		// Anything in the next rings is at least this far away
+		reached := offset + float64(ring)*cellMeters
+		if reached >= q.RadiusMeters {
+			break
+		}
+		if q.Limit > 0 && results.Len() == q.Limit && (*results)[0].DistanceMeters <= reached {
+			break
+		}
+	}
+
+	if results.Len() == 0 {
+		return nil
+	}
+
+	sorted := []Result(*results)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].DistanceMeters < sorted[j].DistanceMeters
+	})
+
+	return sorted
+}
+
+// Bounds is a latitude/longitude rectangle.
+type Bounds struct {
+	MinLatitude, MinLongitude float64
+	MaxLatitude, MaxLongitude float64
+}
+
+// Contains reports whether the point lies inside the rectangle, edges included.
+func (b Bounds) Contains(lat, lon float64) bool {
+	return lat >= b.MinLatitude && lat <= b.MaxLatitude && lon >= b.MinLongitude && lon <= b.MaxLongitude
+}
+
+// BoundsAround returns the smallest rectangle holding the circle of radiusMeters around the point.
+func BoundsAround(lat, lon, radiusMeters float64) Bounds {
+	metersPerDegreeLat := math.Pi / 180 * earthRadiusMeters
+	dLat := radiusMeters / metersPerDegreeLat
+	dLon := radiusMeters / (metersPerDegreeLat * math.Max(math.Cos(lat*math.Pi/180), 1e-6))
+
+	return Bounds{
+		MinLatitude:  lat - dLat,
+		MinLongitude: lon - dLon,
+		MaxLatitude:  lat + dLat,
+		MaxLongitude: lon + dLon,
+	}
+}
+
+// Position is an item with where it is.
+type Position struct {
+	ID          string
+	PackageSlug string
+	Latitude    float64
+	Longitude   float64
+}
+
+// Within returns the items inside the rectangle, of the package unless packageSlug is empty.
+// With a positive limit only the limit items closest to the centre of the rectangle are returned,
+// so the same items keep being returned while they don't move.
+func (idx *Index) Within(b Bounds, packageSlug string, limit int) []Position {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if b.MinLatitude > b.MaxLatitude || b.MinLongitude > b.MaxLongitude {
+		return nil
+	}
+
+	var positions []Position
+	collect := func(it *item) {
+		if b.Contains(it.lat, it.lon) && (packageSlug == "" || it.packageSlug == packageSlug) {
+			positions = append(positions, Position{ID: it.id, PackageSlug: it.packageSlug, Latitude: it.lat, Longitude: it.lon})
+		}
+	}
+
+	minCell, maxCell := idx.cellOf(b.MinLatitude, b.MinLongitude), idx.cellOf(b.MaxLatitude, b.MaxLongitude)
+	cellCount := (maxCell.lat - minCell.lat + 1) * (maxCell.lon - minCell.lon + 1)
+
+	if cellCount > len(idx.items) {
+		// Large rectangles cover more cells than there are items, scanning the items is cheaper
+		for _, it := range idx.items {
+			collect(it)
+		}
+	} else {
+		for lat := minCell.lat; lat <= maxCell.lat; lat++ {
+			for lon := minCell.lon; lon <= maxCell.lon; lon++ {
+				for slug, cells := range idx.cells {
+					if packageSlug != "" && slug != packageSlug {
+						continue
+					}
+					for _, it := range cells[cellKey{lat, lon}] {
+						collect(it)
+					}
+				}
+			}
+		}
+	}
+
+	if limit > 0 && len(positions) > limit {
+		centerLat, centerLon := (b.MinLatitude+b.MaxLatitude)/2, (b.MinLongitude+b.MaxLongitude)/2
+		sort.Slice(positions, func(i, j int) bool {
+			return Distance(centerLat, centerLon, positions[i].Latitude, positions[i].Longitude) <
+				Distance(centerLat, centerLon, positions[j].Latitude, positions[j].Longitude)
+		})
+		positions = positions[:limit]
+	}
+
+	return positions
+}
+
+// Distance returns the great-circle distance in meters between two points.
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLon := (lon2 - lon1) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusMeters * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+func (idx *Index) cellOf(lat, lon float64) cellKey {
+	return cellKey{
+		lat: int(math.Floor(lat / idx.cellSize)),
+		lon: int(math.Floor(lon / idx.cellSize)),
+	}
+}
+
+// removeLocked must be called with the write lock held.
+func (idx *Index) removeLocked(it *item) {
+	delete(idx.items, it.id)
+
+	packageCells := idx.cells[it.packageSlug]
+	members := packageCells[it.cell]
+	delete(members, it.id)
+
+	if len(members) == 0 {
+		delete(packageCells, it.cell)
+	}
+	if len(packageCells) == 0 {
+		delete(idx.cells, it.packageSlug)
+	}
+}
+
+// ringCells returns the cells at exactly ring cells from the center, in Chebyshev distance.
+func ringCells(center cellKey, ring int) []cellKey {
+	if ring == 0 {
+		return []cellKey{center}
+	}
+
+	cells := make([]cellKey, 0, 8*ring)
+	for d := -ring; d <= ring; d++ {
+		cells = append(cells,
+			cellKey{center.lat - ring, center.lon + d},
+			cellKey{center.lat + ring, center.lon + d},
+		)
+	}
+	for d := -ring + 1; d <= ring-1; d++ {
+		cells = append(cells,
+			cellKey{center.lat + d, center.lon - ring},
+			cellKey{center.lat + d, center.lon + ring},
+		)
+	}
+
+	return cells
+}
+
+// resultHeap is a max-heap on distance.
+type resultHeap []Result
+
+func (h resultHeap) Len() int           { return len(h) }
+func (h resultHeap) Less(i, j int) bool { return h[i].DistanceMeters > h[j].DistanceMeters }
+func (h resultHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *resultHeap) Push(x any)        { *h = append(*h, x.(Result)) }
+func (h *resultHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,190 @@
+package geoindex
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"testing"
+)
+
+var packages = []string{"suv", "sedan", "van", "luxury"}
+
+// Bounding box of San Francisco
+const (
+	minLat, maxLat = 37.70, 37.82
+	minLon, maxLon = -122.52, -122.36
+)
+
+const (
+	benchLimit  = 5
+	benchRadius = 5000
+)
+
+type simulatedDriver struct {
+	id          string
+	packageSlug string
+	lat, lon    float64
+}
+
+func simulateDrivers(rng *rand.Rand, count int) []simulatedDriver {
+	drivers := make([]simulatedDriver, count)
+	for i := range drivers {
+		lat, lon := randomPoint(rng)
+		drivers[i] = simulatedDriver{
+			id:          fmt.Sprintf("driver-%d", i),
+			packageSlug: packages[i%len(packages)],
+			lat:         lat,
+			lon:         lon,
+		}
+	}
+
+	return drivers
+}
+
+func indexDrivers(drivers []simulatedDriver) *Index {
+	index := New(DefaultCellSizeDegrees)
+	for _, d := range drivers {
+		index.Upsert(d.id, d.packageSlug, d.lat, d.lon)
+	}
+
+	return index
+}
+
+func randomPoint(rng *rand.Rand) (float64, float64) {
+	return minLat + rng.Float64()*(maxLat-minLat), minLon + rng.Float64()*(maxLon-minLon)
+}
+
+// scanNearest is what the driver pool did before the index: look at every driver.
+func scanNearest(drivers []simulatedDriver, lat, lon float64, packageSlug string, limit int, radius float64) []Result {
+	var results []Result
+	for _, d := range drivers {
+		if d.packageSlug != packageSlug {
+			continue
+		}
+
+		if distance := Distance(lat, lon, d.lat, d.lon); distance <= radius {
+			results = append(results, Result{ID: d.id, DistanceMeters: distance})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].DistanceMeters < results[j].DistanceMeters
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+func TestNearestMatchesScan(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	drivers := simulateDrivers(rng, 2000)
+	index := indexDrivers(drivers)
+
+	for i := 0; i < 200; i++ {
+		lat, lon := randomPoint(rng)
+		packageSlug := packages[i%len(packages)]
+		radius := 500 + rng.Float64()*5000
+
+		got := index.Nearest(Query{
+			Latitude:     lat,
+			Longitude:    lon,
+			PackageSlug:  packageSlug,
+			Limit:        benchLimit,
+			RadiusMeters: radius,
+		})
+		want := scanNearest(drivers, lat, lon, packageSlug, benchLimit, radius)
+
+		if len(got) != len(want) {
+			t.Fatalf("query %d: got %d results, want %d", i, len(got), len(want))
+		}
+		for j := range want {
+			if got[j].ID != want[j].ID {
+				t.Fatalf("query %d: result %d is %s, want %s", i, j, got[j].ID, want[j].ID)
+			}
+		}
+	}
+}
+
+func TestNearestExcludesAndRemoves(t *testing.T) {
+	index := New(DefaultCellSizeDegrees)
+	index.Upsert("near", "sedan", 37.7749, -122.4194)
+	index.Upsert("far", "sedan", 37.7849, -122.4194)
+	index.Upsert("suv", "suv", 37.7749, -122.4194)
+
+	query := Query{Latitude: 37.7749, Longitude: -122.4194, PackageSlug: "sedan", RadiusMeters: 5000}
+
+	if got := index.Nearest(query); len(got) != 2 || got[0].ID != "near" || got[1].ID != "far" {
+		t.Fatalf("got %+v, want near then far", got)
+	}
+
+	query.Exclude = func(id string) bool { return id == "near" }
+	if got := index.Nearest(query); len(got) != 1 || got[0].ID != "far" {
+		t.Fatalf("got %+v, want far only", got)
+	}
+
+	index.Remove("far")
+	if got := index.Nearest(query); got != nil {
+		t.Fatalf("got %+v, want no result", got)
+	}
+}
+
+// The benchmarks compare nearest driver lookups in the grid index with a full scan of the driver pool:
+//
+//	go test -bench Nearest ./services/driver-service/internal/geoindex
+var benchDriverCounts = []int{1000, 10000, 50000}
+
+func BenchmarkNearestIndex(b *testing.B) {
+	for _, count := range benchDriverCounts {
+		b.Run(fmt.Sprintf("drivers=%d", count), func(b *testing.B) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			index := indexDrivers(simulateDrivers(rng, count))
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				lat, lon := randomPoint(rng)
+				index.Nearest(Query{
+					Latitude:     lat,
+					Longitude:    lon,
+					PackageSlug:  packages[i%len(packages)],
+					Limit:        benchLimit,
+					RadiusMeters: benchRadius,
+				})
+			}
+		})
+	}
+}
+
+func BenchmarkNearestScan(b *testing.B) {
+	for _, count := range benchDriverCounts {
+		b.Run(fmt.Sprintf("drivers=%d", count), func(b *testing.B) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			drivers := simulateDrivers(rng, count)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				lat, lon := randomPoint(rng)
+				scanNearest(drivers, lat, lon, packages[i%len(packages)], benchLimit, benchRadius)
+			}
+		})
+	}
+}
+
+func BenchmarkUpsert(b *testing.B) {
+	for _, count := range benchDriverCounts {
+		b.Run(fmt.Sprintf("drivers=%d", count), func(b *testing.B) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			drivers := simulateDrivers(rng, count)
+			index := indexDrivers(drivers)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				d := drivers[i%len(drivers)]
+				lat, lon := randomPoint(rng)
+				index.Upsert(d.id, d.packageSlug, lat, lon)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package matching
+
+import "time"
+
+// PickupETA estimates how long a driver takes to reach a pickup distanceMeters away,
+// driving at speedKmh on average.
+func PickupETA(distanceMeters, speedKmh float64) time.Duration {
+	if speedKmh <= 0 {
+		return 0
+	}
+
+	return time.Duration(distanceMeters / (speedKmh / 3.6) * float64(time.Second))
+}
@@ -0,0 +1,128 @@
+// Package matching pairs trip requests with drivers.
+package matching
+
+import "math"
+
+// Unmatched marks a row left without a column.
+const Unmatched = -1
+
+// Solve returns the assignment of rows to columns with the minimum total cost,
+// using the Hungarian algorithm in O(n²m) for n rows and m columns.
+// Rows and columns do not need to be the same number, the extra ones are left unmatched.
+// A +Inf cost forbids a pair, a row whose every pair is forbidden is left unmatched.
+//
+// It returns the column of every row, or Unmatched.
+func Solve(cost [][]float64) []int {
+	rows := len(cost)
+	if rows == 0 {
+		return nil
+	}
+	cols := len(cost[0])
+
+	assignment := make([]int, rows)
+	for i := range assignment {
+		assignment[i] = Unmatched
+	}
+	if cols == 0 {
+		return assignment
+	}
+
+	// Forbidden pairs get a cost higher than any assignment made of allowed pairs only
+	forbidden := 1.0
+	for _, row := range cost {
+		for _, c := range row {
+			if !math.IsInf(c, 1) {
+				forbidden += math.Abs(c)
+			}
+		}
+	}
+
+	// The algorithm needs at most as many rows as columns, so the matrix is transposed otherwise
+	transposed := rows > cols
+	n, m := rows, cols
+	if transposed {
+		n, m = cols, rows
+	}
+
+	at := func(i, j int) float64 {
+		if transposed {
+			i, j = j, i
+		}
+		c := cost[i][j]
+		if math.IsInf(c, 1) {
+			return forbidden
+		}
+		return c
+	}
+
+	// 1-indexed potentials and matching, p[j] is the row matched to column j
+	u := make([]float64, n+1)
+	v := make([]float64, m+1)
+	p := make([]int, m+1)
+	way := make([]int, m+1)
+
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		minv := make([]float64, m+1)
+		used := make([]bool, m+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+
+		for p[j0] != 0 {
+			used[j0] = true
+			i0 := p[j0]
+			delta := math.Inf(1)
+			j1 := 0
+
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+
+				if cur := at(i0-1, j-1) - u[i0] - v[j]; cur < minv[j] {
+					minv[j] = cur
+					way[j] = j0
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	for j := 1; j <= m; j++ {
+		if p[j] == 0 {
+			continue
+		}
+
+		row, col := p[j]-1, j-1
+		if transposed {
+			row, col = col, row
+		}
+
+		if !math.IsInf(cost[row][col], 1) {
+			assignment[row] = col
+		}
+	}
+
+	return assignment
+}
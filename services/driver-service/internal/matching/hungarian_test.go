@@ -0,0 +1,182 @@
+package matching
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+var inf = math.Inf(1)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name string
+		cost [][]float64
+		want []int
+	}{
+		{
+			name: "empty",
+			cost: nil,
+			want: nil,
+		},
+		{
+			name: "no columns",
+			cost: [][]float64{{}, {}},
+			want: []int{Unmatched, Unmatched},
+		},
+		{
+			name: "square",
+			cost: [][]float64{
+				{4, 1, 3},
+				{2, 0, 5},
+				{3, 2, 2},
+			},
+			want: []int{1, 0, 2},
+		},
+		{
+			name: "closest driver goes to the trip that needs it most",
+			cost: [][]float64{
+				{1, 2},
+				{1, 10},
+			},
+			want: []int{1, 0},
+		},
+		{
+			name: "more drivers than requests",
+			cost: [][]float64{
+				{10, 2, 8},
+				{9, 7, 1},
+			},
+			want: []int{1, 2},
+		},
+		{
+			name: "more requests than drivers",
+			cost: [][]float64{
+				{5},
+				{1},
+				{3},
+			},
+			want: []int{Unmatched, 0, Unmatched},
+		},
+		{
+			name: "more requests than drivers with several drivers",
+			cost: [][]float64{
+				{1, 4},
+				{2, 6},
+				{3, 1},
+			},
+			want: []int{0, Unmatched, 1},
+		},
+		{
+			name: "forbidden pairs",
+			cost: [][]float64{
+				{inf, 1},
+				{inf, 2},
+			},
+			want: []int{1, Unmatched},
+		},
+		{
+			name: "row without allowed pairs",
+			cost: [][]float64{
+				{inf, inf},
+				{1, 2},
+			},
+			want: []int{Unmatched, 0},
+		},
+		{
+			name: "matching more rows beats a cheaper total",
+			cost: [][]float64{
+				{1, 100},
+				{1, inf},
+			},
+			want: []int{1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Solve(tt.cost); !slices.Equal(got, tt.want) {
+				t.Errorf("Solve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSolveMatchesBruteForce checks the total cost of random rectangular matrices
+// against the best one found by trying every assignment.
+func TestSolveMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for round := 0; round < 300; round++ {
+		rows, cols := 1+rng.IntN(5), 1+rng.IntN(5)
+		cost := make([][]float64, rows)
+		for i := range cost {
+			cost[i] = make([]float64, cols)
+			for j := range cost[i] {
+				cost[i][j] = float64(rng.IntN(50))
+				if rng.IntN(5) == 0 {
+					cost[i][j] = inf
+				}
+			}
+		}
+
+		assignment := Solve(cost)
+		used := make(map[int]bool)
+		for i, j := range assignment {
+			if j == Unmatched {
+				continue
+			}
+			if used[j] || math.IsInf(cost[i][j], 1) {
+				t.Fatalf("round %d: invalid assignment %v of %v", round, assignment, cost)
+			}
+			used[j] = true
+		}
+
+		gotMatched, gotTotal := score(cost, assignment)
+		wantMatched, wantTotal := bestAssignment(cost, 0, make([]bool, cols))
+		if gotMatched != wantMatched || gotTotal != wantTotal {
+			t.Fatalf("round %d: %v matches %d rows for %v, want %d rows for %v in %v",
+				round, assignment, gotMatched, gotTotal, wantMatched, wantTotal, cost)
+		}
+	}
+}
+
+func score(cost [][]float64, assignment []int) (int, float64) {
+	matched, total := 0, 0.0
+	for i, j := range assignment {
+		if j != Unmatched {
+			matched++
+			total += cost[i][j]
+		}
+	}
+
+	return matched, total
+}
+
+// bestAssignment returns the most rows from row on that can be matched, and the lowest cost of doing so.
+func bestAssignment(cost [][]float64, row int, used []bool) (int, float64) {
+	if row == len(cost) {
+		return 0, 0
+	}
+
+	// Leaving the row unmatched
+	bestMatched, bestTotal := bestAssignment(cost, row+1, used)
+
+	for j, c := range cost[row] {
+		if used[j] || math.IsInf(c, 1) {
+			continue
+		}
+
+		used[j] = true
+		matched, total := bestAssignment(cost, row+1, used)
+		used[j] = false
+
+		matched, total = matched+1, total+c
+		if matched > bestMatched || (matched == bestMatched && total < bestTotal) {
+			bestMatched, bestTotal = matched, total
+		}
+	}
+
+	return bestMatched, bestTotal
+}
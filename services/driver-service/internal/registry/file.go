@@ -0,0 +1,29 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SeedFromFile saves the profiles listed in a JSON file, replacing existing ones with the same id.
+func SeedFromFile(ctx context.Context, repo Repository, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read drivers file: %w", err)
+	}
+
+	var profiles []*Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return 0, fmt.Errorf("failed to parse drivers file: %w", err)
+	}
+
+	for _, profile := range profiles {
+		if err := repo.SaveProfile(ctx, profile); err != nil {
+			return 0, fmt.Errorf("failed to save driver %s: %w", profile.ID, err)
+		}
+	}
+
+	return len(profiles), nil
+}
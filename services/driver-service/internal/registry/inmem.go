@@ -0,0 +1,45 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type inmemRepository struct {
+	mu       sync.RWMutex
+	profiles map[string]*Profile
+}
+
+func NewInmemRepository() *inmemRepository {
+	return &inmemRepository{
+		profiles: make(map[string]*Profile),
+	}
+}
+
+func (r *inmemRepository) GetProfile(ctx context.Context, id string) (*Profile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	profile, ok := r.profiles[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, id)
+	}
+
+	copied := *profile
+	return &copied, nil
+}
+
+func (r *inmemRepository) SaveProfile(ctx context.Context, profile *Profile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *profile
+	r.profiles[profile.ID] = &copied
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ride-sharing/shared/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoRepository(db *mongo.Database) *mongoRepository {
+	return &mongoRepository{db: db}
+}
+
+func (r *mongoRepository) GetProfile(ctx context.Context, id string) (*Profile, error) {
+	var profile Profile
+	err := r.db.Collection(db.DriverProfilesCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&profile)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, id)
+		}
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+func (r *mongoRepository) SaveProfile(ctx context.Context, profile *Profile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	_, err := r.db.Collection(db.DriverProfilesCollection).ReplaceOne(
+		ctx,
+		bson.M{"_id": profile.ID},
+		profile,
+		options.Replace().SetUpsert(true),
+	)
+
+	return err
+}
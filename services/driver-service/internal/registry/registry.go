@@ -0,0 +1,109 @@
+// Package registry stores the profiles of the drivers allowed to go online.
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	ErrProfileNotFound    = errors.New("driver profile not found")
+	ErrProfileNotApproved = errors.New("driver profile is not approved")
+	ErrNoEligibleVehicle  = errors.New("driver has no vehicle eligible for the package")
+	ErrInvalidProfile     = errors.New("invalid driver profile")
+)
+
+type Status string
+
+const (
+	// StatusPending profiles were submitted but their documents were not checked yet
+	StatusPending   Status = "pending"
+	StatusApproved  Status = "approved"
+	StatusSuspended Status = "suspended"
+)
+
+// Profile is what we know about a driver, independently of whether the driver is online.
+type Profile struct {
+	ID       string    `json:"id" bson:"_id"`
+	Name     string    `json:"name" bson:"name"`
+	PhotoURL string    `json:"photoURL" bson:"photoURL"`
+	Phone    string    `json:"phone" bson:"phone"`
+	License  License   `json:"license" bson:"license"`
+	Vehicles []Vehicle `json:"vehicles" bson:"vehicles"`
+	Status   Status    `json:"status" bson:"status"`
+}
+
+type License struct {
+	Number    string    `json:"number" bson:"number"`
+	ExpiresAt time.Time `json:"expiresAt" bson:"expiresAt"`
+}
+
+type Vehicle struct {
+	Plate  string `json:"plate" bson:"plate"`
+	Make   string `json:"make" bson:"make"`
+	Model  string `json:"model" bson:"model"`
+	Colour string `json:"colour" bson:"colour"`
+	Seats  int    `json:"seats" bson:"seats"`
+	// PackageSlugs are the packages the vehicle can be driven for
+	PackageSlugs []string `json:"packageSlugs" bson:"packageSlugs"`
+}
+
+func (p *Profile) Validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("%w: missing id", ErrInvalidProfile)
+	}
+
+	if p.Name == "" {
+		return fmt.Errorf("%w: %s has no name", ErrInvalidProfile, p.ID)
+	}
+
+	switch p.Status {
+	case StatusPending, StatusApproved, StatusSuspended:
+	default:
+		return fmt.Errorf("%w: %s has unknown status %q", ErrInvalidProfile, p.ID, p.Status)
+	}
+
+	for _, vehicle := range p.Vehicles {
+		if vehicle.Plate == "" {
+			return fmt.Errorf("%w: %s has a vehicle without a plate", ErrInvalidProfile, p.ID)
+		}
+		if vehicle.Seats <= 0 {
+			return fmt.Errorf("%w: vehicle %s of %s has no seats", ErrInvalidProfile, vehicle.Plate, p.ID)
+		}
+	}
+
+	return nil
+}
+
+// CanDrive returns ErrProfileNotApproved unless the driver is approved and holds a valid license.
+func (p *Profile) CanDrive(now time.Time) error {
+	if p.Status != StatusApproved {
+		return fmt.Errorf("%w: %s is %s", ErrProfileNotApproved, p.ID, p.Status)
+	}
+
+	if !p.License.ExpiresAt.IsZero() && now.After(p.License.ExpiresAt) {
+		return fmt.Errorf("%w: license of %s expired", ErrProfileNotApproved, p.ID)
+	}
+
+	return nil
+}
+
+// VehicleFor returns the first vehicle of the driver eligible for the package.
+func (p *Profile) VehicleFor(packageSlug string) (*Vehicle, error) {
+	for i := range p.Vehicles {
+		if slices.Contains(p.Vehicles[i].PackageSlugs, packageSlug) {
+			return &p.Vehicles[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s for %s", ErrNoEligibleVehicle, p.ID, packageSlug)
+}
+
+type Repository interface {
+	// GetProfile returns ErrProfileNotFound when there is no profile with the id.
+	GetProfile(ctx context.Context, id string) (*Profile, error)
+	SaveProfile(ctx context.Context, profile *Profile) error
+}
@@ -0,0 +1,104 @@
+package simulation
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"ride-sharing/services/driver-service/internal/geoindex"
+)
+
+// Bounding box of San Francisco, random routes stay inside it
+const (
+	minLat, maxLat = 37.70, 37.81
+	minLon, maxLon = -122.51, -122.38
+)
+
+// Point is a latitude and longitude pair.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Path moves a position along a polyline at a given speed.
+type Path struct {
+	points []Point
+	// next is the index of the point the position is heading to
+	next     int
+	position Point
+}
+
+// NewPath starts at from and goes through every point of the route.
+func NewPath(from Point, route []Point) *Path {
+	return &Path{
+		points:   route,
+		position: from,
+	}
+}
+
+func (p *Path) Position() Point {
+	return p.position
+}
+
+func (p *Path) Done() bool {
+	return p.next >= len(p.points)
+}
+
+// Advance moves the position by meters along the path, stopping at its end.
+func (p *Path) Advance(meters float64) Point {
+	for meters > 0 && !p.Done() {
+		target := p.points[p.next]
+		distance := geoindex.Distance(p.position.Latitude, p.position.Longitude, target.Latitude, target.Longitude)
+
+		if distance <= meters {
+			p.position = target
+			p.next++
+			meters -= distance
+			continue
+		}
+
+		ratio := meters / distance
+		p.position = Point{
+			Latitude:  p.position.Latitude + (target.Latitude-p.position.Latitude)*ratio,
+			Longitude: p.position.Longitude + (target.Longitude-p.position.Longitude)*ratio,
+		}
+		meters = 0
+	}
+
+	return p.position
+}
+
+// PredefinedRoute returns one of the predefined routes.
+func PredefinedRoute(rng *rand.Rand) []Point {
+	route := PredefinedRoutes[rng.IntN(len(PredefinedRoutes))]
+
+	points := make([]Point, len(route))
+	for i, coord := range route {
+		points[i] = Point{Latitude: coord[0], Longitude: coord[1]}
+	}
+
+	return points
+}
+
+// RandomRoute wanders from start through a few waypoints, each at most legMeters from the previous one.
+func RandomRoute(rng *rand.Rand, start Point, waypoints int, legMeters float64) []Point {
+	const metersPerDegree = math.Pi / 180 * 6371000
+
+	points := make([]Point, 0, waypoints)
+	current := start
+	for i := 0; i < waypoints; i++ {
+		bearing := rng.Float64() * 2 * math.Pi
+		distance := legMeters * (0.3 + 0.7*rng.Float64())
+
+		current = Point{
+			Latitude:  clamp(current.Latitude+distance*math.Cos(bearing)/metersPerDegree, minLat, maxLat),
+			Longitude: clamp(current.Longitude+distance*math.Sin(bearing)/(metersPerDegree*math.Cos(current.Latitude*math.Pi/180)), minLon, maxLon),
+		}
+		points = append(points, current)
+	}
+
+	return points
+}
+
+func clamp(value, min, max float64) float64 {
+	return math.Max(min, math.Min(max, value))
+}
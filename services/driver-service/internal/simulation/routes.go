@@ -1,8 +1,7 @@
-package main
+// Package simulation moves virtual drivers around San Francisco.
+package simulation
 
-import "math/rand"
-
-// Predefined routes for drivers (used for the gRPC Streaming module)
+// Predefined routes for drivers, as [latitude, longitude] points
 // (these are San Francisco routes, get these coordinates from Google Maps for example and build a custom route if you want)
 var PredefinedRoutes = [][][]float64{
 	{
@@ -45,13 +44,3 @@ var PredefinedRoutes = [][][]float64{
 		{37.78300293033823, -122.4225475612199},
 	},
 }
-
-func GenerateRandomPlate() string {
-	letters := "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	plate := ""
-	for i := 0; i < 3; i++ {
-		plate += string(letters[rand.Intn(len(letters))])
-	}
-
-	return plate
-}
@@ -0,0 +1,276 @@
+// Package stats keeps rolling counts of how drivers answer the trips they are offered and how riders rate them.
+package stats
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is something a driver did with a trip.
+type Event int
+
+const (
+	// Offered is a trip sent to the driver
+	Offered Event = iota
+	Accepted
+	Declined
+	// TimedOut is an offer the driver did not answer in time
+	TimedOut
+	// Cancelled is a trip the driver accepted and then cancelled
+	Cancelled
+
+	numEvents
+)
+
+// Flag marks a driver whose stats are past one of the thresholds of Config.
+type Flag string
+
+const (
+	FlagLowAcceptance    Flag = "low_acceptance"
+	FlagHighCancellation Flag = "high_cancellation"
+	FlagLowRating        Flag = "low_rating"
+)
+
+// MaxRating is the best rating a rider can give.
+const MaxRating = 5
+
+type Config struct {
+	// Window is how far back stats go, older events are forgotten
+	Window time.Duration
+	// BucketSize is the granularity of the window, events drop out of it a bucket at a time
+	BucketSize time.Duration
+	// MinAnswers is the number of answered offers below which the acceptance rate is not judged
+	MinAnswers int
+	// MinAcceptanceRate flags drivers accepting fewer of the offers they answered
+	MinAcceptanceRate float64
+	// MinAccepted is the number of accepted trips below which the cancellation rate is not judged
+	MinAccepted int
+	// MaxCancellationRate flags drivers cancelling more of the trips they accepted
+	MaxCancellationRate float64
+	// MinRatings is the number of ratings below which the average rating is not judged
+	MinRatings int
+	// MinRating flags drivers rated lower on average
+	MinRating float64
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Window:              7 * 24 * time.Hour,
+		BucketSize:          time.Hour,
+		MinAnswers:          10,
+		MinAcceptanceRate:   0.5,
+		MinAccepted:         10,
+		MaxCancellationRate: 0.1,
+		MinRatings:          5,
+		MinRating:           4.5,
+	}
+}
+
+// Snapshot is the stats of a driver over the window.
+type Snapshot struct {
+	DriverID  string
+	Offers    int
+	Accepted  int
+	Declined  int
+	TimedOut  int
+	Cancelled int
+	Ratings   int
+	// AcceptanceRate is the share of answered offers the driver accepted, timeouts count as declines
+	AcceptanceRate float64
+	// CancellationRate is the share of accepted trips the driver cancelled
+	CancellationRate float64
+	// AverageRating is 0 until the driver is rated
+	AverageRating float64
+	// Score sums up the stats from 0 to 1, rates the driver has too few events for count as perfect
+	Score float64
+	Flags []Flag
+}
+
+// Flagged reports whether the driver is past any of the thresholds.
+func (s *Snapshot) Flagged() bool {
+	return len(s.Flags) > 0
+}
+
+// Tracker is safe for concurrent use.
+type Tracker struct {
+	config *Config
+	now    func() time.Time
+
+	mu      sync.Mutex
+	drivers map[string]*driverStats
+}
+
+type driverStats struct {
+	// buckets hold the activity of the window, oldest first, buckets without any are not kept
+	buckets []bucket
+	flagged bool
+}
+
+type bucket struct {
+	// slot is the number of BucketSize periods since the Unix epoch
+	slot      int64
+	counts    [numEvents]int
+	ratingSum int
+	ratings   int
+}
+
+func NewTracker(config *Config) *Tracker {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Tracker{
+		config:  config,
+		now:     time.Now,
+		drivers: make(map[string]*driverStats),
+	}
+}
+
+// Config returns the configuration the tracker was created with.
+func (t *Tracker) Config() *Config {
+	return t.config
+}
+
+// Record counts the event for the driver.
+// It returns the stats of the driver when the event got it flagged or cleared its flags, nil otherwise.
+func (t *Tracker) Record(driverID string, event Event) *Snapshot {
+	if driverID == "" || event < 0 || event >= numEvents {
+		return nil
+	}
+
+	return t.update(driverID, func(b *bucket) {
+		b.counts[event]++
+	})
+}
+
+// RecordRating counts a rating from 1 to MaxRating a rider gave the driver, see Record for what it returns.
+func (t *Tracker) RecordRating(driverID string, stars int) *Snapshot {
+	if driverID == "" || stars < 1 || stars > MaxRating {
+		return nil
+	}
+
+	return t.update(driverID, func(b *bucket) {
+		b.ratingSum += stars
+		b.ratings++
+	})
+}
+
+func (t *Tracker) update(driverID string, apply func(b *bucket)) *Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats, ok := t.drivers[driverID]
+	if !ok {
+		stats = &driverStats{}
+		t.drivers[driverID] = stats
+	}
+
+	slot := t.slot()
+	t.expire(stats, slot)
+
+	if n := len(stats.buckets); n == 0 || stats.buckets[n-1].slot != slot {
+		stats.buckets = append(stats.buckets, bucket{slot: slot})
+	}
+	apply(&stats.buckets[len(stats.buckets)-1])
+
+	snapshot := t.snapshot(driverID, stats)
+	if snapshot.Flagged() == stats.flagged {
+		return nil
+	}
+
+	stats.flagged = snapshot.Flagged()
+	return snapshot
+}
+
+// Stats returns the stats of the driver, drivers without any activity in the window have empty stats.
+func (t *Tracker) Stats(driverID string) *Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats, ok := t.drivers[driverID]
+	if !ok {
+		return t.snapshot(driverID, &driverStats{})
+	}
+
+	t.expire(stats, t.slot())
+	if len(stats.buckets) == 0 {
+		delete(t.drivers, driverID)
+	}
+
+	return t.snapshot(driverID, stats)
+}
+
+// Score returns the score of the driver, see Snapshot.
+func (t *Tracker) Score(driverID string) float64 {
+	return t.Stats(driverID).Score
+}
+
+func (t *Tracker) slot() int64 {
+	return t.now().UnixNano() / int64(t.config.BucketSize)
+}
+
+// expire drops the buckets that fell out of the window, it must be called with the lock held.
+func (t *Tracker) expire(stats *driverStats, slot int64) {
+	oldest := slot - int64(t.config.Window/t.config.BucketSize) + 1
+
+	i := 0
+	for i < len(stats.buckets) && stats.buckets[i].slot < oldest {
+		i++
+	}
+	stats.buckets = stats.buckets[i:]
+}
+
+func (t *Tracker) snapshot(driverID string, stats *driverStats) *Snapshot {
+	var total bucket
+	for _, b := range stats.buckets {
+		for event, count := range b.counts {
+			total.counts[event] += count
+		}
+		total.ratingSum += b.ratingSum
+		total.ratings += b.ratings
+	}
+
+	snapshot := &Snapshot{
+		DriverID:  driverID,
+		Offers:    total.counts[Offered],
+		Accepted:  total.counts[Accepted],
+		Declined:  total.counts[Declined],
+		TimedOut:  total.counts[TimedOut],
+		Cancelled: total.counts[Cancelled],
+		Ratings:   total.ratings,
+		Score:     1,
+	}
+
+	if answers := snapshot.Accepted + snapshot.Declined + snapshot.TimedOut; answers > 0 {
+		snapshot.AcceptanceRate = float64(snapshot.Accepted) / float64(answers)
+		if answers >= t.config.MinAnswers {
+			snapshot.Score *= snapshot.AcceptanceRate
+			if snapshot.AcceptanceRate < t.config.MinAcceptanceRate {
+				snapshot.Flags = append(snapshot.Flags, FlagLowAcceptance)
+			}
+		}
+	}
+
+	if snapshot.Accepted > 0 {
+		// Trips accepted before the window started can be cancelled within it
+		snapshot.CancellationRate = min(float64(snapshot.Cancelled)/float64(snapshot.Accepted), 1)
+		if snapshot.Accepted >= t.config.MinAccepted {
+			snapshot.Score *= 1 - snapshot.CancellationRate
+			if snapshot.CancellationRate > t.config.MaxCancellationRate {
+				snapshot.Flags = append(snapshot.Flags, FlagHighCancellation)
+			}
+		}
+	}
+
+	if total.ratings > 0 {
+		snapshot.AverageRating = float64(total.ratingSum) / float64(total.ratings)
+		if total.ratings >= t.config.MinRatings {
+			snapshot.Score *= snapshot.AverageRating / MaxRating
+			if snapshot.AverageRating < t.config.MinRating {
+				snapshot.Flags = append(snapshot.Flags, FlagLowRating)
+			}
+		}
+	}
+
+	return snapshot
+}
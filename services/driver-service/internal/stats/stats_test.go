@@ -0,0 +1,193 @@
+package stats
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func testConfig() *Config {
+	config := DefaultConfig()
+	config.MinAnswers = 4
+	config.MinAccepted = 4
+	config.MinRatings = 2
+	return config
+}
+
+func repeat(event Event, n int) []Event {
+	events := make([]Event, n)
+	for i := range events {
+		events[i] = event
+	}
+	return events
+}
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name                 string
+		events               []Event
+		ratings              []int
+		wantAcceptanceRate   float64
+		wantCancellationRate float64
+		wantAverageRating    float64
+		wantScore            float64
+		wantFlags            []Flag
+	}{
+		{
+			name:      "no activity",
+			wantScore: 1,
+		},
+		{
+			name:               "timeouts count as declines",
+			events:             slices.Concat(repeat(Accepted, 2), repeat(Declined, 1), repeat(TimedOut, 1)),
+			wantAcceptanceRate: 0.5,
+			wantScore:          0.5,
+		},
+		{
+			name:               "low acceptance",
+			events:             slices.Concat(repeat(Accepted, 1), repeat(Declined, 3)),
+			wantAcceptanceRate: 0.25,
+			wantScore:          0.25,
+			wantFlags:          []Flag{FlagLowAcceptance},
+		},
+		{
+			name:               "acceptance is not judged below MinAnswers",
+			events:             repeat(Declined, 3),
+			wantAcceptanceRate: 0,
+			wantScore:          1,
+		},
+		{
+			name:                 "high cancellation",
+			events:               slices.Concat(repeat(Accepted, 4), repeat(Cancelled, 1)),
+			wantAcceptanceRate:   1,
+			wantCancellationRate: 0.25,
+			wantScore:            0.75,
+			wantFlags:            []Flag{FlagHighCancellation},
+		},
+		{
+			name:                 "cancellation rate is capped",
+			events:               slices.Concat(repeat(Accepted, 1), repeat(Cancelled, 2)),
+			wantAcceptanceRate:   1,
+			wantCancellationRate: 1,
+			wantScore:            1,
+		},
+		{
+			name:              "average rating",
+			ratings:           []int{5, 4, 5, 4},
+			wantAverageRating: 4.5,
+			wantScore:         0.9,
+		},
+		{
+			name:              "low rating",
+			ratings:           []int{3, 4},
+			wantAverageRating: 3.5,
+			wantScore:         0.7,
+			wantFlags:         []Flag{FlagLowRating},
+		},
+		{
+			name:              "out of range ratings are ignored",
+			ratings:           []int{0, 5, 6},
+			wantAverageRating: 5,
+			wantScore:         1,
+		},
+		{
+			name:                 "every rate lowers the score",
+			events:               slices.Concat(repeat(Accepted, 4), repeat(Declined, 4), repeat(Cancelled, 1)),
+			ratings:              []int{4, 4},
+			wantAcceptanceRate:   0.5,
+			wantCancellationRate: 0.25,
+			wantAverageRating:    4,
+			wantScore:            0.5 * 0.75 * 0.8,
+			wantFlags:            []Flag{FlagHighCancellation, FlagLowRating},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewTracker(testConfig())
+			for _, event := range tt.events {
+				tracker.Record("driver-1", event)
+			}
+			for _, stars := range tt.ratings {
+				tracker.RecordRating("driver-1", stars)
+			}
+
+			got := tracker.Stats("driver-1")
+			if !almostEqual(got.AcceptanceRate, tt.wantAcceptanceRate) ||
+				!almostEqual(got.CancellationRate, tt.wantCancellationRate) ||
+				!almostEqual(got.AverageRating, tt.wantAverageRating) {
+				t.Errorf("Stats() rates = %v, %v, %v, want %v, %v, %v",
+					got.AcceptanceRate, got.CancellationRate, got.AverageRating,
+					tt.wantAcceptanceRate, tt.wantCancellationRate, tt.wantAverageRating)
+			}
+			if !almostEqual(got.Score, tt.wantScore) {
+				t.Errorf("Stats() score = %v, want %v", got.Score, tt.wantScore)
+			}
+			if !slices.Equal(got.Flags, tt.wantFlags) {
+				t.Errorf("Stats() flags = %v, want %v", got.Flags, tt.wantFlags)
+			}
+		})
+	}
+}
+
+func TestStatsWindow(t *testing.T) {
+	config := testConfig()
+	config.Window = 3 * time.Hour
+	config.BucketSize = time.Hour
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(config)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Record("driver-1", Declined)
+	now = now.Add(time.Hour)
+	tracker.Record("driver-1", Accepted)
+	tracker.RecordRating("driver-1", 4)
+
+	if got := tracker.Stats("driver-1"); got.Declined != 1 || got.Accepted != 1 || got.Ratings != 1 {
+		t.Fatalf("Stats() = %+v, want 1 decline, 1 acceptance and 1 rating", got)
+	}
+
+	// The decline falls out of the window first
+	now = now.Add(2 * time.Hour)
+	if got := tracker.Stats("driver-1"); got.Declined != 0 || got.Accepted != 1 || got.AcceptanceRate != 1 {
+		t.Fatalf("Stats() after the first bucket expired = %+v, want only the acceptance", got)
+	}
+
+	now = now.Add(time.Hour)
+	if got := tracker.Stats("driver-1"); got.Accepted != 0 || got.Ratings != 0 || got.Score != 1 {
+		t.Fatalf("Stats() after the window = %+v, want empty stats", got)
+	}
+}
+
+func TestRecordReportsFlagChanges(t *testing.T) {
+	tracker := NewTracker(testConfig())
+
+	for range 3 {
+		if got := tracker.Record("driver-1", Declined); got != nil {
+			t.Fatalf("Record() below MinAnswers = %+v, want nil", got)
+		}
+	}
+
+	got := tracker.Record("driver-1", Declined)
+	if got == nil || !slices.Equal(got.Flags, []Flag{FlagLowAcceptance}) {
+		t.Fatalf("Record() reaching MinAnswers = %+v, want low acceptance flag", got)
+	}
+
+	// Still flagged, nothing changed
+	if got := tracker.Record("driver-1", Accepted); got != nil {
+		t.Fatalf("Record() of a flagged driver = %+v, want nil", got)
+	}
+
+	for range 3 {
+		got = tracker.Record("driver-1", Accepted)
+	}
+	if got == nil || got.Flagged() {
+		t.Fatalf("Record() back above MinAcceptanceRate = %+v, want cleared flags", got)
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"ride-sharing/services/driver-service/internal/geoindex"
+	"time"
+
+	pb "ride-sharing/shared/proto/driver"
+
+	"github.com/mmcloughlin/geohash"
+)
+
+var (
+	ErrInvalidLocation = errors.New("invalid location")
+	// ErrLocationRejected is returned for updates of offline drivers, that come too fast or imply an impossible speed
+	ErrLocationRejected = errors.New("location update rejected")
+)
+
+// LocationConfig controls how the location updates of drivers are accepted
+type LocationConfig struct {
+	// MinInterval is the minimum time between two accepted updates of a driver
+	MinInterval time.Duration
+	// MaxSpeedMetersPerSecond rejects jumps no car could make, zero disables the check
+	MaxSpeedMetersPerSecond float64
+	// StaleAfter is how long a driver can go without an update before it stops being offered trips
+	StaleAfter time.Duration
+	// SweepInterval is how often drivers are checked for staleness
+	SweepInterval time.Duration
+	// AvailabilityGeohashPrecision is how much a driver has to move for its availability to be published again
+	AvailabilityGeohashPrecision int
+}
+
+func DefaultLocationConfig() *LocationConfig {
+	return &LocationConfig{
+		MinInterval:                  time.Second,
+		MaxSpeedMetersPerSecond:      70,
+		StaleAfter:                   2 * time.Minute,
+		SweepInterval:                15 * time.Second,
+		AvailabilityGeohashPrecision: 6,
+	}
+}
+
+// LocationUpdate is the outcome of an accepted location update.
+type LocationUpdate struct {
+	Driver *pb.Driver
+	// AvailabilityChanged is set when the driver came back from being stale
+	// or moved to another area while available, so its availability has to be published again
+	AvailabilityChanged bool
+}
+
+// UpdateLocation moves the driver to the reported position.
+func (s *Service) UpdateLocation(driverID string, lat, lon float64, recordedAt time.Time) (*LocationUpdate, error) {
+	if math.IsNaN(lat) || math.IsNaN(lon) || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("%w: %f, %f", ErrInvalidLocation, lat, lon)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	driver, ok := s.drivers[driverID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDriverNotFound, driverID)
+	}
+
+	if driver.State == DriverStateOffline {
+		return nil, fmt.Errorf("%w: %s is offline", ErrLocationRejected, driverID)
+	}
+
+	// The first report replaces the position given at registration,
+	// and a driver coming back from being stale may be anywhere
+	if !driver.LocatedAt.IsZero() && !driver.Stale {
+		elapsed := recordedAt.Sub(driver.LocatedAt)
+		if elapsed < s.location.MinInterval {
+			return nil, fmt.Errorf("%w: %s reported again after %s", ErrLocationRejected, driverID, elapsed)
+		}
+
+		current := driver.Driver.Location
+		if s.location.MaxSpeedMetersPerSecond > 0 {
+			distance := geoindex.Distance(current.Latitude, current.Longitude, lat, lon)
+			if speed := distance / elapsed.Seconds(); speed > s.location.MaxSpeedMetersPerSecond {
+				return nil, fmt.Errorf("%w: %s moved %.0fm at %.0fm/s", ErrLocationRejected, driverID, distance, speed)
+			}
+		}
+	}
+
+	previousGeohash := driver.Driver.Geohash
+	wasStale := driver.Stale
+
+	driver.Driver.Location = &pb.Location{Latitude: lat, Longitude: lon}
+	driver.Driver.Geohash = geohash.Encode(lat, lon)
+	driver.LastSeenAt = recordedAt
+	driver.LocatedAt = recordedAt
+	driver.Stale = false
+
+	s.syncIndexLocked(driverID, driver)
+	if !driver.isAvailable() {
+		return &LocationUpdate{Driver: driver.snapshot()}, nil
+	}
+
+	return &LocationUpdate{
+		Driver:              driver.snapshot(),
+		AvailabilityChanged: wasStale || s.geohashArea(previousGeohash) != s.geohashArea(driver.Driver.Geohash),
+	}, nil
+}
+
+// MarkStaleDrivers stops offering trips to the drivers not heard from for StaleAfter.
+// It returns the drivers that stopped being available.
+func (s *Service) MarkStaleDrivers(now time.Time) []*pb.Driver {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var unavailable []*pb.Driver
+	for id, driver := range s.drivers {
+		if driver.Stale || driver.State == DriverStateOffline || now.Sub(driver.LastSeenAt) < s.location.StaleAfter {
+			continue
+		}
+
+		wasAvailable := driver.isAvailable()
+		driver.Stale = true
+		s.syncIndexLocked(id, driver)
+
+		if wasAvailable {
+			unavailable = append(unavailable, driver.snapshot())
+		}
+	}
+
+	return unavailable
+}
+
+func (s *Service) geohashArea(hash string) string {
+	if precision := s.location.AvailabilityGeohashPrecision; precision > 0 && len(hash) > precision {
+		return hash[:precision]
+	}
+
+	return hash
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// locationConsumer applies the positions drivers report through the gateway.
+type locationConsumer struct {
+	rabbitmq  *messaging.Rabbitmq
+	service   *Service
+	publisher *driverEventPublisher
+}
+
+func NewLocationConsumer(rabbitmq *messaging.Rabbitmq, service *Service, publisher *driverEventPublisher) *locationConsumer {
+	return &locationConsumer{
+		rabbitmq:  rabbitmq,
+		service:   service,
+		publisher: publisher,
+	}
+}
+
+func (c *locationConsumer) Listen() error {
+	return c.rabbitmq.ConsumeMessages(messaging.DriverLocationQueue, func(ctx context.Context, msg amqp.Delivery) error {
+		var message contracts.AmqpMessage
+		if err := json.Unmarshal(msg.Body, &message); err != nil {
+			log.Printf("Failed to unmarshal message: %v", err)
+			return err
+		}
+
+		var payload messaging.DriverLocationData
+		if err := json.Unmarshal(message.Data, &payload); err != nil {
+			log.Printf("Failed to unmarshal message: %v", err)
+			return err
+		}
+
+		update, err := c.service.UpdateLocation(payload.DriverID, payload.Latitude, payload.Longitude, payload.RecordedAt)
+		if err != nil {
+			// Retrying a rejected update would not make it valid
+			if errors.Is(err, ErrDriverNotFound) || errors.Is(err, ErrInvalidLocation) || errors.Is(err, ErrLocationRejected) {
+				log.Printf("Ignoring location update: %v", err)
+				return nil
+			}
+			return err
+		}
+
+		if update.AvailabilityChanged {
+			return c.publisher.PublishAvailability(ctx, update.Driver, true)
+		}
+
+		return nil
+	})
+}
+
+// RunStaleDriverSweeper marks the drivers that stopped reporting their location unavailable, until ctx is done.
+func RunStaleDriverSweeper(ctx context.Context, service *Service, publisher *driverEventPublisher) {
+	ticker := time.NewTicker(service.location.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, driver := range service.MarkStaleDrivers(now) {
+				log.Printf("Driver %s stopped reporting its location, marking it unavailable", driver.Id)
+
+				if err := publisher.PublishAvailability(ctx, driver, false); err != nil {
+					log.Printf("Failed to publish availability of driver %s: %v", driver.Id, err)
+				}
+			}
+		}
+	}
+}
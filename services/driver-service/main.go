@@ -5,10 +5,17 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"ride-sharing/services/driver-service/internal/geoindex"
+	"ride-sharing/services/driver-service/internal/registry"
+	"ride-sharing/services/driver-service/internal/stats"
+	"ride-sharing/shared/auth"
+	"ride-sharing/shared/db"
 	"ride-sharing/shared/env"
 	"ride-sharing/shared/messaging"
 	"ride-sharing/shared/tracing"
+	"strings"
 	"syscall"
+	"time"
 
 	"golang.org/x/net/context"
 	grpcserver "google.golang.org/grpc"
@@ -54,19 +61,114 @@ func main() {
 	}
 	defer rabbitmq.Close()
 
-	service := NewService()
+	locationCfg := DefaultLocationConfig()
+	locationCfg.MinInterval = time.Duration(env.GetInt("DRIVER_LOCATION_MIN_INTERVAL_MS", int(locationCfg.MinInterval.Milliseconds()))) * time.Millisecond
+	locationCfg.MaxSpeedMetersPerSecond = env.GetFloat("DRIVER_LOCATION_MAX_SPEED", locationCfg.MaxSpeedMetersPerSecond)
+	locationCfg.StaleAfter = time.Duration(env.GetInt("DRIVER_STALE_AFTER_SECONDS", int(locationCfg.StaleAfter.Seconds()))) * time.Second
+
+	var profiles registry.Repository
+	switch backend := env.GetString("DRIVER_REGISTRY_BACKEND", "memory"); backend {
+	case "memory":
+		profiles = registry.NewInmemRepository()
+	case "mongo":
+		mongoClient, err := db.NewMongoClient(ctx, db.NewMongoDefaultConfig())
+		if err != nil {
+			log.Fatalf("Failed to connect to MongoDB: %v", err)
+		}
+		defer mongoClient.Disconnect(ctx)
+
+		profiles = registry.NewMongoRepository(db.GetDatabase(mongoClient, db.NewMongoDefaultConfig()))
+	default:
+		log.Fatalf("Unknown driver registry backend: %q", backend)
+	}
+
+	if driversFile := env.GetString("DRIVERS_FILE", ""); driversFile != "" {
+		seeded, err := registry.SeedFromFile(ctx, profiles, driversFile)
+		if err != nil {
+			log.Fatalf("Failed to seed driver profiles: %v", err)
+		}
+		log.Printf("Seeded %d driver profiles from %s", seeded, driversFile)
+	}
+
+	service := NewService(geoindex.New(geoindex.DefaultCellSizeDegrees), locationCfg, profiles)
+	publisher := NewDriverEventPublisher(rabbitmq)
 
 	// starting the grpc server
-	grpcServer := grpcserver.NewServer(tracing.WithTracingInterceptors()...)
-	NewGrpcHandler(grpcServer, service)
+	// Handlers check requests against the identity of the user the gateway called on behalf of
+	grpcServer := grpcserver.NewServer(append(tracing.WithTracingInterceptors(), auth.ServerOptions()...)...)
+	nearbyCfg := DefaultNearbyConfig()
+	nearbyCfg.UpdateInterval = time.Duration(env.GetInt("NEARBY_DRIVERS_UPDATE_INTERVAL_MS", int(nearbyCfg.UpdateInterval.Milliseconds()))) * time.Millisecond
+	nearbyCfg.PrecisionMeters = env.GetFloat("NEARBY_DRIVERS_PRECISION_METERS", nearbyCfg.PrecisionMeters)
+	nearbyCfg.MaxDrivers = env.GetInt("NEARBY_DRIVERS_MAX", nearbyCfg.MaxDrivers)
+	nearbyCfg.MaxRadiusMeters = env.GetFloat("NEARBY_DRIVERS_MAX_RADIUS_METERS", nearbyCfg.MaxRadiusMeters)
+
+	statsCfg := stats.DefaultConfig()
+	statsCfg.Window = time.Duration(env.GetInt("DRIVER_STATS_WINDOW_HOURS", int(statsCfg.Window.Hours()))) * time.Hour
+	statsCfg.BucketSize = time.Duration(env.GetInt("DRIVER_STATS_BUCKET_MINUTES", int(statsCfg.BucketSize.Minutes()))) * time.Minute
+	statsCfg.MinAnswers = env.GetInt("DRIVER_STATS_MIN_ANSWERS", statsCfg.MinAnswers)
+	statsCfg.MinAcceptanceRate = env.GetFloat("DRIVER_STATS_MIN_ACCEPTANCE_RATE", statsCfg.MinAcceptanceRate)
+	statsCfg.MinAccepted = env.GetInt("DRIVER_STATS_MIN_ACCEPTED", statsCfg.MinAccepted)
+	statsCfg.MaxCancellationRate = env.GetFloat("DRIVER_STATS_MAX_CANCELLATION_RATE", statsCfg.MaxCancellationRate)
+	statsCfg.MinRatings = env.GetInt("DRIVER_STATS_MIN_RATINGS", statsCfg.MinRatings)
+	statsCfg.MinRating = env.GetFloat("DRIVER_STATS_MIN_RATING", statsCfg.MinRating)
+
+	driverStats := stats.NewTracker(statsCfg)
+
+	NewGrpcHandler(grpcServer, service, publisher, nearbyCfg, driverStats)
+
+	dispatchCfg := DefaultDispatchConfig()
+	dispatchCfg.RadiusMeters = float64(env.GetInt("DISPATCH_RADIUS_METERS", int(dispatchCfg.RadiusMeters)))
+	dispatchCfg.CandidatesPerRound = env.GetInt("DISPATCH_CANDIDATES_PER_ROUND", dispatchCfg.CandidatesPerRound)
+	dispatchCfg.OfferTimeout = time.Duration(env.GetInt("DISPATCH_OFFER_TIMEOUT_SECONDS", int(dispatchCfg.OfferTimeout.Seconds()))) * time.Second
+	dispatchCfg.MaxRounds = env.GetInt("DISPATCH_MAX_ROUNDS", dispatchCfg.MaxRounds)
+	dispatchCfg.MaxDuration = time.Duration(env.GetInt("DISPATCH_MAX_DURATION_SECONDS", int(dispatchCfg.MaxDuration.Seconds()))) * time.Second
+	dispatchCfg.RoundInterval = time.Duration(env.GetInt("DISPATCH_ROUND_INTERVAL_SECONDS", int(dispatchCfg.RoundInterval.Seconds()))) * time.Second
+	dispatchCfg.QualityWeight = env.GetFloat("DISPATCH_QUALITY_WEIGHT", dispatchCfg.QualityWeight)
+
+	dispatcher := NewDispatcher(ctx, rabbitmq, service, publisher, dispatchCfg, driverStats)
+
+	batchCfg := DefaultBatchConfig()
+	for _, area := range strings.Split(env.GetString("BATCH_MATCHING_AREAS", ""), ",") {
+		if area = strings.TrimSpace(area); area != "" {
+			batchCfg.Areas[area] = true
+		}
+	}
+	batchCfg.Window = time.Duration(env.GetInt("BATCH_MATCHING_WINDOW_MS", int(batchCfg.Window.Milliseconds()))) * time.Millisecond
+	batchCfg.CandidatesPerTrip = env.GetInt("BATCH_MATCHING_CANDIDATES_PER_TRIP", batchCfg.CandidatesPerTrip)
+	batchCfg.AverageSpeedKmh = env.GetFloat("BATCH_MATCHING_AVERAGE_SPEED_KMH", batchCfg.AverageSpeedKmh)
+
+	batchDispatcher := NewBatchDispatcher(ctx, service, dispatcher, batchCfg, dispatchCfg.RadiusMeters)
 
-	consumer := NewTripConsumer(rabbitmq, service)
+	consumer := NewTripConsumer(rabbitmq, batchDispatcher)
 	go func() {
 		if err := consumer.Listen(); err != nil {
 			log.Fatalf("failed to listen to the message: %v", err)
 		}
 	}()
 
+	statusConsumer := NewTripStatusConsumer(rabbitmq, service, publisher, dispatcher, driverStats)
+	go func() {
+		if err := statusConsumer.Listen(); err != nil {
+			log.Fatalf("failed to listen to the message: %v", err)
+		}
+	}()
+
+	responseConsumer := NewDriverResponseConsumer(rabbitmq, service, publisher, dispatcher, driverStats)
+	go func() {
+		if err := responseConsumer.Listen(); err != nil {
+			log.Fatalf("failed to listen to the message: %v", err)
+		}
+	}()
+
+	locationConsumer := NewLocationConsumer(rabbitmq, service, publisher)
+	go func() {
+		if err := locationConsumer.Listen(); err != nil {
+			log.Fatalf("failed to listen to the message: %v", err)
+		}
+	}()
+
+	go RunStaleDriverSweeper(ctx, service, publisher)
+
 	log.Printf("Starting gRPC server Driver service on port %s", lis.Addr())
 
 	go func() {
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"ride-sharing/services/driver-service/internal/geoindex"
+	"time"
+
+	pb "ride-sharing/shared/proto/driver"
+)
+
+var ErrInvalidArea = errors.New("invalid area")
+
+const metersPerDegree = math.Pi / 180 * 6371000
+
+// NearbyConfig controls what riders watching the drivers around them get to see
+type NearbyConfig struct {
+	// UpdateInterval is the time between two updates of a watch
+	UpdateInterval time.Duration
+	// PrecisionMeters is the grid positions are snapped to, so riders can't follow a driver closely
+	PrecisionMeters float64
+	// MaxDrivers caps the drivers of a watch, the ones closest to the center of the area are kept
+	MaxDrivers int
+	// MaxRadiusMeters is the largest radius that can be watched, viewports can't be bigger than its circle
+	MaxRadiusMeters float64
+}
+
+func DefaultNearbyConfig() *NearbyConfig {
+	return &NearbyConfig{
+		UpdateInterval:  3 * time.Second,
+		PrecisionMeters: 150,
+		MaxDrivers:      50,
+		MaxRadiusMeters: 10000,
+	}
+}
+
+// NearbyDrivers returns the available drivers inside the bounds, and within radiusMeters of the center if it is positive.
+func (s *Service) NearbyDrivers(bounds geoindex.Bounds, center *pb.Location, radiusMeters float64, packageSlug string, limit int) []geoindex.Position {
+	positions := s.available.Within(bounds, packageSlug, limit)
+	if radiusMeters <= 0 {
+		return positions
+	}
+
+	inside := positions[:0]
+	for _, p := range positions {
+		if geoindex.Distance(center.Latitude, center.Longitude, p.Latitude, p.Longitude) <= radiusMeters {
+			inside = append(inside, p)
+		}
+	}
+
+	return inside
+}
+
+// nearbyWatch turns the drivers around an area into the updates of one stream.
+// Drivers are known by a pseudonym that only lasts as long as the stream and their positions are coarsened.
+type nearbyWatch struct {
+	config       *NearbyConfig
+	bounds       geoindex.Bounds
+	center       *pb.Location
+	radiusMeters float64
+	packageSlug  string
+	key          []byte
+	// sent is the last position sent of every driver in the area, by pseudonym
+	sent    map[string]*pb.NearbyDriver
+	started bool
+}
+
+func newNearbyWatch(req *pb.WatchNearbyDriversRequest, config *NearbyConfig) (*nearbyWatch, error) {
+	watch := &nearbyWatch{
+		config:      config,
+		packageSlug: req.GetPackageSlug(),
+		key:         make([]byte, 32),
+		sent:        make(map[string]*pb.NearbyDriver),
+	}
+
+	if _, err := rand.Read(watch.key); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case req.GetViewport() != nil:
+		sw, ne := req.GetViewport().GetSouthWest(), req.GetViewport().GetNorthEast()
+		if !validLocation(sw) || !validLocation(ne) || sw.Latitude > ne.Latitude || sw.Longitude > ne.Longitude {
+			return nil, fmt.Errorf("%w: viewport must go from its south west to its north east corner", ErrInvalidArea)
+		}
+
+		halfDiagonal := geoindex.Distance(sw.Latitude, sw.Longitude, ne.Latitude, ne.Longitude) / 2
+		if halfDiagonal > config.MaxRadiusMeters {
+			return nil, fmt.Errorf("%w: viewport is larger than %.0fm across", ErrInvalidArea, 2*config.MaxRadiusMeters)
+		}
+
+		watch.bounds = geoindex.Bounds{
+			MinLatitude:  sw.Latitude,
+			MinLongitude: sw.Longitude,
+			MaxLatitude:  ne.Latitude,
+			MaxLongitude: ne.Longitude,
+		}
+
+	case req.GetCenter() != nil:
+		center := req.GetCenter()
+		if !validLocation(center) {
+			return nil, fmt.Errorf("%w: center %f, %f", ErrInvalidArea, center.Latitude, center.Longitude)
+		}
+
+		radius := req.GetRadiusMeters()
+		if radius <= 0 || radius > config.MaxRadiusMeters {
+			return nil, fmt.Errorf("%w: radius must be positive and at most %.0fm", ErrInvalidArea, config.MaxRadiusMeters)
+		}
+
+		watch.bounds = geoindex.BoundsAround(center.Latitude, center.Longitude, radius)
+		watch.center = center
+		watch.radiusMeters = radius
+
+	default:
+		return nil, fmt.Errorf("%w: either a viewport or a center is required", ErrInvalidArea)
+	}
+
+	return watch, nil
+}
+
+func validLocation(l *pb.Location) bool {
+	return l != nil && !math.IsNaN(l.Latitude) && !math.IsNaN(l.Longitude) &&
+		l.Latitude >= -90 && l.Latitude <= 90 && l.Longitude >= -180 && l.Longitude <= 180
+}
+
+// next returns what changed since the previous update, nil if nothing did.
+// The first update is always returned, as a snapshot.
+func (w *nearbyWatch) next(service *Service) *pb.NearbyDriversUpdate {
+	positions := service.NearbyDrivers(w.bounds, w.center, w.radiusMeters, w.packageSlug, w.config.MaxDrivers)
+
+	update := &pb.NearbyDriversUpdate{Snapshot: !w.started}
+	w.started = true
+
+	current := make(map[string]bool, len(positions))
+	for _, p := range positions {
+		id := w.pseudonym(p.ID)
+		current[id] = true
+
+		driver := &pb.NearbyDriver{
+			Id:          id,
+			PackageSlug: p.PackageSlug,
+			Location:    w.coarsen(p.Latitude, p.Longitude),
+		}
+
+		// Drivers moving within their grid square don't need to be sent again
+		if previous, ok := w.sent[id]; ok &&
+			previous.Location.Latitude == driver.Location.Latitude && previous.Location.Longitude == driver.Location.Longitude {
+			continue
+		}
+
+		w.sent[id] = driver
+		update.Upserted = append(update.Upserted, driver)
+	}
+
+	for id := range w.sent {
+		if !current[id] {
+			delete(w.sent, id)
+			update.Removed = append(update.Removed, id)
+		}
+	}
+
+	if !update.Snapshot && len(update.Upserted) == 0 && len(update.Removed) == 0 {
+		return nil
+	}
+
+	return update
+}
+
+func (w *nearbyWatch) pseudonym(driverID string) string {
+	mac := hmac.New(sha256.New, w.key)
+	mac.Write([]byte(driverID))
+
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// coarsen snaps the position to the center of its square of the PrecisionMeters grid.
+func (w *nearbyWatch) coarsen(lat, lon float64) *pb.Location {
+	if w.config.PrecisionMeters <= 0 {
+		return &pb.Location{Latitude: lat, Longitude: lon}
+	}
+
+	latStep := w.config.PrecisionMeters / metersPerDegree
+	// Longitude steps are sized at the area's latitude so the grid is the same for every driver of the stream
+	lonStep := w.config.PrecisionMeters / (metersPerDegree * math.Max(math.Cos((w.bounds.MinLatitude+w.bounds.MaxLatitude)/2*math.Pi/180), 1e-6))
+
+	return &pb.Location{
+		Latitude:  (math.Floor(lat/latStep) + 0.5) * latStep,
+		Longitude: (math.Floor(lon/lonStep) + 0.5) * lonStep,
+	}
+}
@@ -1,80 +1,386 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	math "math/rand/v2"
+	"ride-sharing/services/driver-service/internal/geoindex"
+	"ride-sharing/services/driver-service/internal/registry"
+	"ride-sharing/services/driver-service/internal/simulation"
 	pb "ride-sharing/shared/proto/driver"
-	"ride-sharing/shared/util"
 	"sync"
+	"time"
 
 	"github.com/mmcloughlin/geohash"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrDriverNotFound = errors.New("driver not found")
+	// ErrInvalidDriverTransition is returned when the driver is not in a state allowing the change, e.g. offering a trip to a busy driver
+	ErrInvalidDriverTransition = errors.New("invalid driver state transition")
+)
+
+// DriverState is where a driver stands in the dispatch flow:
+//
+//	offline -> available <-> offered -> on_trip -> available -> offline
+type DriverState string
+
+const (
+	DriverStateOffline   DriverState = "offline"
+	DriverStateAvailable DriverState = "available"
+	DriverStateOffered   DriverState = "offered"
+	DriverStateOnTrip    DriverState = "on_trip"
 )
 
 type Service struct {
-	drivers []*driverInMap
-	mu      sync.RWMutex
+	drivers map[string]*driverInMap
+	// available indexes the position of the drivers that are free to take a trip
+	available *geoindex.Index
+	location  *LocationConfig
+	profiles  registry.Repository
+	mu        sync.RWMutex
 }
 
 type driverInMap struct {
 	Driver *pb.Driver
+	State  DriverState
+	// TripID is the trip the driver was offered or is driving, empty otherwise
+	TripID string
+	// LastSeenAt is when the driver registered or last reported its location
+	LastSeenAt time.Time
+	// LocatedAt is when the driver last reported its location, zero until it does
+	LocatedAt time.Time
+	// Stale drivers stopped reporting their location and are not offered trips until they do again
+	Stale bool
+}
+
+// DriverStatus is a snapshot of a driver and its state.
+type DriverStatus struct {
+	Driver     *pb.Driver
+	State      DriverState
+	TripID     string
+	Stale      bool
+	LastSeenAt time.Time
 }
 
-func NewService() *Service {
+func (s *DriverStatus) ToProto() *pb.GetDriverResponse {
+	return &pb.GetDriverResponse{
+		Driver:     s.Driver,
+		State:      string(s.State),
+		TripID:     s.TripID,
+		Stale:      s.Stale,
+		LastSeenAt: s.LastSeenAt.Unix(),
+	}
+}
+
+// isAvailable must be called with the lock held.
+func (d *driverInMap) isAvailable() bool {
+	return d.State == DriverStateAvailable && !d.Stale
+}
+
+// status must be called with the lock held.
+func (d *driverInMap) status() *DriverStatus {
+	return &DriverStatus{
+		Driver:     d.snapshot(),
+		State:      d.State,
+		TripID:     d.TripID,
+		Stale:      d.Stale,
+		LastSeenAt: d.LastSeenAt,
+	}
+}
+
+// snapshot returns a copy of the driver that can be used once the lock is released.
+func (d *driverInMap) snapshot() *pb.Driver {
+	return proto.Clone(d.Driver).(*pb.Driver)
+}
+
+func NewService(index *geoindex.Index, location *LocationConfig, profiles registry.Repository) *Service {
+	if location == nil {
+		location = DefaultLocationConfig()
+	}
+
 	return &Service{
-		drivers: make([]*driverInMap, 0),
+		drivers:   make(map[string]*driverInMap),
+		available: index,
+		location:  location,
+		profiles:  profiles,
 	}
 }
 
-func (s *Service) FindAvailableDrivers(packageType string) []string {
-	var matchingDrivers []string
+// FindAvailableDrivers returns up to limit free drivers of the package within radiusMeters of the pickup,
+// closest first. exclude, if set, skips drivers such as the ones already offered the trip.
+func (s *Service) FindAvailableDrivers(packageType string, pickup *pb.Location, radiusMeters float64, limit int, exclude func(driverID string) bool) []geoindex.Result {
+	return s.available.Nearest(geoindex.Query{
+		Latitude:     pickup.Latitude,
+		Longitude:    pickup.Longitude,
+		PackageSlug:  packageType,
+		Limit:        limit,
+		RadiusMeters: radiusMeters,
+		Exclude:      exclude,
+	})
+}
+
+func (s *Service) GetDriver(driverID string) (*DriverStatus, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
+	driver, ok := s.drivers[driverID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDriverNotFound, driverID)
+	}
+
+	return driver.status(), nil
+}
+
+// ListAvailableDrivers returns a snapshot of the drivers free to take a trip.
+func (s *Service) ListAvailableDrivers() []*pb.Driver {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	drivers := make([]*pb.Driver, 0, len(s.drivers))
 	for _, driver := range s.drivers {
-		if driver.Driver.PackageSlug == packageType {
-			matchingDrivers = append(matchingDrivers, driver.Driver.Id)
+		if driver.isAvailable() {
+			drivers = append(drivers, driver.snapshot())
 		}
 	}
 
-	for len(matchingDrivers) == 0 {
-		return []string{}
+	return drivers
+}
+
+// RegisterDriver brings the driver online with a vehicle eligible for the package.
+// It fails for drivers without an approved profile in the registry.
+// A driver reconnecting while still online or in the middle of a trip keeps its state.
+func (s *Service) RegisterDriver(ctx context.Context, driverId string, packageSlug string) (*DriverStatus, error) {
+	profile, err := s.profiles.GetProfile(ctx, driverId)
+	if err != nil {
+		return nil, err
 	}
 
-	return matchingDrivers
-}
+	if err := profile.CanDrive(time.Now()); err != nil {
+		return nil, err
+	}
+
+	vehicle, err := profile.VehicleFor(packageSlug)
+	if err != nil {
+		return nil, err
+	}
 
-func (s *Service) RegisterDriver(driverId string, packageSlug string) (*pb.Driver, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	randomIndex := math.IntN(len(PredefinedRoutes))
-	randomRoute := PredefinedRoutes[randomIndex]
+	if driver, ok := s.drivers[driverId]; ok {
+		// The profile may have changed since the driver was last online
+		location, geohash := driver.Driver.Location, driver.Driver.Geohash
+		driver.Driver = profileToProto(profile, vehicle, packageSlug)
+		driver.Driver.Location, driver.Driver.Geohash = location, geohash
+
+		driver.LastSeenAt = time.Now()
+		driver.LocatedAt = time.Time{}
+		driver.Stale = false
+
+		if driver.State == DriverStateOffline {
+			driver.State = DriverStateAvailable
+			if driver.TripID != "" {
+				driver.State = DriverStateOnTrip
+			}
+		}
+
+		// The package may have changed, so the driver is indexed again
+		s.available.Remove(driverId)
+		s.syncIndexLocked(driverId, driver)
+
+		return driver.status(), nil
+	}
+
+	// Drivers start somewhere on a predefined route until they report their location
+	start := simulation.PredefinedRoutes[math.IntN(len(simulation.PredefinedRoutes))][0]
+
+	driver := &driverInMap{
+		Driver:     profileToProto(profile, vehicle, packageSlug),
+		State:      DriverStateAvailable,
+		LastSeenAt: time.Now(),
+	}
+	driver.Driver.Location = &pb.Location{Latitude: start[0], Longitude: start[1]}
+	driver.Driver.Geohash = geohash.Encode(start[0], start[1])
 
-	randomAvatar := util.GetRandomAvatar(randomIndex)
-	randomPlate := GenerateRandomPlate()
+	s.drivers[driverId] = driver
+	s.syncIndexLocked(driverId, driver)
 
-	// we can ignore this property for now, but it must be sent to the frontend.
-	geohash := geohash.Encode(randomRoute[0][0], randomRoute[0][1])
+	return driver.status(), nil
+}
 
-	driver := &pb.Driver{
-		Id:             driverId,
-		Geohash:        geohash,
-		Location:       &pb.Location{Latitude: randomRoute[0][0], Longitude: randomRoute[0][1]},
-		Name:           "Lando Norris",
+func profileToProto(profile *registry.Profile, vehicle *registry.Vehicle, packageSlug string) *pb.Driver {
+	return &pb.Driver{
+		Id:             profile.ID,
+		Name:           profile.Name,
+		ProfilePicture: profile.PhotoURL,
+		CarPlate:       vehicle.Plate,
 		PackageSlug:    packageSlug,
-		ProfilePicture: randomAvatar,
-		CarPlate:       randomPlate,
+		Vehicle: &pb.Vehicle{
+			Plate:  vehicle.Plate,
+			Make:   vehicle.Make,
+			Model:  vehicle.Model,
+			Colour: vehicle.Colour,
+			Seats:  int32(vehicle.Seats),
+		},
+	}
+}
+
+// UnregisterDriver takes the driver offline, returning it if it was available until now.
+// A driver going offline in the middle of a trip is still on it when it registers again.
+func (s *Service) UnregisterDriver(driverId string) *pb.Driver {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	driver, ok := s.drivers[driverId]
+	if !ok {
+		return nil
+	}
+
+	if driver.State == DriverStateOffered {
+		// The offer is lost with the driver's connection
+		driver.TripID = ""
+	}
+
+	return s.transitionLocked(driverId, driver, DriverStateOffline, driver.TripID)
+}
+
+// OfferTrip reserves an available driver for the trip while the driver decides whether to take it.
+// It returns the driver, or ErrInvalidDriverTransition if the driver is not available anymore.
+func (s *Service) OfferTrip(driverId string, tripID string) (*pb.Driver, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	driver, ok := s.drivers[driverId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDriverNotFound, driverId)
 	}
 
-	s.drivers = append(s.drivers, &driverInMap{Driver: driver})
+	if !driver.isAvailable() {
+		return nil, fmt.Errorf("%w: %s is %s", ErrInvalidDriverTransition, driverId, driver.State)
+	}
+
+	return s.transitionLocked(driverId, driver, DriverStateOffered, tripID), nil
+}
+
+// DeclineOffer makes a driver who turned down the trip available again.
+// It returns the driver if it became available, nil if it was not offered that trip.
+func (s *Service) DeclineOffer(driverId string, tripID string) *pb.Driver {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return driver, nil
+	driver, ok := s.drivers[driverId]
+	if !ok || driver.State != DriverStateOffered || driver.TripID != tripID {
+		return nil
+	}
+
+	return s.transitionLocked(driverId, driver, DriverStateAvailable, "")
 }
 
-func (s *Service) UnregisterDriver(driverId string) {
+// AcceptOffer puts the driver on the trip it was offered and returns the driver.
+// It returns ErrInvalidDriverTransition if the offer was withdrawn or expired meanwhile.
+func (s *Service) AcceptOffer(driverId string, tripID string) (*pb.Driver, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for i, driver := range s.drivers {
-		if driver.Driver.Id == driverId {
-			s.drivers = append(s.drivers[:i], s.drivers[i+1:]...)
+	driver, ok := s.drivers[driverId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDriverNotFound, driverId)
+	}
+
+	if driver.State != DriverStateOffered || driver.TripID != tripID {
+		return nil, fmt.Errorf("%w: %s was not offered trip %s", ErrInvalidDriverTransition, driverId, tripID)
+	}
+
+	s.transitionLocked(driverId, driver, DriverStateOnTrip, tripID)
+
+	return driver.snapshot(), nil
+}
+
+// AssignTrip marks the driver as busy with the given trip, once trip service assigned it.
+// It returns the driver if it was available until now, nil otherwise.
+func (s *Service) AssignTrip(driverId string, tripID string) *pb.Driver {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	driver, ok := s.drivers[driverId]
+	if !ok {
+		return nil
+	}
+
+	state := DriverStateOnTrip
+	if driver.State == DriverStateOffline {
+		// The trip is picked up again when the driver comes back online
+		state = DriverStateOffline
+	}
+
+	return s.transitionLocked(driverId, driver, state, tripID)
+}
+
+// ReleaseDriver makes the driver available again once the trip it was offered or driving is over.
+// It returns the released driver, or nil if it did not become available.
+func (s *Service) ReleaseDriver(driverId string, tripID string) *pb.Driver {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	driver, ok := s.drivers[driverId]
+	if !ok || driver.TripID != tripID {
+		return nil
+	}
+
+	state := DriverStateAvailable
+	if driver.State == DriverStateOffline {
+		state = DriverStateOffline
+	}
+
+	return s.transitionLocked(driverId, driver, state, "")
+}
+
+// WithdrawOffers releases the drivers still deciding on a trip that went away, e.g. was cancelled.
+// It returns the drivers that became available.
+func (s *Service) WithdrawOffers(tripID string) []*pb.Driver {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var released []*pb.Driver
+	for id, driver := range s.drivers {
+		if driver.State != DriverStateOffered || driver.TripID != tripID {
+			continue
+		}
+
+		if d := s.transitionLocked(id, driver, DriverStateAvailable, ""); d != nil {
+			released = append(released, d)
 		}
 	}
+
+	return released
+}
+
+// transitionLocked must be called with the lock held.
+// It moves the driver to state and returns the driver if its availability changed.
+func (s *Service) transitionLocked(driverId string, driver *driverInMap, state DriverState, tripID string) *pb.Driver {
+	wasAvailable := driver.isAvailable()
+
+	driver.State = state
+	driver.TripID = tripID
+	s.syncIndexLocked(driverId, driver)
+
+	if wasAvailable == driver.isAvailable() {
+		return nil
+	}
+
+	return driver.snapshot()
+}
+
+// syncIndexLocked must be called with the lock held, it keeps only available drivers in the index.
+func (s *Service) syncIndexLocked(driverId string, driver *driverInMap) {
+	if !driver.isAvailable() {
+		s.available.Remove(driverId)
+		return
+	}
+
+	location := driver.Driver.Location
+	s.available.Upsert(driverId, driver.Driver.PackageSlug, location.Latitude, location.Longitude)
 }
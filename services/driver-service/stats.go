@@ -0,0 +1,55 @@
+package main
+
+import (
+	"log"
+	"ride-sharing/services/driver-service/internal/stats"
+
+	pb "ride-sharing/shared/proto/driver"
+)
+
+// recordStat counts the event in the driver's stats.
+func recordStat(tracker *stats.Tracker, driverID string, event stats.Event) {
+	logFlagChange(tracker.Record(driverID, event))
+}
+
+// recordRating counts the rating a rider gave the driver in its stats.
+func recordRating(tracker *stats.Tracker, driverID string, stars int) {
+	logFlagChange(tracker.RecordRating(driverID, stars))
+}
+
+func logFlagChange(snapshot *stats.Snapshot) {
+	if snapshot == nil {
+		return
+	}
+
+	if snapshot.Flagged() {
+		log.Printf("Driver %s flagged %v: acceptance %.2f, cancellation %.2f, rating %.2f",
+			snapshot.DriverID, snapshot.Flags, snapshot.AcceptanceRate, snapshot.CancellationRate, snapshot.AverageRating)
+		return
+	}
+
+	log.Printf("Driver %s no longer flagged", snapshot.DriverID)
+}
+
+func driverStatsToProto(snapshot *stats.Snapshot, config *stats.Config) *pb.DriverStats {
+	flags := make([]string, len(snapshot.Flags))
+	for i, flag := range snapshot.Flags {
+		flags[i] = string(flag)
+	}
+
+	return &pb.DriverStats{
+		DriverID:         snapshot.DriverID,
+		Offers:           int32(snapshot.Offers),
+		Accepted:         int32(snapshot.Accepted),
+		Declined:         int32(snapshot.Declined),
+		TimedOut:         int32(snapshot.TimedOut),
+		Cancelled:        int32(snapshot.Cancelled),
+		Ratings:          int32(snapshot.Ratings),
+		AcceptanceRate:   snapshot.AcceptanceRate,
+		CancellationRate: snapshot.CancellationRate,
+		AverageRating:    snapshot.AverageRating,
+		Score:            snapshot.Score,
+		Flags:            flags,
+		WindowSeconds:    int64(config.Window.Seconds()),
+	}
+}
@@ -4,22 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"log"
-	"math/rand"
 	"ride-sharing/shared/contracts"
 	"ride-sharing/shared/messaging"
 
+	pb "ride-sharing/shared/proto/driver"
+	pbt "ride-sharing/shared/proto/trip"
+
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 type tripConsumer struct {
-	rabbitmq *messaging.Rabbitmq
-	service  *Service
+	rabbitmq   *messaging.Rabbitmq
+	dispatcher *batchDispatcher
 }
 
-func NewTripConsumer(rabbitmq *messaging.Rabbitmq, service *Service) *tripConsumer {
+func NewTripConsumer(rabbitmq *messaging.Rabbitmq, dispatcher *batchDispatcher) *tripConsumer {
 	return &tripConsumer{
-		rabbitmq: rabbitmq,
-		service:  service,
+		rabbitmq:   rabbitmq,
+		dispatcher: dispatcher,
 	}
 }
 
@@ -40,8 +42,8 @@ func (c *tripConsumer) Listen() error {
 		log.Printf("driver received message: %+v", payload)
 
 		switch msg.RoutingKey {
-		case contracts.TripEventCreated, contracts.TripEventDriverNotInterested:
-			return c.handleFindAndNotifyDrivers(ctx, payload)
+		case contracts.TripEventCreated:
+			return c.dispatcher.Dispatch(ctx, payload)
 		}
 
 		log.Printf("unknown trip event: %+v", payload)
@@ -50,40 +52,19 @@ func (c *tripConsumer) Listen() error {
 	})
 }
 
-func (c *tripConsumer) handleFindAndNotifyDrivers(ctx context.Context, payload messaging.TripEventData) error {
-	suitableIDs := c.service.FindAvailableDrivers(payload.Trip.SelectedFare.PackageSlug)
-
-	log.Printf("Found suitable drivers %v", len(suitableIDs))
-
-	if len(suitableIDs) == 0 {
-		// Notify the driver that no drivers are available
-		if err := c.rabbitmq.PublishMessage(ctx, contracts.TripEventNoDriversFound, contracts.AmqpMessage{
-			OwnerID: payload.Trip.UserID,
-		}); err != nil {
-			log.Printf("Failed to pusblish message to exchange: %v", err)
-			return err
-		}
-
-		return nil
+// tripPickup returns where the rider waits for the driver.
+func tripPickup(trip *pbt.Trip) *pb.Location {
+	if pickup := trip.GetPickup(); pickup != nil {
+		return &pb.Location{Latitude: pickup.Latitude, Longitude: pickup.Longitude}
 	}
 
-	// Get a random index from the matching drivers
-	randomIndex := rand.Intn(len(suitableIDs))
-	suitableDriverID := suitableIDs[randomIndex]
-
-	marshalledEvent, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-
-	// Notify the driver about a potential trip
-	if err := c.rabbitmq.PublishMessage(ctx, contracts.DriverCmdTripRequest, contracts.AmqpMessage{
-		OwnerID: suitableDriverID,
-		Data:    marshalledEvent,
-	}); err != nil {
-		log.Printf("Failed to pusblish message to exchange: %v", err)
-		return err
+	// Trips created before the pickup was part of the event start where their route does.
+	// Route coordinates hold OSRM's [longitude, latitude] pairs in (latitude, longitude).
+	geometry := trip.GetRoute().GetGeometry()
+	if len(geometry) == 0 || len(geometry[0].Coordinates) == 0 {
+		return nil
 	}
 
-	return nil
+	start := geometry[0].Coordinates[0]
+	return &pb.Location{Latitude: start.Longitude, Longitude: start.Latitude}
 }
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"ride-sharing/services/driver-service/internal/stats"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	pb "ride-sharing/shared/proto/trip"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// tripStatusConsumer keeps the driver pool in sync with the trips drivers are assigned to.
+type tripStatusConsumer struct {
+	rabbitmq   *messaging.Rabbitmq
+	service    *Service
+	publisher  *driverEventPublisher
+	dispatcher *dispatcher
+	stats      *stats.Tracker
+}
+
+func NewTripStatusConsumer(rabbitmq *messaging.Rabbitmq, service *Service, publisher *driverEventPublisher, dispatcher *dispatcher, driverStats *stats.Tracker) *tripStatusConsumer {
+	return &tripStatusConsumer{
+		rabbitmq:   rabbitmq,
+		service:    service,
+		publisher:  publisher,
+		dispatcher: dispatcher,
+		stats:      driverStats,
+	}
+}
+
+func (c *tripStatusConsumer) Listen() error {
+	return c.rabbitmq.ConsumeMessages(messaging.DriverTripStatusQueue, func(ctx context.Context, msg amqp.Delivery) error {
+		var tripEvent contracts.AmqpMessage
+		if err := json.Unmarshal(msg.Body, &tripEvent); err != nil {
+			log.Printf("Failed to unmarshal message: %v", err)
+			return err
+		}
+
+		switch msg.RoutingKey {
+		case contracts.TripEventDriverAssigned:
+			var trip pb.Trip
+			if err := json.Unmarshal(tripEvent.Data, &trip); err != nil {
+				log.Printf("Failed to unmarshal message: %v", err)
+				return err
+			}
+
+			c.dispatcher.Finish(trip.Id)
+
+			if driverID := trip.GetDriver().GetId(); driverID != "" {
+				if driver := c.service.AssignTrip(driverID, trip.Id); driver != nil {
+					return c.publisher.PublishAvailability(ctx, driver, false)
+				}
+			}
+		case contracts.TripEventCompleted:
+			var payload messaging.TripEventData
+			if err := json.Unmarshal(tripEvent.Data, &payload); err != nil {
+				log.Printf("Failed to unmarshal message: %v", err)
+				return err
+			}
+
+			if driverID := payload.Trip.GetDriver().GetId(); driverID != "" {
+				if driver := c.service.ReleaseDriver(driverID, payload.Trip.Id); driver != nil {
+					return c.publisher.PublishAvailability(ctx, driver, true)
+				}
+			}
+		case contracts.TripEventCancelled:
+			var payload messaging.TripCancelledData
+			if err := json.Unmarshal(tripEvent.Data, &payload); err != nil {
+				log.Printf("Failed to unmarshal message: %v", err)
+				return err
+			}
+
+			c.dispatcher.Finish(payload.Trip.Id)
+
+			// The event is published once per participant, releasing is idempotent
+			if driverID := payload.Trip.GetDriver().GetId(); driverID != "" {
+				// Only the copy of the driver counts, for the cancellation to be counted once
+				if payload.CancelledBy == "driver" && tripEvent.OwnerID == driverID {
+					recordStat(c.stats, driverID, stats.Cancelled)
+				}
+
+				if driver := c.service.ReleaseDriver(driverID, payload.Trip.Id); driver != nil {
+					log.Printf("Driver %s released from cancelled trip %s", driverID, payload.Trip.Id)
+					return c.publisher.PublishAvailability(ctx, driver, true)
+				}
+			}
+
+			// The trip may have been cancelled while a driver was deciding whether to take it
+			for _, driver := range c.service.WithdrawOffers(payload.Trip.Id) {
+				log.Printf("Offer of cancelled trip %s withdrawn from driver %s", payload.Trip.Id, driver.Id)
+				if err := c.publisher.PublishAvailability(ctx, driver, true); err != nil {
+					return err
+				}
+			}
+		case contracts.TripEventRated:
+			var payload messaging.TripRatedData
+			if err := json.Unmarshal(tripEvent.Data, &payload); err != nil {
+				log.Printf("Failed to unmarshal message: %v", err)
+				return err
+			}
+
+			recordRating(c.stats, payload.DriverID, payload.Rating)
+		default:
+			log.Printf("unknown trip event: %s", msg.RoutingKey)
+		}
+
+		return nil
+	})
+}
@@ -3,17 +3,27 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"ride-sharing/services/payment-service/internal/domain"
 	"ride-sharing/services/payment-service/internal/infrastructure/events"
+	"ride-sharing/services/payment-service/internal/infrastructure/grpc"
+	"ride-sharing/services/payment-service/internal/infrastructure/payout"
+	"ride-sharing/services/payment-service/internal/infrastructure/repository"
 	"ride-sharing/services/payment-service/internal/infrastructure/stripe"
 	"ride-sharing/services/payment-service/internal/service"
 	"ride-sharing/services/payment-service/pkg/types"
+	"ride-sharing/shared/auth"
+	"ride-sharing/shared/db"
 	"ride-sharing/shared/env"
 	"ride-sharing/shared/messaging"
 	"ride-sharing/shared/tracing"
+
+	grpcserver "google.golang.org/grpc"
 )
 
 var GrpcAddr = env.GetString("GRPC_ADDR", ":9004")
@@ -57,8 +67,44 @@ func main() {
 		return
 	}
 
+	var ledgerRepo domain.LedgerRepository
+	switch backend := env.GetString("LEDGER_BACKEND", "memory"); backend {
+	case "memory":
+		ledgerRepo = repository.NewInmemLedgerRepository()
+	case "mongo":
+		mongoClient, err := db.NewMongoClient(ctx, db.NewMongoDefaultConfig())
+		if err != nil {
+			log.Fatalf("Failed to connect to MongoDB: %v", err)
+		}
+		defer mongoClient.Disconnect(ctx)
+
+		ledgerRepo = repository.NewMongoLedgerRepository(db.GetDatabase(mongoClient, db.NewMongoDefaultConfig()))
+	default:
+		log.Fatalf("Unknown ledger backend: %q", backend)
+	}
+
+	var payoutProcessor domain.PayoutProcessor
+	switch processor := env.GetString("PAYOUT_PROCESSOR", "fake"); processor {
+	case "fake":
+		payoutProcessor = payout.NewFakeProcessor()
+	default:
+		log.Fatalf("Unknown payout processor: %q", processor)
+	}
+
+	ledgerCfg := types.DefaultLedgerConfig()
+	ledgerCfg.CommissionPercent = env.GetFloat("DRIVER_COMMISSION_PERCENT", ledgerCfg.CommissionPercent)
+	ledgerCfg.Currency = env.GetString("LEDGER_CURRENCY", ledgerCfg.Currency)
+	if tz := env.GetString("STATEMENT_TIMEZONE", ""); tz != "" {
+		location, err := time.LoadLocation(tz)
+		if err != nil {
+			log.Fatalf("Invalid statement time zone %q: %v", tz, err)
+		}
+		ledgerCfg.Location = location
+	}
+
 	paymentProcessor := stripe.NewStripeClient(stripeCfg)
-	svc := service.NewPaymentService(paymentProcessor)
+	svc := service.NewPaymentService(paymentProcessor, ledgerRepo)
+	ledger := service.NewLedgerService(ledgerRepo, payoutProcessor, ledgerCfg)
 
 	// RabbitMQ connection
 	rabbitmq, err := messaging.NewRebbitmq(rabbitMqURI)
@@ -70,9 +116,35 @@ func main() {
 	tripConsumer := events.NewTripConsumer(rabbitmq, svc)
 	go tripConsumer.Listen()
 
+	paymentConsumer := events.NewPaymentConsumer(rabbitmq, ledger)
+	go func() {
+		if err := paymentConsumer.Listen(); err != nil {
+			log.Fatalf("failed to listen to the message: %v", err)
+		}
+	}()
+
 	log.Println("Starting RabbitMQ connection")
 
+	lis, err := net.Listen("tcp", GrpcAddr)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	// Handlers check requests against the identity of the user the gateway called on behalf of
+	grpcServer := grpcserver.NewServer(append(tracing.WithTracingInterceptors(), auth.ServerOptions()...)...)
+	grpc.NewGRPCHandler(grpcServer, svc, ledger)
+
+	log.Printf("Starting gRPC server Payment service on port %s", lis.Addr())
+
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Printf("failed to serve: %v", err)
+			cancel()
+		}
+	}()
+
 	// Wait for shutdown signal
 	<-ctx.Done()
 	log.Println("Shutting down payment service...")
+	grpcServer.GracefulStop()
 }
@@ -2,14 +2,62 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"io"
+	"time"
 
 	"ride-sharing/services/payment-service/pkg/types"
+	"ride-sharing/shared/money"
+)
+
+var (
+	ErrIntentNotFound = errors.New("payment intent not found")
+	// ErrDuplicateEntry is returned when recording what the ledger already holds, e.g. a trip paid twice
+	ErrDuplicateEntry    = errors.New("ledger entry already recorded")
+	ErrInvalidLedgerArgs = errors.New("invalid ledger arguments")
+	// ErrNothingToPay is returned when paying out a driver without a positive balance
+	ErrNothingToPay = errors.New("nothing to pay out")
+	ErrNotPayer     = errors.New("user did not make the payment")
+	ErrNotPaid      = errors.New("payment has not succeeded yet")
 )
 
 type Service interface {
-	CreatePaymentSession(ctx context.Context, tripID, userID, driverID string, amount int64, currency string) (*types.PaymentIntent, error)
+	CreatePaymentSession(ctx context.Context, tripID, userID, driverID string, amount money.Money, description string, lineItems []types.LineItem) (*types.PaymentIntent, error)
+	// GetReceipt returns the paid intent of the trip, only to the rider who paid it
+	GetReceipt(ctx context.Context, tripID, userID string) (*types.PaymentIntent, error)
 }
 
 type PaymentProcessor interface {
-	CreatePaymentSession(ctx context.Context, amount int64, currency string, metadata map[string]string) (string, error)
+	// CreatePaymentSession charges amount, itemized by lineItems when they are given.
+	CreatePaymentSession(ctx context.Context, amount money.Money, description string, lineItems []types.LineItem, metadata map[string]string) (string, error)
+}
+
+// LedgerService keeps track of what drivers earn and are paid.
+type LedgerService interface {
+	// RecordTripPayment marks the payment of the trip as paid and credits its driver with their share of the fare
+	RecordTripPayment(ctx context.Context, tripID string) (*types.LedgerEntry, error)
+	RecordTip(ctx context.Context, tripID string, amount money.Money) (*types.LedgerEntry, error)
+	RecordAdjustment(ctx context.Context, driverID string, amount money.Money, reason string) (*types.LedgerEntry, error)
+	// GetStatement returns the statement of the period holding day
+	GetStatement(ctx context.Context, driverID string, period types.StatementPeriod, day time.Time) (*types.Statement, error)
+	ExportStatementCSV(statement *types.Statement, w io.Writer) error
+	// PayoutDriver pays the driver's whole balance out
+	PayoutDriver(ctx context.Context, driverID string) (*types.Payout, error)
+}
+
+type LedgerRepository interface {
+	SaveIntent(ctx context.Context, intent *types.PaymentIntent) error
+	GetIntentByTrip(ctx context.Context, tripID string) (*types.PaymentIntent, error)
+	// MarkIntentPaid sets when the latest intent of the trip was paid, unless it was already
+	MarkIntentPaid(ctx context.Context, tripID string, paidAt time.Time) error
+	// AddEntry returns ErrDuplicateEntry if an entry with the same ID was recorded already
+	AddEntry(ctx context.Context, entry *types.LedgerEntry) error
+	// ListEntries returns the entries of the driver created in [from, to), oldest first
+	ListEntries(ctx context.Context, driverID string, from, to time.Time) ([]*types.LedgerEntry, error)
+}
+
+// PayoutProcessor transfers money to drivers.
+type PayoutProcessor interface {
+	// Payout returns the reference of the transfer. reference identifies the payout, so retrying it doesn't pay twice.
+	Payout(ctx context.Context, driverID string, amount money.Money, reference string) (string, error)
 }
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// PaymentConsumer credits drivers in the earnings ledger once their trips are paid
+type PaymentConsumer struct {
+	rabbitmq *messaging.Rabbitmq
+	ledger   domain.LedgerService
+}
+
+func NewPaymentConsumer(rabbitmq *messaging.Rabbitmq, ledger domain.LedgerService) *PaymentConsumer {
+	return &PaymentConsumer{
+		rabbitmq: rabbitmq,
+		ledger:   ledger,
+	}
+}
+
+func (c *PaymentConsumer) Listen() error {
+	return c.rabbitmq.ConsumeMessages(messaging.PaymentLedgerQueue, func(ctx context.Context, msg amqp091.Delivery) error {
+		var message contracts.AmqpMessage
+		if err := json.Unmarshal(msg.Body, &message); err != nil {
+			log.Printf("Failed to unmarshal message: %v", err)
+			return err
+		}
+
+		var payload messaging.PaymentStatusUpdateData
+		if err := json.Unmarshal(message.Data, &payload); err != nil {
+			log.Printf("Failed to unmarshal payload: %v", err)
+			return err
+		}
+
+		entry, err := c.ledger.RecordTripPayment(ctx, payload.TripID)
+		switch {
+		case errors.Is(err, domain.ErrDuplicateEntry):
+			// The event was delivered again
+			log.Printf("Payment of trip %s is already in the ledger", payload.TripID)
+			return nil
+		case errors.Is(err, domain.ErrIntentNotFound), errors.Is(err, domain.ErrInvalidLedgerArgs):
+			// Retrying won't help, e.g. the session was created before the ledger existed
+			log.Printf("Cannot credit the driver of trip %s: %v", payload.TripID, err)
+			return nil
+		case err != nil:
+			return err
+		}
+
+		log.Printf("Credited driver %s with %s for trip %s", entry.DriverID, entry.Amount, payload.TripID)
+
+		return nil
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/services/payment-service/pkg/types"
 	"ride-sharing/shared/contracts"
 	"ride-sharing/shared/messaging"
 
@@ -58,8 +59,9 @@ func (c *TripConsumer) handleTripAccepted(ctx context.Context, payload messaging
 		payload.TripID,
 		payload.UserID,
 		payload.DriverID,
-		int64(payload.Amount),
-		payload.Currency,
+		payload.Amount,
+		payload.Description,
+		lineItems(payload.LineItems),
 	)
 	if err != nil {
 		log.Printf("Failed to create payment session: %v", err)
@@ -72,8 +74,7 @@ func (c *TripConsumer) handleTripAccepted(ctx context.Context, payload messaging
 	paymentPayload := messaging.PaymentEventSessionCreatedData{
 		TripID:    payload.TripID,
 		SessionID: paymentSession.StripeSessionID,
-		Amount:    float64(paymentSession.Amount) / 100.0, // Convert from cents to dollars
-		Currency:  paymentSession.Currency,
+		Amount:    paymentSession.Amount,
 	}
 
 	payloadBytes, err := json.Marshal(paymentPayload)
@@ -95,3 +96,16 @@ func (c *TripConsumer) handleTripAccepted(ctx context.Context, payload messaging
 	log.Printf("Published payment session created event for trip: %s", payload.TripID)
 	return nil
 }
+
+func lineItems(items []messaging.PaymentLineItem) []types.LineItem {
+	lineItems := make([]types.LineItem, len(items))
+	for i, item := range items {
+		lineItems[i] = types.LineItem{
+			Type:        item.Type,
+			Description: item.Description,
+			Amount:      item.Amount,
+		}
+	}
+
+	return lineItems
+}
@@ -0,0 +1,165 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/services/payment-service/pkg/types"
+	"ride-sharing/shared/auth"
+	"ride-sharing/shared/money"
+	pb "ride-sharing/shared/proto/payment"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type grpcHandler struct {
+	payments domain.Service
+	ledger   domain.LedgerService
+
+	pb.UnimplementedPaymentServiceServer
+}
+
+func NewGRPCHandler(server *grpc.Server, payments domain.Service, ledger domain.LedgerService) *grpcHandler {
+	handler := &grpcHandler{
+		payments: payments,
+		ledger:   ledger,
+	}
+
+	pb.RegisterPaymentServiceServer(server, handler)
+
+	return handler
+}
+
+func (h *grpcHandler) GetDriverStatement(ctx context.Context, req *pb.GetDriverStatementRequest) (*pb.DriverStatement, error) {
+	statement, err := h.statement(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return statement.ToProto(), nil
+}
+
+func (h *grpcHandler) ExportDriverStatement(ctx context.Context, req *pb.GetDriverStatementRequest) (*pb.ExportDriverStatementResponse, error) {
+	statement, err := h.statement(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := h.ledger.ExportStatementCSV(statement, &buf); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to export statement: %v", err)
+	}
+
+	return &pb.ExportDriverStatementResponse{
+		Filename: fmt.Sprintf("statement-%s-%s-%s.csv", statement.DriverID, statement.Period, statement.From.Format(time.DateOnly)),
+		Csv:      buf.Bytes(),
+	}, nil
+}
+
+func (h *grpcHandler) statement(ctx context.Context, req *pb.GetDriverStatementRequest) (*types.Statement, error) {
+	if err := auth.CheckUser(ctx, req.GetDriverID()); err != nil {
+		return nil, err
+	}
+
+	day := time.Now()
+	if req.GetDate() != "" {
+		parsed, err := time.Parse(time.DateOnly, req.GetDate())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid date %q, expected YYYY-MM-DD", req.GetDate())
+		}
+		// Noon keeps the day the same in any time zone the ledger is configured with
+		day = parsed.Add(12 * time.Hour)
+	}
+
+	period := types.StatementPeriod(req.GetPeriod())
+	if period == "" {
+		period = types.StatementWeekly
+	}
+
+	statement, err := h.ledger.GetStatement(ctx, req.GetDriverID(), period, day)
+	if err != nil {
+		return nil, status.Errorf(ledgerErrorCode(err), "failed to get statement: %v", err)
+	}
+
+	return statement, nil
+}
+
+func (h *grpcHandler) RecordTip(ctx context.Context, req *pb.RecordTipRequest) (*pb.LedgerEntry, error) {
+	if err := auth.CheckRole(ctx, auth.RoleRider, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	entry, err := h.ledger.RecordTip(ctx, req.GetTripID(), money.FromProto(req.GetAmount()))
+	if err != nil {
+		return nil, status.Errorf(ledgerErrorCode(err), "failed to record tip: %v", err)
+	}
+
+	return entry.ToProto(), nil
+}
+
+func (h *grpcHandler) RecordAdjustment(ctx context.Context, req *pb.RecordAdjustmentRequest) (*pb.LedgerEntry, error) {
+	if err := auth.CheckRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	entry, err := h.ledger.RecordAdjustment(ctx, req.GetDriverID(), money.FromProto(req.GetAmount()), req.GetReason())
+	if err != nil {
+		return nil, status.Errorf(ledgerErrorCode(err), "failed to record adjustment: %v", err)
+	}
+
+	return entry.ToProto(), nil
+}
+
+func (h *grpcHandler) PayoutDriver(ctx context.Context, req *pb.PayoutDriverRequest) (*pb.Payout, error) {
+	if err := auth.CheckRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	payout, err := h.ledger.PayoutDriver(ctx, req.GetDriverID())
+	if err != nil {
+		return nil, status.Errorf(ledgerErrorCode(err), "failed to pay out driver: %v", err)
+	}
+
+	return payout.ToProto(), nil
+}
+
+func (h *grpcHandler) GetTripReceipt(ctx context.Context, req *pb.GetTripReceiptRequest) (*pb.TripReceipt, error) {
+	if err := auth.CheckUser(ctx, req.GetUserID()); err != nil {
+		return nil, err
+	}
+
+	intent, err := h.payments.GetReceipt(ctx, req.GetTripID(), req.GetUserID())
+	if err != nil {
+		code := ledgerErrorCode(err)
+		switch {
+		case errors.Is(err, domain.ErrNotPayer):
+			code = codes.PermissionDenied
+		case errors.Is(err, domain.ErrNotPaid):
+			code = codes.FailedPrecondition
+		}
+		return nil, status.Errorf(code, "failed to get receipt: %v", err)
+	}
+
+	return intent.ToReceiptProto(), nil
+}
+
+func ledgerErrorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, domain.ErrIntentNotFound):
+		return codes.NotFound
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		return codes.AlreadyExists
+	case errors.Is(err, domain.ErrInvalidLedgerArgs), errors.Is(err, money.ErrCurrencyMismatch):
+		return codes.InvalidArgument
+	case errors.Is(err, domain.ErrNothingToPay):
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
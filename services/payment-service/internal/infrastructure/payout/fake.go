@@ -0,0 +1,22 @@
+// Package payout holds the implementations of the processors paying drivers out.
+package payout
+
+import (
+	"context"
+	"log"
+
+	"ride-sharing/shared/money"
+)
+
+type fakeProcessor struct{}
+
+// NewFakeProcessor pretends to transfer money to drivers, for local runs.
+func NewFakeProcessor() *fakeProcessor {
+	return &fakeProcessor{}
+}
+
+func (p *fakeProcessor) Payout(ctx context.Context, driverID string, amount money.Money, reference string) (string, error) {
+	log.Printf("Fake payout of %s to driver %s (%s)", amount, driverID, reference)
+
+	return "fake_po_" + reference, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/services/payment-service/pkg/types"
+)
+
+type inmemLedgerRepository struct {
+	// intents holds the latest payment intent of every trip
+	intents map[string]*types.PaymentIntent
+	entries map[string]*types.LedgerEntry
+	// driverEntries holds the entries of every driver in the order they were added
+	driverEntries map[string][]*types.LedgerEntry
+	mu            sync.RWMutex
+}
+
+func NewInmemLedgerRepository() *inmemLedgerRepository {
+	return &inmemLedgerRepository{
+		intents:       make(map[string]*types.PaymentIntent),
+		entries:       make(map[string]*types.LedgerEntry),
+		driverEntries: make(map[string][]*types.LedgerEntry),
+	}
+}
+
+func (r *inmemLedgerRepository) SaveIntent(ctx context.Context, intent *types.PaymentIntent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.intents[intent.TripID] = intent
+	return nil
+}
+
+func (r *inmemLedgerRepository) GetIntentByTrip(ctx context.Context, tripID string) (*types.PaymentIntent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	intent, ok := r.intents[tripID]
+	if !ok {
+		return nil, fmt.Errorf("%w: trip %s", domain.ErrIntentNotFound, tripID)
+	}
+
+	return intent, nil
+}
+
+func (r *inmemLedgerRepository) MarkIntentPaid(ctx context.Context, tripID string, paidAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	intent, ok := r.intents[tripID]
+	if !ok {
+		return fmt.Errorf("%w: trip %s", domain.ErrIntentNotFound, tripID)
+	}
+
+	if intent.PaidAt == nil {
+		intent.PaidAt = &paidAt
+	}
+
+	return nil
+}
+
+func (r *inmemLedgerRepository) AddEntry(ctx context.Context, entry *types.LedgerEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.entries[entry.ID]; ok {
+		return fmt.Errorf("%w: %s", domain.ErrDuplicateEntry, entry.ID)
+	}
+
+	r.entries[entry.ID] = entry
+	r.driverEntries[entry.DriverID] = append(r.driverEntries[entry.DriverID], entry)
+
+	return nil
+}
+
+func (r *inmemLedgerRepository) ListEntries(ctx context.Context, driverID string, from, to time.Time) ([]*types.LedgerEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var entries []*types.LedgerEntry
+	for _, entry := range r.driverEntries[driverID] {
+		if !entry.CreatedAt.Before(from) && entry.CreatedAt.Before(to) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/services/payment-service/pkg/types"
+	"ride-sharing/shared/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoLedgerRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoLedgerRepository(db *mongo.Database) *mongoLedgerRepository {
+	return &mongoLedgerRepository{db: db}
+}
+
+func (r *mongoLedgerRepository) SaveIntent(ctx context.Context, intent *types.PaymentIntent) error {
+	_, err := r.db.Collection(db.PaymentIntentsCollection).InsertOne(ctx, intent)
+	return err
+}
+
+func (r *mongoLedgerRepository) GetIntentByTrip(ctx context.Context, tripID string) (*types.PaymentIntent, error) {
+	var intent types.PaymentIntent
+
+	// A trip whose session creation was retried has several intents, the last one is the one published to the rider
+	err := r.db.Collection(db.PaymentIntentsCollection).FindOne(
+		ctx,
+		bson.M{"tripID": tripID},
+		options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}}),
+	).Decode(&intent)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w: trip %s", domain.ErrIntentNotFound, tripID)
+		}
+		return nil, err
+	}
+
+	return &intent, nil
+}
+
+func (r *mongoLedgerRepository) MarkIntentPaid(ctx context.Context, tripID string, paidAt time.Time) error {
+	err := r.db.Collection(db.PaymentIntentsCollection).FindOneAndUpdate(
+		ctx,
+		bson.M{"tripID": tripID},
+		bson.M{"$min": bson.M{"paidAt": paidAt}},
+		options.FindOneAndUpdate().SetSort(bson.D{{Key: "createdAt", Value: -1}}),
+	).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("%w: trip %s", domain.ErrIntentNotFound, tripID)
+	}
+
+	return err
+}
+
+func (r *mongoLedgerRepository) AddEntry(ctx context.Context, entry *types.LedgerEntry) error {
+	_, err := r.db.Collection(db.LedgerEntriesCollection).InsertOne(ctx, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %s", domain.ErrDuplicateEntry, entry.ID)
+	}
+
+	return err
+}
+
+func (r *mongoLedgerRepository) ListEntries(ctx context.Context, driverID string, from, to time.Time) ([]*types.LedgerEntry, error) {
+	cursor, err := r.db.Collection(db.LedgerEntriesCollection).Find(
+		ctx,
+		bson.M{
+			"driverID":  driverID,
+			"createdAt": bson.M{"$gte": from, "$lt": to},
+		},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*types.LedgerEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
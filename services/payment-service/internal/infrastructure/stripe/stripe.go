@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"ride-sharing/services/payment-service/internal/domain"
 	"ride-sharing/services/payment-service/pkg/types"
+	"ride-sharing/shared/money"
+	"strings"
 
 	"github.com/stripe/stripe-go/v81"
 	"github.com/stripe/stripe-go/v81/checkout/session"
+	"github.com/stripe/stripe-go/v81/coupon"
 )
 
 type stripeClient struct {
@@ -22,24 +25,66 @@ func NewStripeClient(config *types.PaymentConfig) domain.PaymentProcessor {
 	}
 }
 
-func (s *stripeClient) CreatePaymentSession(ctx context.Context, amount int64, currency string, metadata map[string]string) (string, error) {
+func (s *stripeClient) CreatePaymentSession(ctx context.Context, amount money.Money, description string, lineItems []types.LineItem, metadata map[string]string) (string, error) {
+	if description == "" {
+		description = "Ride Payment"
+	}
+
+	// Stripe expects lowercase ISO currency codes
+	currency := strings.ToLower(amount.Currency)
+
+	if len(lineItems) == 0 {
+		lineItems = []types.LineItem{{Description: description, Amount: amount}}
+	}
+
+	// Checkout does not accept negative line items, discounts are applied with a one-off coupon instead
+	var checkoutLineItems []*stripe.CheckoutSessionLineItemParams
+	discount := int64(0)
+	for _, item := range lineItems {
+		if item.Amount.IsNegative() {
+			discount -= item.Amount.Amount
+			continue
+		}
+
+		if item.Amount.IsZero() {
+			continue
+		}
+
+		checkoutLineItems = append(checkoutLineItems, &stripe.CheckoutSessionLineItemParams{
+			PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+				Currency: stripe.String(currency),
+				ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+					Name: stripe.String(item.Description),
+				},
+				UnitAmount: stripe.Int64(item.Amount.Amount),
+			},
+			Quantity: stripe.Int64(1),
+		})
+	}
+
 	params := &stripe.CheckoutSessionParams{
 		SuccessURL: stripe.String(s.config.SuccessURL),
 		CancelURL:  stripe.String(s.config.CancelURL),
 		Metadata:   metadata,
-		LineItems: []*stripe.CheckoutSessionLineItemParams{
-			{
-				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
-					Currency: stripe.String(currency),
-					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
-						Name: stripe.String("Ride Payment"),
-					},
-					UnitAmount: stripe.Int64(amount),
-				},
-				Quantity: stripe.Int64(1),
-			},
-		},
-		Mode: stripe.String(string(stripe.CheckoutSessionModePayment)),
+		LineItems:  checkoutLineItems,
+		Mode:       stripe.String(string(stripe.CheckoutSessionModePayment)),
+	}
+
+	if discount > 0 {
+		discountCoupon, err := coupon.New(&stripe.CouponParams{
+			Name:           stripe.String("Discount"),
+			AmountOff:      stripe.Int64(discount),
+			Currency:       stripe.String(currency),
+			Duration:       stripe.String(string(stripe.CouponDurationOnce)),
+			MaxRedemptions: stripe.Int64(1),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create a discount coupon on stripe: %w", err)
+		}
+
+		params.Discounts = []*stripe.CheckoutSessionDiscountParams{
+			{Coupon: stripe.String(discountCoupon.ID)},
+		}
 	}
 
 	result, err := session.New(params)
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/services/payment-service/pkg/types"
+	"ride-sharing/shared/money"
+
+	"github.com/google/uuid"
+)
+
+// driverShareItems are the line items of a fare the driver gets a share of.
+// Booking fees and taxes go to the platform, and discounts are funded by it so they don't lower the driver's share.
+// Cancellation fees pay the driver for the drive to a pickup the rider cancelled, the commission is taken from them as from fares.
+var driverShareItems = map[string]bool{
+	"base":             true,
+	"distance":         true,
+	"time":             true,
+	"minimum_fare":     true,
+	"surge":            true,
+	"cancellation_fee": true,
+}
+
+type ledgerService struct {
+	repo    domain.LedgerRepository
+	payouts domain.PayoutProcessor
+	config  *types.LedgerConfig
+	// payoutMu keeps a driver's balance from being paid out twice at the same time
+	payoutMu sync.Mutex
+}
+
+// NewLedgerService creates the service keeping the earnings ledger of drivers
+func NewLedgerService(repo domain.LedgerRepository, payouts domain.PayoutProcessor, config *types.LedgerConfig) domain.LedgerService {
+	if config == nil {
+		config = types.DefaultLedgerConfig()
+	}
+
+	return &ledgerService{
+		repo:    repo,
+		payouts: payouts,
+		config:  config,
+	}
+}
+
+func (s *ledgerService) RecordTripPayment(ctx context.Context, tripID string) (*types.LedgerEntry, error) {
+	intent, err := s.repo.GetIntentByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Payments without a driver, e.g. some cancellation fees, are marked paid too for the rider's receipt
+	if intent.PaidAt == nil {
+		if err := s.repo.MarkIntentPaid(ctx, tripID, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	if intent.DriverID == "" {
+		return nil, fmt.Errorf("%w: trip %s has no driver", domain.ErrInvalidLedgerArgs, tripID)
+	}
+
+	fare, err := driverFare(intent)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCurrency(fare); err != nil {
+		return nil, err
+	}
+
+	commission := fare.Percent(s.config.CommissionPercent)
+	earnings, err := fare.Sub(commission)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &types.LedgerEntry{
+		ID:          "trip:" + tripID,
+		DriverID:    intent.DriverID,
+		Type:        types.LedgerEntryTrip,
+		TripID:      tripID,
+		Amount:      earnings,
+		Fare:        fare,
+		Commission:  commission,
+		Description: fmt.Sprintf("Trip %s", tripID),
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.repo.AddEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// driverFare is the part of what the rider paid the commission is taken from.
+// Payments without a breakdown are taken as a whole.
+func driverFare(intent *types.PaymentIntent) (money.Money, error) {
+	if len(intent.LineItems) == 0 {
+		return intent.Amount, nil
+	}
+
+	var amounts []money.Money
+	for _, item := range intent.LineItems {
+		if driverShareItems[item.Type] {
+			amounts = append(amounts, item.Amount)
+		}
+	}
+
+	return money.Sum(intent.Amount.Currency, amounts...)
+}
+
+func (s *ledgerService) RecordTip(ctx context.Context, tripID string, amount money.Money) (*types.LedgerEntry, error) {
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("%w: tip must be positive", domain.ErrInvalidLedgerArgs)
+	}
+
+	if err := s.checkCurrency(amount); err != nil {
+		return nil, err
+	}
+
+	// Only paid trips can be tipped, the driver comes from their payment
+	intent, err := s.repo.GetIntentByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &types.LedgerEntry{
+		ID:          "tip:" + tripID,
+		DriverID:    intent.DriverID,
+		Type:        types.LedgerEntryTip,
+		TripID:      tripID,
+		Amount:      amount,
+		Description: fmt.Sprintf("Tip for trip %s", tripID),
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.repo.AddEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (s *ledgerService) RecordAdjustment(ctx context.Context, driverID string, amount money.Money, reason string) (*types.LedgerEntry, error) {
+	if driverID == "" || reason == "" || amount.IsZero() {
+		return nil, fmt.Errorf("%w: adjustments need a driver, a reason and a non zero amount", domain.ErrInvalidLedgerArgs)
+	}
+
+	if err := s.checkCurrency(amount); err != nil {
+		return nil, err
+	}
+
+	entry := &types.LedgerEntry{
+		ID:          "adjustment:" + uuid.New().String(),
+		DriverID:    driverID,
+		Type:        types.LedgerEntryAdjustment,
+		Amount:      amount,
+		Description: reason,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.repo.AddEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (s *ledgerService) GetStatement(ctx context.Context, driverID string, period types.StatementPeriod, day time.Time) (*types.Statement, error) {
+	if driverID == "" {
+		return nil, fmt.Errorf("%w: driver is required", domain.ErrInvalidLedgerArgs)
+	}
+
+	day = day.In(s.config.Location)
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, s.config.Location)
+
+	var to time.Time
+	switch period {
+	case types.StatementDaily:
+		to = from.AddDate(0, 0, 1)
+	case types.StatementWeekly:
+		// Weeks start on Monday
+		from = from.AddDate(0, 0, -(int(from.Weekday())+6)%7)
+		to = from.AddDate(0, 0, 7)
+	default:
+		return nil, fmt.Errorf("%w: unknown statement period %q", domain.ErrInvalidLedgerArgs, period)
+	}
+
+	entries, err := s.repo.ListEntries(ctx, driverID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	zero := money.Zero(s.config.Currency)
+	statement := &types.Statement{
+		DriverID:     driverID,
+		Period:       period,
+		From:         from,
+		To:           to,
+		Entries:      entries,
+		Fares:        zero,
+		Commission:   zero,
+		TripEarnings: zero,
+		Tips:         zero,
+		Adjustments:  zero,
+		Earnings:     zero,
+		Payouts:      zero,
+	}
+
+	for _, entry := range entries {
+		var total *money.Money
+		switch entry.Type {
+		case types.LedgerEntryTrip:
+			statement.Trips++
+			if err := addTo(&statement.Fares, entry.Fare); err != nil {
+				return nil, err
+			}
+			if err := addTo(&statement.Commission, entry.Commission); err != nil {
+				return nil, err
+			}
+			total = &statement.TripEarnings
+		case types.LedgerEntryTip:
+			total = &statement.Tips
+		case types.LedgerEntryAdjustment:
+			total = &statement.Adjustments
+		case types.LedgerEntryPayout:
+			if err := addTo(&statement.Payouts, entry.Amount.Neg()); err != nil {
+				return nil, err
+			}
+			continue
+		default:
+			continue
+		}
+
+		if err := addTo(total, entry.Amount); err != nil {
+			return nil, err
+		}
+		if err := addTo(&statement.Earnings, entry.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	return statement, nil
+}
+
+func addTo(total *money.Money, amount money.Money) error {
+	sum, err := total.Add(amount)
+	if err != nil {
+		return err
+	}
+
+	*total = sum
+	return nil
+}
+
+// ExportStatementCSV writes a line per entry followed by the totals of the statement.
+func (s *ledgerService) ExportStatementCSV(statement *types.Statement, w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	if err := out.Write([]string{"date", "type", "trip_id", "description", "fare", "commission", "amount", "currency"}); err != nil {
+		return err
+	}
+
+	for _, entry := range statement.Entries {
+		if err := out.Write([]string{
+			entry.CreatedAt.In(s.config.Location).Format(time.RFC3339),
+			string(entry.Type),
+			entry.TripID,
+			entry.Description,
+			formatMajor(entry.Fare),
+			formatMajor(entry.Commission),
+			formatMajor(entry.Amount),
+			entry.Amount.Currency,
+		}); err != nil {
+			return err
+		}
+	}
+
+	totals := []struct {
+		name   string
+		amount money.Money
+	}{
+		{"total_fares", statement.Fares},
+		{"total_commission", statement.Commission},
+		{"total_trip_earnings", statement.TripEarnings},
+		{"total_tips", statement.Tips},
+		{"total_adjustments", statement.Adjustments},
+		{"total_earnings", statement.Earnings},
+		{"total_payouts", statement.Payouts},
+	}
+	for _, total := range totals {
+		if err := out.Write([]string{"", total.name, "", "", "", "", formatMajor(total.amount), total.amount.Currency}); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// formatMajor formats the amount in major units without its currency, as spreadsheets expect.
+func formatMajor(m money.Money) string {
+	return strconv.FormatFloat(m.Major(), 'f', money.Exponent(m.Currency), 64)
+}
+
+func (s *ledgerService) PayoutDriver(ctx context.Context, driverID string) (*types.Payout, error) {
+	if driverID == "" {
+		return nil, fmt.Errorf("%w: driver is required", domain.ErrInvalidLedgerArgs)
+	}
+
+	s.payoutMu.Lock()
+	defer s.payoutMu.Unlock()
+
+	entries, err := s.repo.ListEntries(ctx, driverID, time.Time{}, time.Now().Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	balance := money.Zero(s.config.Currency)
+	for _, entry := range entries {
+		if err := addTo(&balance, entry.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	if !balance.IsPositive() {
+		return nil, fmt.Errorf("%w: balance of driver %s is %s", domain.ErrNothingToPay, driverID, balance)
+	}
+
+	payout := &types.Payout{
+		ID:        uuid.New().String(),
+		DriverID:  driverID,
+		Amount:    balance,
+		CreatedAt: time.Now(),
+	}
+
+	reference, err := s.payouts.Payout(ctx, driverID, balance, payout.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pay out driver %s: %w", driverID, err)
+	}
+	payout.Reference = reference
+
+	if err := s.repo.AddEntry(ctx, &types.LedgerEntry{
+		ID:          "payout:" + payout.ID,
+		DriverID:    driverID,
+		Type:        types.LedgerEntryPayout,
+		Amount:      balance.Neg(),
+		Description: fmt.Sprintf("Payout %s", reference),
+		CreatedAt:   payout.CreatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("driver %s was paid out %s as %s but it could not be recorded: %w", driverID, balance, reference, err)
+	}
+
+	return payout, nil
+}
+
+func (s *ledgerService) checkCurrency(amount money.Money) error {
+	if amount.Currency != s.config.Currency {
+		return fmt.Errorf("%w: ledger is in %s, got %s", money.ErrCurrencyMismatch, s.config.Currency, amount)
+	}
+
+	return nil
+}
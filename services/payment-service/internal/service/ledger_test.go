@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/services/payment-service/internal/infrastructure/repository"
+	"ride-sharing/services/payment-service/pkg/types"
+	"ride-sharing/shared/money"
+)
+
+func usd(cents int64) money.Money {
+	return money.New(cents, "USD")
+}
+
+func TestRecordTripPayment(t *testing.T) {
+	tests := []struct {
+		name           string
+		driverID       string
+		amount         money.Money
+		lineItems      []types.LineItem
+		wantFare       money.Money
+		wantCommission money.Money
+		wantEarnings   money.Money
+		wantErr        error
+	}{
+		{
+			name:           "without breakdown the whole amount is shared",
+			driverID:       "driver-1",
+			amount:         usd(1000),
+			wantFare:       usd(1000),
+			wantCommission: usd(200),
+			wantEarnings:   usd(800),
+		},
+		{
+			name:     "booking fee and tax go to the platform",
+			driverID: "driver-1",
+			amount:   usd(1230),
+			lineItems: []types.LineItem{
+				{Type: "base", Amount: usd(300)},
+				{Type: "distance", Amount: usd(500)},
+				{Type: "time", Amount: usd(200)},
+				{Type: "booking_fee", Amount: usd(150)},
+				{Type: "tax", Amount: usd(80)},
+			},
+			wantFare:       usd(1000),
+			wantCommission: usd(200),
+			wantEarnings:   usd(800),
+		},
+		{
+			name:     "surge and minimum fare are shared",
+			driverID: "driver-1",
+			amount:   usd(900),
+			lineItems: []types.LineItem{
+				{Type: "minimum_fare", Amount: usd(600)},
+				{Type: "surge", Amount: usd(300)},
+			},
+			wantFare:       usd(900),
+			wantCommission: usd(180),
+			wantEarnings:   usd(720),
+		},
+		{
+			name:     "discounts are funded by the platform",
+			driverID: "driver-1",
+			amount:   usd(700),
+			lineItems: []types.LineItem{
+				{Type: "base", Amount: usd(400)},
+				{Type: "distance", Amount: usd(600)},
+				{Type: "discount", Amount: usd(-300)},
+			},
+			wantFare:       usd(1000),
+			wantCommission: usd(200),
+			wantEarnings:   usd(800),
+		},
+		{
+			name:     "cancellation fee pays the driver",
+			driverID: "driver-1",
+			amount:   usd(500),
+			lineItems: []types.LineItem{
+				{Type: "cancellation_fee", Amount: usd(500)},
+			},
+			wantFare:       usd(500),
+			wantCommission: usd(100),
+			wantEarnings:   usd(400),
+		},
+		{
+			name:           "commission is rounded half away from zero",
+			driverID:       "driver-1",
+			amount:         usd(333),
+			wantFare:       usd(333),
+			wantCommission: usd(67),
+			wantEarnings:   usd(266),
+		},
+		{
+			name:    "payment without driver",
+			amount:  usd(500),
+			wantErr: domain.ErrInvalidLedgerArgs,
+		},
+		{
+			name:     "payment in another currency",
+			driverID: "driver-1",
+			amount:   money.New(1000, "EUR"),
+			wantErr:  money.ErrCurrencyMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewInmemLedgerRepository()
+			ledger := NewLedgerService(repo, nil, types.DefaultLedgerConfig())
+			ctx := context.Background()
+
+			if err := repo.SaveIntent(ctx, &types.PaymentIntent{
+				ID:        "intent-1",
+				TripID:    "trip-1",
+				DriverID:  tt.driverID,
+				Amount:    tt.amount,
+				LineItems: tt.lineItems,
+			}); err != nil {
+				t.Fatalf("SaveIntent() error = %v", err)
+			}
+
+			entry, err := ledger.RecordTripPayment(ctx, "trip-1")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RecordTripPayment() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RecordTripPayment() error = %v", err)
+			}
+
+			if entry.Fare != tt.wantFare || entry.Commission != tt.wantCommission || entry.Amount != tt.wantEarnings {
+				t.Errorf("RecordTripPayment() fare = %v, commission = %v, earnings = %v, want %v, %v, %v",
+					entry.Fare, entry.Commission, entry.Amount, tt.wantFare, tt.wantCommission, tt.wantEarnings)
+			}
+		})
+	}
+}
+
+func TestRecordTripPaymentTwice(t *testing.T) {
+	repo := repository.NewInmemLedgerRepository()
+	ledger := NewLedgerService(repo, nil, types.DefaultLedgerConfig())
+	ctx := context.Background()
+
+	if err := repo.SaveIntent(ctx, &types.PaymentIntent{ID: "intent-1", TripID: "trip-1", DriverID: "driver-1", Amount: usd(1000)}); err != nil {
+		t.Fatalf("SaveIntent() error = %v", err)
+	}
+
+	if _, err := ledger.RecordTripPayment(ctx, "trip-1"); err != nil {
+		t.Fatalf("RecordTripPayment() error = %v", err)
+	}
+	if _, err := ledger.RecordTripPayment(ctx, "trip-1"); !errors.Is(err, domain.ErrDuplicateEntry) {
+		t.Fatalf("RecordTripPayment() of a paid trip error = %v, want %v", err, domain.ErrDuplicateEntry)
+	}
+}
@@ -3,22 +3,26 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"ride-sharing/services/payment-service/internal/domain"
 	"ride-sharing/services/payment-service/pkg/types"
+	"ride-sharing/shared/money"
 
 	"github.com/google/uuid"
 )
 
 type paymentService struct {
 	paymentProcessor domain.PaymentProcessor
+	repo             domain.LedgerRepository
 }
 
 // NewPaymentService creates a new instance of the payment service
-func NewPaymentService(paymentProcessor domain.PaymentProcessor) domain.Service {
+func NewPaymentService(paymentProcessor domain.PaymentProcessor, repo domain.LedgerRepository) domain.Service {
 	return &paymentService{
 		paymentProcessor: paymentProcessor,
+		repo:             repo,
 	}
 }
 
@@ -28,8 +32,9 @@ func (s *paymentService) CreatePaymentSession(
 	tripID string,
 	userID string,
 	driverID string,
-	amount int64,
-	currency string,
+	amount money.Money,
+	description string,
+	lineItems []types.LineItem,
 ) (*types.PaymentIntent, error) {
 	metadata := map[string]string{
 		"trip_id":   tripID,
@@ -37,7 +42,21 @@ func (s *paymentService) CreatePaymentSession(
 		"driver_id": driverID,
 	}
 
-	sessionID, err := s.paymentProcessor.CreatePaymentSession(ctx, amount, currency, metadata)
+	if len(lineItems) > 0 {
+		amounts := make([]money.Money, len(lineItems))
+		for i, item := range lineItems {
+			amounts[i] = item.Amount
+		}
+
+		total, err := money.Sum(amount.Currency, amounts...)
+		if err != nil || total != amount {
+			// Charging the amount matters more than showing the breakdown
+			log.Printf("Line items of trip %s add up to %s instead of %s, charging a single item", tripID, total, amount)
+			lineItems = nil
+		}
+	}
+
+	sessionID, err := s.paymentProcessor.CreatePaymentSession(ctx, amount, description, lineItems, metadata)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create payment session: %w", err)
 	}
@@ -48,10 +67,32 @@ func (s *paymentService) CreatePaymentSession(
 		UserID:          userID,
 		DriverID:        driverID,
 		Amount:          amount,
-		Currency:        currency,
+		LineItems:       lineItems,
 		StripeSessionID: sessionID,
 		CreatedAt:       time.Now(),
 	}
 
+	// The driver is credited from the intent once the payment succeeds
+	if err := s.repo.SaveIntent(ctx, paymentIntent); err != nil {
+		return nil, fmt.Errorf("failed to save payment intent: %w", err)
+	}
+
 	return paymentIntent, nil
 }
+
+func (s *paymentService) GetReceipt(ctx context.Context, tripID, userID string) (*types.PaymentIntent, error) {
+	intent, err := s.repo.GetIntentByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if intent.UserID != userID {
+		return nil, domain.ErrNotPayer
+	}
+
+	if intent.PaidAt == nil {
+		return nil, fmt.Errorf("%w: trip %s", domain.ErrNotPaid, tripID)
+	}
+
+	return intent, nil
+}
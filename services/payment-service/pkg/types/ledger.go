@@ -0,0 +1,143 @@
+package types
+
+import (
+	"time"
+
+	"ride-sharing/shared/money"
+	pb "ride-sharing/shared/proto/payment"
+)
+
+// LedgerEntryType is what moved money in or out of a driver's earnings
+type LedgerEntryType string
+
+const (
+	// LedgerEntryTrip is the driver's share of a paid trip, after commission
+	LedgerEntryTrip LedgerEntryType = "trip"
+	// LedgerEntryTip goes to the driver in full
+	LedgerEntryTip LedgerEntryType = "tip"
+	// LedgerEntryAdjustment corrects earnings either way, e.g. a refunded trip or a bonus
+	LedgerEntryAdjustment LedgerEntryType = "adjustment"
+	// LedgerEntryPayout is money paid out to the driver, it has a negative amount
+	LedgerEntryPayout LedgerEntryType = "payout"
+)
+
+// LedgerEntry is a line of a driver's earnings ledger. Entries are never changed once recorded,
+// mistakes are corrected with adjustments.
+type LedgerEntry struct {
+	// ID is derived from what the entry records, e.g. the trip, so it is recorded only once
+	ID       string          `json:"id" bson:"_id"`
+	DriverID string          `json:"driver_id" bson:"driverID"`
+	Type     LedgerEntryType `json:"type" bson:"type"`
+	TripID   string          `json:"trip_id,omitempty" bson:"tripID,omitempty"`
+	// Amount is what the entry adds to the driver's balance
+	Amount money.Money `json:"amount" bson:"amount"`
+	// Fare and Commission are set on trip entries, Amount is the fare minus the commission
+	Fare        money.Money `json:"fare" bson:"fare"`
+	Commission  money.Money `json:"commission" bson:"commission"`
+	Description string      `json:"description" bson:"description"`
+	CreatedAt   time.Time   `json:"created_at" bson:"createdAt"`
+}
+
+// StatementPeriod is how long a statement spans
+type StatementPeriod string
+
+const (
+	StatementDaily StatementPeriod = "daily"
+	// StatementWeekly statements go from Monday to Sunday
+	StatementWeekly StatementPeriod = "weekly"
+)
+
+// Statement sums up the ledger entries of a driver over a period.
+type Statement struct {
+	DriverID string          `json:"driver_id"`
+	Period   StatementPeriod `json:"period"`
+	// From is inclusive and To exclusive
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Entries []*LedgerEntry `json:"entries"`
+	Trips   int            `json:"trips"`
+	// Fares is what riders paid for the driver's trips, before commission
+	Fares        money.Money `json:"fares"`
+	Commission   money.Money `json:"commission"`
+	TripEarnings money.Money `json:"trip_earnings"`
+	Tips         money.Money `json:"tips"`
+	Adjustments  money.Money `json:"adjustments"`
+	// Earnings is what the driver earned over the period: trip earnings, tips and adjustments
+	Earnings money.Money `json:"earnings"`
+	Payouts  money.Money `json:"payouts"`
+}
+
+// Payout is a transfer of a driver's balance to the driver.
+type Payout struct {
+	ID       string      `json:"id"`
+	DriverID string      `json:"driver_id"`
+	Amount   money.Money `json:"amount"`
+	// Reference identifies the payout at the payout processor
+	Reference string    `json:"reference"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LedgerConfig holds the configuration of the earnings ledger
+type LedgerConfig struct {
+	// CommissionPercent is the platform's share of trip fares
+	CommissionPercent float64
+	// Currency of the ledger, amounts in another currency are rejected
+	Currency string
+	// Location is the time zone statement periods start and end in
+	Location *time.Location
+}
+
+func DefaultLedgerConfig() *LedgerConfig {
+	return &LedgerConfig{
+		CommissionPercent: 20,
+		Currency:          "USD",
+		Location:          time.UTC,
+	}
+}
+
+func (e *LedgerEntry) ToProto() *pb.LedgerEntry {
+	return &pb.LedgerEntry{
+		Id:          e.ID,
+		DriverID:    e.DriverID,
+		Type:        string(e.Type),
+		TripID:      e.TripID,
+		Amount:      e.Amount.ToProto(),
+		Fare:        e.Fare.ToProto(),
+		Commission:  e.Commission.ToProto(),
+		Description: e.Description,
+		CreatedAt:   e.CreatedAt.Unix(),
+	}
+}
+
+func (s *Statement) ToProto() *pb.DriverStatement {
+	entries := make([]*pb.LedgerEntry, len(s.Entries))
+	for i, entry := range s.Entries {
+		entries[i] = entry.ToProto()
+	}
+
+	return &pb.DriverStatement{
+		DriverID:     s.DriverID,
+		Period:       string(s.Period),
+		From:         s.From.Unix(),
+		To:           s.To.Unix(),
+		Entries:      entries,
+		Trips:        int32(s.Trips),
+		Fares:        s.Fares.ToProto(),
+		Commission:   s.Commission.ToProto(),
+		TripEarnings: s.TripEarnings.ToProto(),
+		Tips:         s.Tips.ToProto(),
+		Adjustments:  s.Adjustments.ToProto(),
+		Earnings:     s.Earnings.ToProto(),
+		Payouts:      s.Payouts.ToProto(),
+	}
+}
+
+func (p *Payout) ToProto() *pb.Payout {
+	return &pb.Payout{
+		Id:        p.ID,
+		DriverID:  p.DriverID,
+		Amount:    p.Amount.ToProto(),
+		Reference: p.Reference,
+		CreatedAt: p.CreatedAt.Unix(),
+	}
+}
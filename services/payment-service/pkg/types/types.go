@@ -1,6 +1,11 @@
 package types
 
-import "time"
+import (
+	"time"
+
+	"ride-sharing/shared/money"
+	pb "ride-sharing/shared/proto/payment"
+)
 
 // PaymentStatus represents the current status of a payment
 type PaymentStatus string
@@ -17,8 +22,7 @@ type Payment struct {
 	ID              string        `json:"id"`
 	TripID          string        `json:"trip_id"`
 	UserID          string        `json:"user_id"`
-	Amount          int64         `json:"amount"`   // Amount in cents
-	Currency        string        `json:"currency"` // e.g., "usd"
+	Amount          money.Money   `json:"amount"`
 	Status          PaymentStatus `json:"status"`
 	StripeSessionID string        `json:"stripe_session_id"`
 	CreatedAt       time.Time     `json:"created_at"`
@@ -27,14 +31,51 @@ type Payment struct {
 
 // PaymentIntent represents the intent to collect a payment
 type PaymentIntent struct {
-	ID              string    `json:"id"`
-	TripID          string    `json:"trip_id"`
-	UserID          string    `json:"user_id"`
-	DriverID        string    `json:"driver_id"`
-	Amount          int64     `json:"amount"`
-	Currency        string    `json:"currency"`
-	StripeSessionID string    `json:"stripe_session_id"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID       string      `json:"id" bson:"_id"`
+	TripID   string      `json:"trip_id" bson:"tripID"`
+	UserID   string      `json:"user_id" bson:"userID"`
+	DriverID string      `json:"driver_id" bson:"driverID"`
+	Amount   money.Money `json:"amount" bson:"amount"`
+	// LineItems is the breakdown of Amount the driver's earnings are computed from, when available
+	LineItems       []LineItem `json:"line_items,omitempty" bson:"lineItems,omitempty"`
+	StripeSessionID string     `json:"stripe_session_id" bson:"stripeSessionID"`
+	CreatedAt       time.Time  `json:"created_at" bson:"createdAt"`
+	// PaidAt is set once the payment succeeded
+	PaidAt *time.Time `json:"paid_at,omitempty" bson:"paidAt,omitempty"`
+}
+
+func (p *PaymentIntent) ToReceiptProto() *pb.TripReceipt {
+	items := make([]*pb.ReceiptLineItem, len(p.LineItems))
+	for i, item := range p.LineItems {
+		items[i] = &pb.ReceiptLineItem{
+			Type:        item.Type,
+			Description: item.Description,
+			Amount:      item.Amount.ToProto(),
+		}
+	}
+
+	receipt := &pb.TripReceipt{
+		TripID:    p.TripID,
+		UserID:    p.UserID,
+		DriverID:  p.DriverID,
+		Amount:    p.Amount.ToProto(),
+		LineItems: items,
+		CreatedAt: p.CreatedAt.Unix(),
+	}
+
+	if p.PaidAt != nil {
+		receipt.PaidAt = p.PaidAt.Unix()
+	}
+
+	return receipt
+}
+
+// LineItem is a single charge of a payment, discounts have a negative amount
+type LineItem struct {
+	// Type is what the item charges for, e.g. distance or booking_fee, see the fare line items of trip service
+	Type        string      `json:"type,omitempty" bson:"type,omitempty"`
+	Description string      `json:"description" bson:"description"`
+	Amount      money.Money `json:"amount" bson:"amount"`
 }
 
 // PaymentConfig holds the configuration for the payment service
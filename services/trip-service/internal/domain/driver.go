@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"context"
+
+	pbd "ride-sharing/shared/proto/driver"
+)
+
+// DriverPool lists the drivers driver service knows about.
+type DriverPool interface {
+	// AvailableDrivers returns the drivers currently free to take a trip.
+	AvailableDrivers(ctx context.Context) ([]*pbd.Driver, error)
+}
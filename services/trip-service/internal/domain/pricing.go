@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ride-sharing/shared/money"
+	pb "ride-sharing/shared/proto/trip"
+	"ride-sharing/shared/types"
+
+	"github.com/mmcloughlin/geohash"
+)
+
+var (
+	ErrPackageNotFound     = errors.New("package not found")
+	ErrServiceAreaNotFound = errors.New("service area not found")
+)
+
+// PackagePricing holds the rates of a car package in a service area.
+// Amounts are in the minor units of Currency, e.g. cents for USD.
+type PackagePricing struct {
+	Slug                  string `json:"slug" bson:"slug"`
+	DisplayName           string `json:"displayName" bson:"displayName"`
+	BaseFareInCents       int64  `json:"baseFareInCents" bson:"baseFareInCents"`
+	PricePerKmInCents     int64  `json:"pricePerKmInCents" bson:"pricePerKmInCents"`
+	PricePerMinuteInCents int64  `json:"pricePerMinuteInCents" bson:"pricePerMinuteInCents"`
+	MinimumFareInCents    int64  `json:"minimumFareInCents" bson:"minimumFareInCents"`
+	BookingFeeInCents     int64  `json:"bookingFeeInCents" bson:"bookingFeeInCents"`
+	// TaxRatePercent is applied on the fare including fees, e.g. 8.5 for 8.5%
+	TaxRatePercent float64 `json:"taxRatePercent" bson:"taxRatePercent"`
+	Currency       string  `json:"currency" bson:"currency"`
+}
+
+func (p *PackagePricing) ToProto() *pb.CarPackage {
+	return &pb.CarPackage{
+		Slug:           p.Slug,
+		DisplayName:    p.DisplayName,
+		BaseFare:       p.Amount(p.BaseFareInCents).ToProto(),
+		PricePerKm:     p.Amount(p.PricePerKmInCents).ToProto(),
+		PricePerMinute: p.Amount(p.PricePerMinuteInCents).ToProto(),
+		MinimumFare:    p.Amount(p.MinimumFareInCents).ToProto(),
+		BookingFee:     p.Amount(p.BookingFeeInCents).ToProto(),
+		TaxRatePercent: p.TaxRatePercent,
+	}
+}
+
+// Amount returns minor units of the package currency as money.
+func (p *PackagePricing) Amount(minorUnits int64) money.Money {
+	return money.New(minorUnits, p.Currency)
+}
+
+// ServiceArea is a region with its own package pricing.
+// Pickups are matched to an area by geohash prefix, the default area catches everything else.
+type ServiceArea struct {
+	Slug            string            `json:"slug" bson:"slug"`
+	Default         bool              `json:"default" bson:"default"`
+	GeohashPrefixes []string          `json:"geohashPrefixes" bson:"geohashPrefixes"`
+	Packages        []*PackagePricing `json:"packages" bson:"packages"`
+}
+
+func (a *ServiceArea) Package(slug string) (*PackagePricing, error) {
+	for _, pkg := range a.Packages {
+		if pkg.Slug == slug {
+			return pkg, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s in area %s", ErrPackageNotFound, slug, a.Slug)
+}
+
+type PricingCatalog struct {
+	Areas []*ServiceArea `json:"areas"`
+}
+
+// AreaFor returns the service area with the longest geohash prefix matching the coordinate,
+// or the default area when none matches.
+func (c *PricingCatalog) AreaFor(coord *types.Coordinate) *ServiceArea {
+	hash := geohash.Encode(coord.Latitude, coord.Longitude)
+
+	var match *ServiceArea
+	matchLen := 0
+	for _, area := range c.Areas {
+		for _, prefix := range area.GeohashPrefixes {
+			if len(prefix) > matchLen && strings.HasPrefix(hash, prefix) {
+				match = area
+				matchLen = len(prefix)
+			}
+		}
+	}
+
+	if match != nil {
+		return match
+	}
+
+	return c.DefaultArea()
+}
+
+func (c *PricingCatalog) Area(slug string) *ServiceArea {
+	for _, area := range c.Areas {
+		if area.Slug == slug {
+			return area
+		}
+	}
+
+	return nil
+}
+
+func (c *PricingCatalog) DefaultArea() *ServiceArea {
+	for _, area := range c.Areas {
+		if area.Default {
+			return area
+		}
+	}
+
+	return nil
+}
+
+// Validate makes sure the catalog can price any pickup before it is put in use.
+func (c *PricingCatalog) Validate() error {
+	defaults := 0
+	for _, area := range c.Areas {
+		if area.Slug == "" {
+			return fmt.Errorf("service area without slug")
+		}
+		if area.Default {
+			defaults++
+		}
+		if len(area.Packages) == 0 {
+			return fmt.Errorf("service area %s has no packages", area.Slug)
+		}
+		for _, pkg := range area.Packages {
+			if pkg.Slug == "" || pkg.Currency == "" {
+				return fmt.Errorf("service area %s has a package without slug or currency", area.Slug)
+			}
+			if pkg.TaxRatePercent < 0 {
+				return fmt.Errorf("service area %s package %s has a negative tax rate", area.Slug, pkg.Slug)
+			}
+		}
+	}
+
+	if defaults != 1 {
+		return fmt.Errorf("pricing catalog needs exactly one default service area, got %d", defaults)
+	}
+
+	return nil
+}
+
+// PricingCatalogSource loads the pricing catalog from its backing storage.
+type PricingCatalogSource interface {
+	LoadPricingCatalog(ctx context.Context) (*PricingCatalog, error)
+}
+
+// DefaultPricingCatalog is used when no catalog source has been configured.
+func DefaultPricingCatalog() *PricingCatalog {
+	return &PricingCatalog{
+		Areas: []*ServiceArea{
+			{
+				Slug:    "default",
+				Default: true,
+				Packages: []*PackagePricing{
+					defaultPackagePricing("suv", "SUV", 200),
+					defaultPackagePricing("sedan", "Sedan", 350),
+					defaultPackagePricing("van", "Van", 400),
+					defaultPackagePricing("luxury", "Luxury", 1000),
+				},
+			},
+		},
+	}
+}
+
+func defaultPackagePricing(slug, name string, baseFare int64) *PackagePricing {
+	return &PackagePricing{
+		Slug:                  slug,
+		DisplayName:           name,
+		BaseFareInCents:       baseFare,
+		PricePerKmInCents:     150,
+		PricePerMinuteInCents: 25,
+		MinimumFareInCents:    500,
+		Currency:              "USD",
+	}
+}
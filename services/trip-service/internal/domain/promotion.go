@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"ride-sharing/shared/money"
+)
+
+var (
+	ErrPromotionNotFound      = errors.New("promotion not found")
+	ErrPromotionExpired       = errors.New("promotion is not active")
+	ErrPromotionLimitReached  = errors.New("promotion usage limit reached")
+	ErrPromotionFirstRideOnly = errors.New("promotion is only valid on the first ride")
+)
+
+type DiscountType string
+
+const (
+	DiscountPercentage DiscountType = "percentage"
+	DiscountFixed      DiscountType = "fixed"
+)
+
+// Promotion is a promo code riders can apply when previewing a trip.
+type Promotion struct {
+	Code         string       `json:"code" bson:"_id"`
+	Description  string       `json:"description" bson:"description"`
+	DiscountType DiscountType `json:"discountType" bson:"discountType"`
+	// PercentOff is used by percentage discounts, e.g. 20 for 20% off
+	PercentOff float64 `json:"percentOff,omitempty" bson:"percentOff,omitempty"`
+	// AmountOff is used by fixed discounts, which only apply to fares in the same currency
+	AmountOff money.Money `json:"amountOff" bson:"amountOff"`
+	// MaxDiscount caps percentage discounts, zero means no cap
+	MaxDiscount money.Money `json:"maxDiscount" bson:"maxDiscount"`
+	// PackageSlugs restricts the promotion to some packages, empty means all of them
+	PackageSlugs  []string `json:"packageSlugs,omitempty" bson:"packageSlugs,omitempty"`
+	FirstRideOnly bool     `json:"firstRideOnly" bson:"firstRideOnly"`
+	// MaxUsesPerUser limits how many trips a rider can take with the promotion, zero means unlimited
+	MaxUsesPerUser int       `json:"maxUsesPerUser" bson:"maxUsesPerUser"`
+	StartsAt       time.Time `json:"startsAt" bson:"startsAt"`
+	ExpiresAt      time.Time `json:"expiresAt" bson:"expiresAt"`
+}
+
+// NormalizePromoCode makes promo codes case insensitive.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (p *Promotion) IsActive(now time.Time) bool {
+	if !p.StartsAt.IsZero() && now.Before(p.StartsAt) {
+		return false
+	}
+
+	return p.ExpiresAt.IsZero() || now.Before(p.ExpiresAt)
+}
+
+func (p *Promotion) AppliesTo(packageSlug string) bool {
+	return len(p.PackageSlugs) == 0 || slices.Contains(p.PackageSlugs, packageSlug)
+}
+
+// Discount returns how much is taken off amount, never more than amount itself.
+func (p *Promotion) Discount(amount money.Money) money.Money {
+	discount := money.Zero(amount.Currency)
+
+	switch p.DiscountType {
+	case DiscountPercentage:
+		discount = amount.Percent(p.PercentOff)
+		if p.MaxDiscount.IsPositive() && p.MaxDiscount.Currency == amount.Currency && discount.Amount > p.MaxDiscount.Amount {
+			discount = p.MaxDiscount
+		}
+	case DiscountFixed:
+		if p.AmountOff.Currency == amount.Currency {
+			discount = p.AmountOff
+		}
+	}
+
+	if discount.Amount > amount.Amount {
+		return amount
+	}
+
+	if discount.IsNegative() {
+		return money.Zero(amount.Currency)
+	}
+
+	return discount
+}
+
+type PromotionRepository interface {
+	GetPromotion(ctx context.Context, code string) (*Promotion, error)
+	SavePromotion(ctx context.Context, promotion *Promotion) error
+	CountRedemptions(ctx context.Context, code, userID string) (int, error)
+	// RedeemPromotion atomically counts one more use of the promotion by the user,
+	// returning ErrPromotionLimitReached if the user already used it maxUses times (zero means unlimited).
+	// A first ride promotion is used once, and returns ErrPromotionFirstRideOnly while the user holds
+	// the redemption of another one.
+	RedeemPromotion(ctx context.Context, code, userID string, maxUses int, firstRideOnly bool) error
+	// ReleasePromotion gives back a use counted by RedeemPromotion.
+	ReleasePromotion(ctx context.Context, code, userID string) error
+}
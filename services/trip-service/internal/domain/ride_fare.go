@@ -1,29 +1,124 @@
 package domain
 
 import (
+	"errors"
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/money"
 	pb "ride-sharing/shared/proto/trip"
+	"ride-sharing/shared/types"
+)
+
+var (
+	ErrFareNotFound    = errors.New("fare does not exist")
+	ErrFareNotOwned    = errors.New("fare does not belong to the user")
+	ErrFareExpired     = errors.New("fare has expired")
+	ErrFareAlreadyUsed = errors.New("fare has already been used")
 )
 
 type RideFareModel struct {
-	ID                primitive.ObjectID         `bson:"_id,omitempty"`
-	UserID            string                     `bson:"userID"`
-	PackageSlug       string                     `bson:"packageSlug"`
-	TotalPriceInCents float64                    `bson:"totalPriceInCents"`
-	Route             *tripTypes.OsrmApiResponse `bson:"route"`
+	ID          primitive.ObjectID         `bson:"_id,omitempty"`
+	UserID      string                     `bson:"userID"`
+	PackageSlug string                     `bson:"packageSlug"`
+	TotalPrice  money.Money                `bson:"totalPrice"`
+	ServiceArea string                     `bson:"serviceArea"`
+	Route       *tripTypes.OsrmApiResponse `bson:"route"`
+	ExpiresAt   time.Time                  `bson:"expiresAt"`
+	// LineItems break the total price down, their amounts add up to TotalPrice
+	LineItems []*FareLineItem `bson:"lineItems"`
+	// SurgeMultiplier is the surge quoted with the fare, already included in TotalPrice
+	SurgeMultiplier float64           `bson:"surgeMultiplier"`
+	PickupGeohash   string            `bson:"pickupGeohash"`
+	Pickup          *types.Coordinate `bson:"pickup"`
+	// PromoCode is the promotion discounted in the fare, redeemed when the trip is created
+	PromoCode string `bson:"promoCode,omitempty"`
+	// Consumed is set once a trip has been created from the fare
+	Consumed   bool      `bson:"consumed"`
+	ConsumedAt time.Time `bson:"consumedAt,omitempty"`
+}
+
+func (r *RideFareModel) IsExpired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && now.After(r.ExpiresAt)
 }
 
 func (r *RideFareModel) ToProto() *pb.RideFare {
 	return &pb.RideFare{
-		Id:                r.ID.Hex(),
-		UserID:            r.UserID,
-		PackageSlug:       r.PackageSlug,
-		TotalPriceInCents: r.TotalPriceInCents,
+		Id:              r.ID.Hex(),
+		UserID:          r.UserID,
+		PackageSlug:     r.PackageSlug,
+		TotalPrice:      r.TotalPrice.ToProto(),
+		ExpiresAt:       r.ExpiresAt.Unix(),
+		LineItems:       ToFareLineItemsProto(r.LineItems),
+		SurgeMultiplier: r.SurgeMultiplier,
+		PromoCode:       r.PromoCode,
+		ServiceArea:     r.ServiceArea,
+	}
+}
+
+func (r *RideFareModel) PickupProto() *pb.Coordinate {
+	if r.Pickup == nil {
+		return nil
+	}
+
+	return &pb.Coordinate{
+		Latitude:  r.Pickup.Latitude,
+		Longitude: r.Pickup.Longitude,
+	}
+}
+
+// FareLineItemType identifies what a line item of a fare charges for.
+type FareLineItemType string
+
+const (
+	FareLineItemBase        FareLineItemType = "base"
+	FareLineItemDistance    FareLineItemType = "distance"
+	FareLineItemTime        FareLineItemType = "time"
+	FareLineItemMinimumFare FareLineItemType = "minimum_fare"
+	FareLineItemSurge       FareLineItemType = "surge"
+	FareLineItemBookingFee  FareLineItemType = "booking_fee"
+	FareLineItemDiscount    FareLineItemType = "discount"
+	FareLineItemTax         FareLineItemType = "tax"
+	// FareLineItemCancellationFee is charged instead of the fare when the rider cancels a dispatched trip
+	FareLineItemCancellationFee FareLineItemType = "cancellation_fee"
+)
+
+// FareLineItem is a single component of a fare. Discounts have a negative amount.
+type FareLineItem struct {
+	Type        FareLineItemType `bson:"type" json:"type"`
+	Description string           `bson:"description" json:"description"`
+	Amount      money.Money      `bson:"amount" json:"amount"`
+}
+
+func (i *FareLineItem) ToProto() *pb.FareLineItem {
+	return &pb.FareLineItem{
+		Type:        string(i.Type),
+		Description: i.Description,
+		Amount:      i.Amount.ToProto(),
 	}
 }
 
+func ToFareLineItemsProto(items []*FareLineItem) []*pb.FareLineItem {
+	lineItems := make([]*pb.FareLineItem, len(items))
+	for i, item := range items {
+		lineItems[i] = item.ToProto()
+	}
+
+	return lineItems
+}
+
+// SumFareLineItems returns the total amount of the line items, which must all be in currency.
+func SumFareLineItems(currency string, items []*FareLineItem) (money.Money, error) {
+	amounts := make([]money.Money, len(items))
+	for i, item := range items {
+		amounts[i] = item.Amount
+	}
+
+	return money.Sum(currency, amounts...)
+}
+
 func ToRideFaresProto(fares []*RideFareModel) []*pb.RideFare {
 	rideFares := make([]*pb.RideFare, len(fares))
 	for i, fare := range fares {
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"ride-sharing/shared/types"
+
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+)
+
+// RouteProvider computes the driving route between two coordinates.
+type RouteProvider interface {
+	GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*tripTypes.OsrmApiResponse, error)
+}
+
+// RouteCache stores computed routes by a key derived from the snapped pickup and destination.
+type RouteCache interface {
+	// Get returns nil without an error when there is no fresh entry for the key.
+	Get(ctx context.Context, key string) (*tripTypes.OsrmApiResponse, error)
+	Set(ctx context.Context, key string, route *tripTypes.OsrmApiResponse) error
+}
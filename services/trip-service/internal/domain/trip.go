@@ -12,30 +12,69 @@ import (
 )
 
 type TripModel struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	UserID   string             `bson:"userID"`
-	Status   string             `bson:"status"`
-	RideFare *RideFareModel     `bson:"rideFare"`
-	Driver   *pb.TripDriver     `bson:"driver"`
+	ID           primitive.ObjectID      `bson:"_id,omitempty"`
+	UserID       string                  `bson:"userID"`
+	Status       TripStatus              `bson:"status"`
+	RideFare     *RideFareModel          `bson:"rideFare"`
+	Driver       *pb.TripDriver          `bson:"driver"`
+	History      []*TripStatusTransition `bson:"history"`
+	Cancellation *TripCancellation       `bson:"cancellation,omitempty"`
+	Rating       *TripRating             `bson:"rating,omitempty"`
+	// Pickup is where the rider moved the pickup of the fare to, if they did
+	Pickup *types.Coordinate `bson:"pickup,omitempty"`
+	Stops  []*TripStop       `bson:"stops,omitempty"`
 }
 
 func (t *TripModel) ToProto() *pb.Trip {
-	return &pb.Trip{
+	trip := &pb.Trip{
 		Id:           t.ID.Hex(),
 		UserID:       t.UserID,
 		SelectedFare: t.RideFare.ToProto(),
-		Status:       t.Status,
+		Status:       string(t.Status),
 		Driver:       t.Driver,
 		Route:        t.RideFare.Route.ToProto(),
+		Pickup:       t.RideFare.PickupProto(),
 	}
+
+	if t.Pickup != nil {
+		trip.Pickup = &pb.Coordinate{Latitude: t.Pickup.Latitude, Longitude: t.Pickup.Longitude}
+	}
+
+	for _, stop := range t.Stops {
+		trip.Stops = append(trip.Stops, &pb.Coordinate{Latitude: stop.Location.Latitude, Longitude: stop.Location.Longitude})
+	}
+
+	if t.Rating != nil {
+		trip.Rating = int32(t.Rating.Stars)
+	}
+
+	return trip
 }
 
 type TripRepository interface {
 	CreateTrip(ctx context.Context, trip *TripModel) (*TripModel, error)
 	SaveRideFare(ctx context.Context, fare *RideFareModel) error
 	GetRideFareByID(ctx context.Context, id string) (*RideFareModel, error)
+	// ConsumeRideFare atomically marks an unexpired, unused fare as consumed,
+	// returning ErrFareExpired or ErrFareAlreadyUsed otherwise.
+	ConsumeRideFare(ctx context.Context, id string) error
+	// ReleaseRideFare makes a consumed fare usable again, for when its trip could not be created.
+	ReleaseRideFare(ctx context.Context, id string) error
 	GetTripByID(ctx context.Context, id string) (*TripModel, error)
-	UpdateTrip(ctx context.Context, tripID string, status string, driver *pbd.Driver) error
+	// CountUserTrips counts the trips of the rider that were not cancelled or expired.
+	CountUserTrips(ctx context.Context, userID string) (int, error)
+	// UpdateTripStatus applies the transition only if the trip is still in transition.From,
+	// returning a *TripTransitionError otherwise.
+	UpdateTripStatus(ctx context.Context, tripID string, transition *TripStatusTransition, driver *pbd.Driver) error
+	CancelTrip(ctx context.Context, tripID string, transition *TripStatusTransition, cancellation *TripCancellation) error
+	// RateTrip records the rating of a trip that is rateable and not rated yet,
+	// returning ErrTripNotRateable or ErrTripAlreadyRated otherwise.
+	RateTrip(ctx context.Context, tripID string, rating *TripRating) error
+	// UpdatePickup moves the pickup of a trip that can still be moved, returning ErrTripNotEditable otherwise.
+	UpdatePickup(ctx context.Context, tripID string, pickup *types.Coordinate) error
+	// AddStop adds a stop to a trip that can still get one and has fewer than maxStops,
+	// returning ErrTripNotEditable or ErrTooManyStops otherwise.
+	AddStop(ctx context.Context, tripID string, stop *TripStop, maxStops int) error
 }
 
 type TripService interface {
@@ -43,7 +82,19 @@ type TripService interface {
 	GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*tripTypes.OsrmApiResponse, error)
 	GenerateTripFares(ctx context.Context, fares []*RideFareModel, userID string, route *tripTypes.OsrmApiResponse) ([]*RideFareModel, error)
 	GetAndValidateFare(ctx context.Context, fareID, userID string) (*RideFareModel, error)
-	EstimatePackagesPriceWithRoute(route *tripTypes.OsrmApiResponse) []*RideFareModel
+	// EstimatePackagesPriceWithRoute prices every package of the pickup's service area,
+	// discounting the optional promo code when the rider is allowed to use it.
+	EstimatePackagesPriceWithRoute(ctx context.Context, userID string, pickup *types.Coordinate, route *tripTypes.OsrmApiResponse, promoCode string) ([]*RideFareModel, error)
+	ListPackages(ctx context.Context, areaSlug string, location *types.Coordinate) (*ServiceArea, error)
 	GetTripByID(ctx context.Context, id string) (*TripModel, error)
-	UpdateTrip(ctx context.Context, tripID string, status string, driver *pbd.Driver) error
+	UpdateTripStatus(ctx context.Context, tripID string, status TripStatus, actor TripActor, driver *pbd.Driver) error
+	CancelTrip(ctx context.Context, tripID string, actor TripActor, reason string) (*TripModel, error)
+	// RateTrip lets the rider of a finished trip rate its driver, once.
+	RateTrip(ctx context.Context, tripID, userID string, stars int, comment string) (*TripModel, error)
+	// UpdatePickup lets the rider move the pickup pin before being picked up, within a short distance of the quoted pickup.
+	UpdatePickup(ctx context.Context, tripID, userID string, pickup *types.Coordinate) (*TripModel, error)
+	// AddStop lets the rider add a stop on the way, until the trip is completed.
+	AddStop(ctx context.Context, tripID, userID string, location *types.Coordinate) (*TripModel, error)
+	// UpdateDriverAvailability feeds the driver supply used to compute surge pricing.
+	UpdateDriverAvailability(ctx context.Context, driverID, geohash string, available bool)
 }
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"ride-sharing/shared/money"
+)
+
+var ErrNotTripParticipant = errors.New("user is not a participant of the trip")
+
+// TripCancellation records who cancelled a trip and the fee charged for it.
+type TripCancellation struct {
+	Actor  TripActor   `bson:"actor"`
+	Reason string      `bson:"reason,omitempty"`
+	Fee    money.Money `bson:"fee"`
+	At     time.Time   `bson:"at"`
+}
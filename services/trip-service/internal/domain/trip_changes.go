@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"ride-sharing/shared/types"
+)
+
+var (
+	// ErrTripNotEditable is returned when a trip is past the point where the change is allowed
+	ErrTripNotEditable = errors.New("trip can no longer be changed")
+	ErrPickupTooFar    = errors.New("pickup is too far from the quoted one")
+	ErrTooManyStops    = errors.New("trip has too many stops")
+	ErrInvalidLocation = errors.New("invalid location")
+)
+
+// TripStop is a place the rider asked to stop at on the way to the destination.
+type TripStop struct {
+	Location *types.Coordinate `bson:"location"`
+	AddedAt  time.Time         `bson:"addedAt"`
+}
+
+// CanMovePickup reports whether the pickup of a trip in status s can still be moved, the rider was not picked up yet.
+func (s TripStatus) CanMovePickup() bool {
+	return s == TripStatusRequested || s == TripStatusDriverAssigned || s == TripStatusDriverArriving
+}
+
+// CanAddStop reports whether stops can still be added to a trip in status s.
+func (s TripStatus) CanAddStop() bool {
+	return s.CanMovePickup() || s == TripStatusInProgress
+}
+
+func ValidLocation(c *types.Coordinate) bool {
+	return c != nil && !math.IsNaN(c.Latitude) && !math.IsNaN(c.Longitude) &&
+		c.Latitude >= -90 && c.Latitude <= 90 && c.Longitude >= -180 && c.Longitude <= 180
+}
+
+// DistanceMeters is the great circle distance between two coordinates.
+func DistanceMeters(a, b *types.Coordinate) float64 {
+	const earthRadiusMeters = 6371000.0
+
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	MinTripRating = 1
+	MaxTripRating = 5
+)
+
+var (
+	ErrInvalidRating = errors.New("invalid rating")
+	// ErrTripNotRateable is returned for trips that did not end with a ride, e.g. cancelled or still in progress
+	ErrTripNotRateable  = errors.New("trip cannot be rated")
+	ErrTripAlreadyRated = errors.New("trip already rated")
+)
+
+// TripRating is the rider's rating of the driver, given once the trip is over.
+type TripRating struct {
+	Stars   int       `bson:"stars"`
+	Comment string    `bson:"comment,omitempty"`
+	At      time.Time `bson:"at"`
+}
+
+// IsRateable reports whether riders can rate the driver of a trip in status s.
+func (s TripStatus) IsRateable() bool {
+	return s == TripStatusCompleted || s == TripStatusPaid
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TripStatus is the lifecycle state of a trip.
+type TripStatus string
+
+const (
+	TripStatusRequested      TripStatus = "requested"
+	TripStatusDriverAssigned TripStatus = "driver_assigned"
+	TripStatusDriverArriving TripStatus = "driver_arriving"
+	TripStatusInProgress     TripStatus = "in_progress"
+	TripStatusCompleted      TripStatus = "completed"
+	TripStatusPaid           TripStatus = "paid"
+	TripStatusCancelled      TripStatus = "cancelled"
+	TripStatusExpired        TripStatus = "expired"
+)
+
+// tripTransitions lists, for every status, the statuses a trip is allowed to move to.
+// Statuses without an entry are terminal.
+var tripTransitions = map[TripStatus][]TripStatus{
+	TripStatusRequested:      {TripStatusDriverAssigned, TripStatusCancelled, TripStatusExpired},
+	TripStatusDriverAssigned: {TripStatusDriverArriving, TripStatusInProgress, TripStatusCancelled},
+	TripStatusDriverArriving: {TripStatusInProgress, TripStatusCancelled},
+	TripStatusInProgress:     {TripStatusCompleted},
+	TripStatusCompleted:      {TripStatusPaid},
+}
+
+// CanTransitionTo reports whether a trip in status s may move to next.
+func (s TripStatus) CanTransitionTo(next TripStatus) bool {
+	for _, allowed := range tripTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTerminal reports whether no further transitions are allowed from s.
+func (s TripStatus) IsTerminal() bool {
+	return len(tripTransitions[s]) == 0
+}
+
+// Actor roles recorded on trip status transitions
+const (
+	ActorRider   = "rider"
+	ActorDriver  = "driver"
+	ActorPayment = "payment"
+	ActorSystem  = "system"
+)
+
+// TripActor identifies who triggered a trip status transition.
+type TripActor struct {
+	Role string `bson:"role" json:"role"`
+	ID   string `bson:"id,omitempty" json:"id,omitempty"`
+}
+
+// TripStatusTransition is a single accepted status change recorded on the trip document.
+type TripStatusTransition struct {
+	From  TripStatus `bson:"from,omitempty" json:"from,omitempty"`
+	To    TripStatus `bson:"to" json:"to"`
+	Actor TripActor  `bson:"actor" json:"actor"`
+	At    time.Time  `bson:"at" json:"at"`
+}
+
+func NewTripStatusTransition(from, to TripStatus, actor TripActor) *TripStatusTransition {
+	return &TripStatusTransition{
+		From:  from,
+		To:    to,
+		Actor: actor,
+		At:    time.Now().UTC(),
+	}
+}
+
+var (
+	ErrTripNotFound          = errors.New("trip not found")
+	ErrInvalidTripTransition = errors.New("invalid trip status transition")
+)
+
+// TripTransitionError is returned when a trip is asked to move to a status
+// that is not allowed from its current one.
+type TripTransitionError struct {
+	TripID string
+	From   TripStatus
+	To     TripStatus
+}
+
+func (e *TripTransitionError) Error() string {
+	return fmt.Sprintf("trip %s cannot transition from %q to %q", e.TripID, e.From, e.To)
+}
+
+func (e *TripTransitionError) Is(target error) bool {
+	return target == ErrInvalidTripTransition
+}
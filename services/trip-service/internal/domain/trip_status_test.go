@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+var allTripStatuses = []TripStatus{
+	TripStatusRequested,
+	TripStatusDriverAssigned,
+	TripStatusDriverArriving,
+	TripStatusInProgress,
+	TripStatusCompleted,
+	TripStatusPaid,
+	TripStatusCancelled,
+	TripStatusExpired,
+}
+
+func TestTripStatusTransitions(t *testing.T) {
+	allowed := map[TripStatus][]TripStatus{
+		TripStatusRequested:      {TripStatusDriverAssigned, TripStatusCancelled, TripStatusExpired},
+		TripStatusDriverAssigned: {TripStatusDriverArriving, TripStatusInProgress, TripStatusCancelled},
+		TripStatusDriverArriving: {TripStatusInProgress, TripStatusCancelled},
+		TripStatusInProgress:     {TripStatusCompleted},
+		TripStatusCompleted:      {TripStatusPaid},
+	}
+
+	for _, from := range allTripStatuses {
+		want := make(map[TripStatus]bool)
+		for _, to := range allowed[from] {
+			want[to] = true
+		}
+
+		for _, to := range allTripStatuses {
+			if got := from.CanTransitionTo(to); got != want[to] {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want[to])
+			}
+		}
+	}
+}
+
+func TestTripStatusIsTerminal(t *testing.T) {
+	terminal := map[TripStatus]bool{
+		TripStatusPaid:      true,
+		TripStatusCancelled: true,
+		TripStatusExpired:   true,
+	}
+
+	for _, status := range allTripStatuses {
+		if got := status.IsTerminal(); got != terminal[status] {
+			t.Errorf("%s.IsTerminal() = %v, want %v", status, got, terminal[status])
+		}
+	}
+
+	if !TripStatus("unknown").IsTerminal() {
+		t.Error("unknown statuses must not allow any transition")
+	}
+}
+
+func TestTripLifecycles(t *testing.T) {
+	lifecycles := map[string][]TripStatus{
+		"paid trip":                  {TripStatusRequested, TripStatusDriverAssigned, TripStatusDriverArriving, TripStatusInProgress, TripStatusCompleted, TripStatusPaid},
+		"rider already at pickup":    {TripStatusRequested, TripStatusDriverAssigned, TripStatusInProgress, TripStatusCompleted},
+		"cancelled while arriving":   {TripStatusRequested, TripStatusDriverAssigned, TripStatusDriverArriving, TripStatusCancelled},
+		"expired without any driver": {TripStatusRequested, TripStatusExpired},
+	}
+
+	for name, statuses := range lifecycles {
+		for i := 1; i < len(statuses); i++ {
+			if !statuses[i-1].CanTransitionTo(statuses[i]) {
+				t.Errorf("%s: %s cannot move to %s", name, statuses[i-1], statuses[i])
+			}
+		}
+	}
+}
+
+func TestTripTransitionErrorIsInvalidTransition(t *testing.T) {
+	var err error = &TripTransitionError{TripID: "trip-1", From: TripStatusCompleted, To: TripStatusCancelled}
+
+	if !errors.Is(err, ErrInvalidTripTransition) {
+		t.Fatalf("errors.Is(%v, ErrInvalidTripTransition) = false", err)
+	}
+	if errors.Is(err, ErrTripNotFound) {
+		t.Fatalf("errors.Is(%v, ErrTripNotFound) = true", err)
+	}
+
+	want := `trip trip-1 cannot transition from "completed" to "cancelled"`
+	if err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+}
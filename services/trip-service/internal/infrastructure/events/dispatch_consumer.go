@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// dispatchConsumer expires the trips driver service gave up finding a driver for.
+type dispatchConsumer struct {
+	rabbitmq *messaging.Rabbitmq
+	service  domain.TripService
+}
+
+func NewDispatchConsumer(rabbitmq *messaging.Rabbitmq, service domain.TripService) *dispatchConsumer {
+	return &dispatchConsumer{
+		rabbitmq: rabbitmq,
+		service:  service,
+	}
+}
+
+func (c *dispatchConsumer) Listen() error {
+	return c.rabbitmq.ConsumeMessages(messaging.TripNoDriversFoundQueue, func(ctx context.Context, msg amqp091.Delivery) error {
+		var message contracts.AmqpMessage
+		if err := json.Unmarshal(msg.Body, &message); err != nil {
+			log.Printf("Failed to unmarshal message: %v", err)
+			return err
+		}
+
+		var payload messaging.TripEventData
+		if err := json.Unmarshal(message.Data, &payload); err != nil {
+			log.Printf("Failed to unmarshal payload: %v", err)
+			return err
+		}
+
+		tripID := payload.Trip.GetId()
+		if tripID == "" {
+			log.Printf("Ignoring no drivers found event without a trip")
+			return nil
+		}
+
+		err := c.service.UpdateTripStatus(
+			ctx,
+			tripID,
+			domain.TripStatusExpired,
+			domain.TripActor{Role: domain.ActorSystem},
+			nil,
+		)
+		if errors.Is(err, domain.ErrInvalidTripTransition) {
+			// e.g. the rider cancelled the trip while it was dispatched
+			log.Printf("Ignoring no drivers found for trip %s: %v", tripID, err)
+			return nil
+		}
+
+		return err
+	})
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// driverAvailabilityConsumer follows the driver pool to know the supply of every surge cell.
+type driverAvailabilityConsumer struct {
+	rabbitmq *messaging.Rabbitmq
+	service  domain.TripService
+}
+
+func NewDriverAvailabilityConsumer(rabbitmq *messaging.Rabbitmq, service domain.TripService) *driverAvailabilityConsumer {
+	return &driverAvailabilityConsumer{
+		rabbitmq: rabbitmq,
+		service:  service,
+	}
+}
+
+func (c *driverAvailabilityConsumer) Listen() error {
+	return c.rabbitmq.ConsumeMessages(messaging.TripDriverAvailabilityQueue, func(ctx context.Context, msg amqp091.Delivery) error {
+		var message contracts.AmqpMessage
+		if err := json.Unmarshal(msg.Body, &message); err != nil {
+			log.Printf("Failed to unmarshal message: %v", err)
+			return err
+		}
+
+		var payload messaging.DriverAvailabilityData
+		if err := json.Unmarshal(message.Data, &payload); err != nil {
+			log.Printf("Failed to unmarshal payload: %v", err)
+			return err
+		}
+
+		c.service.UpdateDriverAvailability(ctx, payload.DriverID, payload.Geohash, payload.Available)
+
+		return nil
+	})
+}
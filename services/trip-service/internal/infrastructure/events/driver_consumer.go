@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"ride-sharing/services/trip-service/internal/domain"
@@ -11,6 +12,7 @@ import (
 	pbd "ride-sharing/shared/proto/driver"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"google.golang.org/protobuf/proto"
 )
 
 type driverConsumer struct {
@@ -41,27 +43,41 @@ func (c *driverConsumer) Listen() error {
 
 		log.Printf("driver response received message: %+v", payload)
 
+		// The gateway and driver service set the owner to the driver, unlike the payload it can be trusted
+		driver := responseDriver(message.OwnerID, payload.Driver)
+
+		var err error
 		switch msg.RoutingKey {
-		case contracts.DriverCmdTripAccept:
-			if err := c.handleTripAccepted(ctx, payload.TripID, payload.Driver); err != nil {
-				log.Printf("Failed to handle trip accepted: %v", err)
-				return err
-			}
-		case contracts.DriverCmdTripDecline:
-			if err := c.handleTripDeclined(ctx, payload.TripID, payload.RiderID); err != nil {
-				log.Printf("Failed to handle trip declined: %v", err)
-				return err
-			}
+		case contracts.DriverEventOfferAccepted:
+			// Driver service decides which acceptances count, offers and their expiry are its own
+			err = c.handleOfferAccepted(ctx, payload.TripID, driver)
+		case contracts.DriverCmdTripArrive:
+			err = c.handleTripProgress(ctx, payload.TripID, driver, domain.TripStatusDriverArriving)
+		case contracts.DriverCmdTripStart:
+			err = c.handleTripProgress(ctx, payload.TripID, driver, domain.TripStatusInProgress)
+		case contracts.DriverCmdTripEnd:
+			err = c.handleTripEnded(ctx, payload.TripID, driver)
+		default:
+			log.Printf("unknown trip event: %+v", payload)
+			return nil
+		}
+
+		if errors.Is(err, domain.ErrInvalidTripTransition) {
+			// Retrying will not make the transition legal, so drop the message
+			log.Printf("Ignoring driver response %s: %v", msg.RoutingKey, err)
 			return nil
 		}
 
-		log.Printf("unknown trip event: %+v", payload)
+		if err != nil {
+			log.Printf("Failed to handle driver response %s: %v", msg.RoutingKey, err)
+			return err
+		}
 
 		return nil
 	})
 }
 
-func (c *driverConsumer) handleTripAccepted(ctx context.Context, tripID string, driver *pbd.Driver) error {
+func (c *driverConsumer) handleOfferAccepted(ctx context.Context, tripID string, driver *pbd.Driver) error {
 	trip, err := c.service.GetTripByID(ctx, tripID)
 	if err != nil {
 		return err
@@ -71,8 +87,13 @@ func (c *driverConsumer) handleTripAccepted(ctx context.Context, tripID string,
 		return fmt.Errorf("trip was not found %s", tripID)
 	}
 
-	if err := c.service.UpdateTrip(ctx, tripID, "accepted", driver); err != nil {
-		log.Printf("Faield to update trip: %v", err)
+	// Assigning nobody would leave the trip stuck, whatever sent such an acceptance
+	if driver.GetId() == "" {
+		return fmt.Errorf("%w: acceptance of trip %s names no driver", domain.ErrInvalidTripTransition, tripID)
+	}
+
+	if err := c.service.UpdateTripStatus(ctx, tripID, domain.TripStatusDriverAssigned, driverActor(driver), driver); err != nil {
+		log.Printf("Failed to update trip: %v", err)
 		return err
 	}
 
@@ -81,7 +102,7 @@ func (c *driverConsumer) handleTripAccepted(ctx context.Context, tripID string,
 		return err
 	}
 
-	marshalledTrip, err := json.Marshal(trip)
+	marshalledTrip, err := json.Marshal(trip.ToProto())
 	if err != nil {
 		return err
 	}
@@ -94,48 +115,104 @@ func (c *driverConsumer) handleTripAccepted(ctx context.Context, tripID string,
 		return err
 	}
 
-	marshalledPayload, err := json.Marshal(messaging.PaymentTripResponseData{
-		TripID:   tripID,
-		UserID:   trip.UserID,
-		DriverID: driver.Id,
-		Amount:   trip.RideFare.TotalPriceInCents,
-		Currency: "USD",
-	})
+	return nil
+}
 
-	if err := c.rabbitmq.PublishMessage(ctx, contracts.PaymentCmdCreateSession,
-		contracts.AmqpMessage{
-			OwnerID: trip.UserID,
-			Data:    marshalledPayload,
-		},
-	); err != nil {
+func (c *driverConsumer) handleTripProgress(ctx context.Context, tripID string, driver *pbd.Driver, status domain.TripStatus) error {
+	trip, err := c.service.GetTripByID(ctx, tripID)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	if trip == nil {
+		return fmt.Errorf("trip was not found %s", tripID)
+	}
+
+	if driver == nil || trip.Driver.GetId() != driver.Id {
+		return fmt.Errorf("driver is not assigned to trip %s", tripID)
+	}
+
+	return c.service.UpdateTripStatus(ctx, tripID, status, driverActor(driver), nil)
 }
 
-func (c *driverConsumer) handleTripDeclined(ctx context.Context, tripID string, riderID string) error {
-	// When a driver declienes, we should try to find another driver
+func (c *driverConsumer) handleTripEnded(ctx context.Context, tripID string, driver *pbd.Driver) error {
+	if err := c.handleTripProgress(ctx, tripID, driver, domain.TripStatusCompleted); err != nil {
+		return err
+	}
+
 	trip, err := c.service.GetTripByID(ctx, tripID)
 	if err != nil {
 		return err
 	}
 
-	newPayload := messaging.TripEventData{
+	marshalledPayload, err := json.Marshal(messaging.TripEventData{
 		Trip: trip.ToProto(),
-	}
-
-	marshalledPayload, err := json.Marshal(newPayload)
+	})
 	if err != nil {
 		return err
 	}
 
-	if err := c.rabbitmq.PublishMessage(ctx, contracts.TripEventDriverNotInterested, contracts.AmqpMessage{
-		OwnerID: riderID,
+	// Notify the rider that the trip is over
+	if err := c.rabbitmq.PublishMessage(ctx, contracts.TripEventCompleted, contracts.AmqpMessage{
+		OwnerID: trip.UserID,
 		Data:    marshalledPayload,
 	}); err != nil {
 		return err
 	}
 
+	// The rider is charged once the trip has been completed
+	marshalledPayload, err = json.Marshal(messaging.PaymentTripResponseData{
+		TripID:    tripID,
+		UserID:    trip.UserID,
+		DriverID:  driver.Id,
+		Amount:    trip.RideFare.TotalPrice,
+		LineItems: paymentLineItems(trip.RideFare.LineItems),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.rabbitmq.PublishMessage(ctx, contracts.PaymentCmdCreateSession,
+		contracts.AmqpMessage{
+			OwnerID: trip.UserID,
+			Data:    marshalledPayload,
+		},
+	); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// responseDriver is the driver who sent a response, identified by the owner of the message.
+// The details of the payload are kept to show the rider, but its ID is replaced by the trusted one.
+func responseDriver(ownerID string, driver *pbd.Driver) *pbd.Driver {
+	if ownerID == "" {
+		return nil
+	}
+
+	trusted := &pbd.Driver{}
+	if driver != nil {
+		trusted = proto.Clone(driver).(*pbd.Driver)
+	}
+	trusted.Id = ownerID
+
+	return trusted
+}
+
+func driverActor(driver *pbd.Driver) domain.TripActor {
+	return domain.TripActor{Role: domain.ActorDriver, ID: driver.GetId()}
+}
+
+func paymentLineItems(items []*domain.FareLineItem) []messaging.PaymentLineItem {
+	lineItems := make([]messaging.PaymentLineItem, len(items))
+	for i, item := range items {
+		lineItems[i] = messaging.PaymentLineItem{
+			Type:        string(item.Type),
+			Description: item.Description,
+			Amount:      item.Amount,
+		}
+	}
+
+	return lineItems
+}
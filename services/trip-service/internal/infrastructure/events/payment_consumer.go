@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 
 	"ride-sharing/services/trip-service/internal/domain"
@@ -37,13 +38,24 @@ func (c *paymentConsumer) Listen() error {
 			return err
 		}
 
-		log.Printf("Trip has been completed and payed.")
-
-		return c.service.UpdateTrip(
+		err := c.service.UpdateTripStatus(
 			ctx,
 			payload.TripID,
-			"payed",
+			domain.TripStatusPaid,
+			domain.TripActor{Role: domain.ActorPayment, ID: payload.UserID},
 			nil,
 		)
+		if errors.Is(err, domain.ErrInvalidTripTransition) {
+			// e.g. the trip was cancelled before the payment went through
+			log.Printf("Ignoring payment success for trip %s: %v", payload.TripID, err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Trip has been completed and paid.")
+
+		return nil
 	})
 }
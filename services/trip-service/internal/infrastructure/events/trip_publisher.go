@@ -37,3 +37,129 @@ func (p *TripEventPublisher) PublishTripCreated(ctx context.Context, trip *domai
 		},
 	)
 }
+
+// PublishTripCancelled notifies the rider and, if one was assigned, the driver that the trip was cancelled.
+func (p *TripEventPublisher) PublishTripCancelled(ctx context.Context, trip *domain.TripModel) error {
+	payload := messaging.TripCancelledData{
+		Trip: trip.ToProto(),
+	}
+
+	if trip.Cancellation != nil {
+		payload.CancelledBy = trip.Cancellation.Actor.Role
+		payload.Reason = trip.Cancellation.Reason
+		payload.CancellationFee = trip.Cancellation.Fee
+	}
+
+	tripEventJSON, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	recipients := []string{trip.UserID}
+	if driverID := trip.Driver.GetId(); driverID != "" {
+		recipients = append(recipients, driverID)
+	}
+
+	for _, ownerID := range recipients {
+		if err := p.rabbitmq.PublishMessage(
+			ctx,
+			contracts.TripEventCancelled,
+			contracts.AmqpMessage{
+				OwnerID: ownerID,
+				Data:    tripEventJSON,
+			},
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PublishTripRated lets the driver service account for the rating in the driver's stats.
+func (p *TripEventPublisher) PublishTripRated(ctx context.Context, trip *domain.TripModel) error {
+	if trip.Rating == nil {
+		return nil
+	}
+
+	payload := messaging.TripRatedData{
+		TripID:   trip.ID.Hex(),
+		DriverID: trip.Driver.GetId(),
+		RiderID:  trip.UserID,
+		Rating:   trip.Rating.Stars,
+	}
+
+	ratingJSON, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return p.rabbitmq.PublishMessage(
+		ctx,
+		contracts.TripEventRated,
+		contracts.AmqpMessage{
+			OwnerID: payload.DriverID,
+			Data:    ratingJSON,
+		},
+	)
+}
+
+// PublishCancellationFee asks the payment service to charge the rider the trip's cancellation fee.
+// The fee is sent as a cancellation_fee line item: it pays the dispatched driver for the drive
+// to the pickup, so payment service credits them the fee minus the usual commission.
+func (p *TripEventPublisher) PublishCancellationFee(ctx context.Context, trip *domain.TripModel) error {
+	if trip.Cancellation == nil || !trip.Cancellation.Fee.IsPositive() {
+		return nil
+	}
+
+	payload := messaging.PaymentTripResponseData{
+		TripID:      trip.ID.Hex(),
+		UserID:      trip.UserID,
+		DriverID:    trip.Driver.GetId(),
+		Amount:      trip.Cancellation.Fee,
+		Description: "Cancellation fee",
+		LineItems: []messaging.PaymentLineItem{{
+			Type:        string(domain.FareLineItemCancellationFee),
+			Description: "Cancellation fee",
+			Amount:      trip.Cancellation.Fee,
+		}},
+	}
+
+	paymentJSON, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return p.rabbitmq.PublishMessage(
+		ctx,
+		contracts.PaymentCmdCreateSession,
+		contracts.AmqpMessage{
+			OwnerID: trip.UserID,
+			Data:    paymentJSON,
+		},
+	)
+}
+
+// PublishTripUpdated tells the assigned driver, if any, that the rider changed the pickup or the stops of the trip.
+func (p *TripEventPublisher) PublishTripUpdated(ctx context.Context, trip *domain.TripModel) error {
+	driverID := trip.Driver.GetId()
+	if driverID == "" {
+		return nil
+	}
+
+	tripEventJSON, err := json.Marshal(messaging.TripEventData{
+		Trip: trip.ToProto(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.rabbitmq.PublishMessage(
+		ctx,
+		contracts.TripEventUpdated,
+		contracts.AmqpMessage{
+			OwnerID: driverID,
+			Data:    tripEventJSON,
+		},
+	)
+}
@@ -0,0 +1,48 @@
+package grpc
+
+import (
+	"context"
+	pb "ride-sharing/shared/proto/driver"
+	"ride-sharing/shared/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// driverPoolClient asks driver service which drivers are online.
+type driverPoolClient struct {
+	client pb.DriverServiceClient
+	conn   *grpc.ClientConn
+}
+
+func NewDriverPoolClient(driverServiceURL string) (*driverPoolClient, error) {
+	dialOpts := append(
+		tracing.DialOptionsWithTracing(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	conn, err := grpc.NewClient(driverServiceURL, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &driverPoolClient{
+		client: pb.NewDriverServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (c *driverPoolClient) AvailableDrivers(ctx context.Context) ([]*pb.Driver, error) {
+	res, err := c.client.ListAvailableDrivers(ctx, &pb.ListAvailableDriversRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Drivers, nil
+}
+
+func (c *driverPoolClient) Close() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
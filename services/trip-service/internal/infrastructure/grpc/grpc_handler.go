@@ -2,9 +2,11 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"log"
 	"ride-sharing/services/trip-service/internal/domain"
 	"ride-sharing/services/trip-service/internal/infrastructure/events"
+	"ride-sharing/shared/auth"
 	pb "ride-sharing/shared/proto/trip"
 	"ride-sharing/shared/types"
 
@@ -32,20 +34,28 @@ func NewGRPCHandler(server *grpc.Server, service domain.TripService, publisher *
 }
 
 func (h *grpcHandler) CreateTrip(ctx context.Context, req *pb.CreateTripRequest) (*pb.CreateTripResponse, error) {
+	if err := auth.CheckUser(ctx, req.GetUserID()); err != nil {
+		return nil, err
+	}
+
 	fareID := req.GetRideFareID()
 	userID := req.GetUserID()
 
 	rideFare, err := h.service.GetAndValidateFare(ctx, fareID, userID)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to validate fare: %v", err)
+		return nil, status.Errorf(fareErrorCode(err), "failed to validate fare: %v", err)
 	}
 
 	trip, err := h.service.CreateTrip(ctx, rideFare)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create trip: %v", err)
+		return nil, status.Errorf(fareErrorCode(err), "failed to create trip: %v", err)
 	}
 
 	if err := h.publisher.PublishTripCreated(ctx, trip); err != nil {
+		// Nobody will dispatch the trip, expiring it gives back the fare and promotion so the rider can retry
+		if expireErr := h.service.UpdateTripStatus(ctx, trip.ID.Hex(), domain.TripStatusExpired, domain.TripActor{Role: domain.ActorSystem}, nil); expireErr != nil {
+			log.Printf("Failed to expire undispatched trip %s: %v", trip.ID.Hex(), expireErr)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to publish trip created event: %v", err)
 	}
 
@@ -55,6 +65,10 @@ func (h *grpcHandler) CreateTrip(ctx context.Context, req *pb.CreateTripRequest)
 }
 
 func (h *grpcHandler) PreviewTrip(ctx context.Context, req *pb.PreviewTripRequest) (*pb.PreviewTripResponse, error) {
+	if err := auth.CheckUser(ctx, req.GetUserID()); err != nil {
+		return nil, err
+	}
+
 	pickupProto := req.GetStartLocation()
 	destinationProto := req.GetEndLocation()
 
@@ -74,7 +88,14 @@ func (h *grpcHandler) PreviewTrip(ctx context.Context, req *pb.PreviewTripReques
 		return nil, status.Errorf(codes.Internal, "failed to get route: %v", err)
 	}
 
-	estimatedFares := h.service.EstimatePackagesPriceWithRoute(route)
+	estimatedFares, err := h.service.EstimatePackagesPriceWithRoute(ctx, req.GetUserID(), pickup, route, req.GetPromoCode())
+	if err != nil {
+		if code := promotionErrorCode(err); code != codes.Internal {
+			return nil, status.Errorf(code, "failed to apply promo code: %v", err)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to estimate fares: %v", err)
+	}
+
 	fares, err := h.service.GenerateTripFares(ctx, estimatedFares, req.GetUserID(), route)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to generate ride fares: %v", err)
@@ -85,3 +106,198 @@ func (h *grpcHandler) PreviewTrip(ctx context.Context, req *pb.PreviewTripReques
 		RideFares: domain.ToRideFaresProto(fares),
 	}, nil
 }
+
+func (h *grpcHandler) ListPackages(ctx context.Context, req *pb.ListPackagesRequest) (*pb.ListPackagesResponse, error) {
+	var location *types.Coordinate
+	if loc := req.GetLocation(); loc != nil {
+		location = &types.Coordinate{
+			Latitude:  loc.Latitude,
+			Longitude: loc.Longitude,
+		}
+	}
+
+	area, err := h.service.ListPackages(ctx, req.GetServiceArea(), location)
+	if err != nil {
+		if errors.Is(err, domain.ErrServiceAreaNotFound) {
+			return nil, status.Errorf(codes.NotFound, "failed to list packages: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to list packages: %v", err)
+	}
+
+	packages := make([]*pb.CarPackage, len(area.Packages))
+	for i, pkg := range area.Packages {
+		packages[i] = pkg.ToProto()
+	}
+
+	return &pb.ListPackagesResponse{
+		ServiceArea: area.Slug,
+		Packages:    packages,
+	}, nil
+}
+
+func (h *grpcHandler) CancelTrip(ctx context.Context, req *pb.CancelTripRequest) (*pb.CancelTripResponse, error) {
+	if err := auth.CheckUser(ctx, req.GetUserID()); err != nil {
+		return nil, err
+	}
+
+	// The role decides the fee, callers cannot cancel as the other party
+	if err := auth.CheckRole(ctx, auth.Role(req.GetRole()), auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	actor := domain.TripActor{
+		Role: req.GetRole(),
+		ID:   req.GetUserID(),
+	}
+
+	trip, err := h.service.CancelTrip(ctx, req.GetTripID(), actor, req.GetReason())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTripNotFound):
+			return nil, status.Errorf(codes.NotFound, "failed to cancel trip: %v", err)
+		case errors.Is(err, domain.ErrNotTripParticipant):
+			return nil, status.Errorf(codes.PermissionDenied, "failed to cancel trip: %v", err)
+		case errors.Is(err, domain.ErrInvalidTripTransition):
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to cancel trip: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to cancel trip: %v", err)
+	}
+
+	if err := h.publisher.PublishTripCancelled(ctx, trip); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to publish trip cancelled event: %v", err)
+	}
+
+	if err := h.publisher.PublishCancellationFee(ctx, trip); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to publish cancellation fee: %v", err)
+	}
+
+	return &pb.CancelTripResponse{
+		Trip:            trip.ToProto(),
+		CancellationFee: trip.Cancellation.Fee.ToProto(),
+	}, nil
+}
+
+func (h *grpcHandler) RateTrip(ctx context.Context, req *pb.RateTripRequest) (*pb.RateTripResponse, error) {
+	if err := auth.CheckUser(ctx, req.GetUserID()); err != nil {
+		return nil, err
+	}
+
+	trip, err := h.service.RateTrip(ctx, req.GetTripID(), req.GetUserID(), int(req.GetRating()), req.GetComment())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidRating):
+			return nil, status.Errorf(codes.InvalidArgument, "failed to rate trip: %v", err)
+		case errors.Is(err, domain.ErrTripNotFound):
+			return nil, status.Errorf(codes.NotFound, "failed to rate trip: %v", err)
+		case errors.Is(err, domain.ErrNotTripParticipant):
+			return nil, status.Errorf(codes.PermissionDenied, "failed to rate trip: %v", err)
+		case errors.Is(err, domain.ErrTripNotRateable):
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to rate trip: %v", err)
+		case errors.Is(err, domain.ErrTripAlreadyRated):
+			return nil, status.Errorf(codes.AlreadyExists, "failed to rate trip: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to rate trip: %v", err)
+	}
+
+	if err := h.publisher.PublishTripRated(ctx, trip); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to publish trip rated event: %v", err)
+	}
+
+	return &pb.RateTripResponse{
+		Trip: trip.ToProto(),
+	}, nil
+}
+
+func (h *grpcHandler) UpdatePickup(ctx context.Context, req *pb.UpdatePickupRequest) (*pb.UpdateTripResponse, error) {
+	if err := auth.CheckUser(ctx, req.GetUserID()); err != nil {
+		return nil, err
+	}
+
+	trip, err := h.service.UpdatePickup(ctx, req.GetTripID(), req.GetUserID(), coordinateFromProto(req.GetPickup()))
+	if err != nil {
+		return nil, status.Errorf(tripChangeErrorCode(err), "failed to update pickup: %v", err)
+	}
+
+	return h.tripUpdated(ctx, trip)
+}
+
+func (h *grpcHandler) AddStop(ctx context.Context, req *pb.AddStopRequest) (*pb.UpdateTripResponse, error) {
+	if err := auth.CheckUser(ctx, req.GetUserID()); err != nil {
+		return nil, err
+	}
+
+	trip, err := h.service.AddStop(ctx, req.GetTripID(), req.GetUserID(), coordinateFromProto(req.GetLocation()))
+	if err != nil {
+		return nil, status.Errorf(tripChangeErrorCode(err), "failed to add stop: %v", err)
+	}
+
+	return h.tripUpdated(ctx, trip)
+}
+
+func (h *grpcHandler) tripUpdated(ctx context.Context, trip *domain.TripModel) (*pb.UpdateTripResponse, error) {
+	if err := h.publisher.PublishTripUpdated(ctx, trip); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to publish trip updated event: %v", err)
+	}
+
+	return &pb.UpdateTripResponse{
+		Trip: trip.ToProto(),
+	}, nil
+}
+
+func coordinateFromProto(c *pb.Coordinate) *types.Coordinate {
+	if c == nil {
+		return nil
+	}
+
+	return &types.Coordinate{
+		Latitude:  c.Latitude,
+		Longitude: c.Longitude,
+	}
+}
+
+// tripChangeErrorCode maps the errors of changing a trip to the gRPC status code returned to clients.
+func tripChangeErrorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, domain.ErrInvalidLocation), errors.Is(err, domain.ErrPickupTooFar):
+		return codes.InvalidArgument
+	case errors.Is(err, domain.ErrTripNotFound):
+		return codes.NotFound
+	case errors.Is(err, domain.ErrNotTripParticipant):
+		return codes.PermissionDenied
+	case errors.Is(err, domain.ErrTripNotEditable), errors.Is(err, domain.ErrTooManyStops):
+		return codes.FailedPrecondition
+	}
+
+	return codes.Internal
+}
+
+// fareErrorCode maps fare validation errors to the gRPC status code returned to clients.
+func fareErrorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, domain.ErrFareNotFound):
+		return codes.NotFound
+	case errors.Is(err, domain.ErrFareNotOwned):
+		return codes.PermissionDenied
+	case errors.Is(err, domain.ErrFareExpired):
+		return codes.FailedPrecondition
+	case errors.Is(err, domain.ErrFareAlreadyUsed):
+		return codes.AlreadyExists
+	}
+
+	return promotionErrorCode(err)
+}
+
+// promotionErrorCode maps promo code errors to the gRPC status code returned to clients.
+func promotionErrorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, domain.ErrPromotionNotFound):
+		return codes.NotFound
+	case errors.Is(err, domain.ErrPromotionExpired),
+		errors.Is(err, domain.ErrPromotionFirstRideOnly):
+		return codes.FailedPrecondition
+	case errors.Is(err, domain.ErrPromotionLimitReached):
+		return codes.ResourceExhausted
+	}
+
+	return codes.Internal
+}
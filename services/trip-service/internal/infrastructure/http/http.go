@@ -13,7 +13,7 @@ type HttpHandler struct {
 }
 
 type previewTripRequest struct {
-	UserID      string           `json:"userID`
+	UserID      string           `json:"userID"`
 	Pickup      types.Coordinate `json:"pickup"`
 	Destination types.Coordinate `json:"destination"`
 }
@@ -4,14 +4,18 @@ import (
 	"context"
 	"fmt"
 	"ride-sharing/services/trip-service/internal/domain"
+	"sync"
+	"time"
 
 	pbd "ride-sharing/shared/proto/driver"
 	pb "ride-sharing/shared/proto/trip"
+	"ride-sharing/shared/types"
 )
 
 type inmemRepository struct {
 	trips     map[string]*domain.TripModel
 	rideFares map[string]*domain.RideFareModel
+	mu        sync.RWMutex
 }
 
 func NewInmemRepository() *inmemRepository {
@@ -22,27 +26,78 @@ func NewInmemRepository() *inmemRepository {
 }
 
 func (r *inmemRepository) GetRideFareByID(ctx context.Context, id string) (*domain.RideFareModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	fare, exist := r.rideFares[id]
 	if !exist {
-		return nil, fmt.Errorf("fare does not exist with ID: %s", id)
+		return nil, fmt.Errorf("%w: %s", domain.ErrFareNotFound, id)
 	}
 
 	return fare, nil
 }
 
+func (r *inmemRepository) ConsumeRideFare(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fare, exist := r.rideFares[id]
+	if !exist {
+		return fmt.Errorf("%w: %s", domain.ErrFareNotFound, id)
+	}
+
+	now := time.Now()
+	if fare.Consumed {
+		return domain.ErrFareAlreadyUsed
+	}
+
+	if fare.IsExpired(now) {
+		return domain.ErrFareExpired
+	}
+
+	fare.Consumed = true
+	fare.ConsumedAt = now
+
+	return nil
+}
+
+func (r *inmemRepository) ReleaseRideFare(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fare, exist := r.rideFares[id]
+	if !exist {
+		return fmt.Errorf("%w: %s", domain.ErrFareNotFound, id)
+	}
+
+	fare.Consumed = false
+	fare.ConsumedAt = time.Time{}
+
+	return nil
+}
+
 func (r *inmemRepository) CreateTrip(ctx context.Context, trip *domain.TripModel) (*domain.TripModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	r.trips[trip.ID.Hex()] = trip
 
 	return trip, nil
 }
 
 func (r *inmemRepository) SaveRideFare(ctx context.Context, fare *domain.RideFareModel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	r.rideFares[fare.ID.Hex()] = fare
 
 	return nil
 }
 
 func (r *inmemRepository) GetTripByID(ctx context.Context, id string) (*domain.TripModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	trip, ok := r.trips[id]
 	if !ok {
 		return nil, nil
@@ -50,13 +105,28 @@ func (r *inmemRepository) GetTripByID(ctx context.Context, id string) (*domain.T
 	return trip, nil
 }
 
-func (r *inmemRepository) UpdateTrip(ctx context.Context, tripID string, status string, driver *pbd.Driver) error {
-	trip, ok := r.trips[tripID]
-	if !ok {
-		return fmt.Errorf("trip not found with ID: %s", tripID)
+func (r *inmemRepository) CountUserTrips(ctx context.Context, userID string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, trip := range r.trips {
+		if trip.UserID == userID && trip.Status != domain.TripStatusCancelled && trip.Status != domain.TripStatusExpired {
+			count++
+		}
 	}
 
-	trip.Status = status
+	return count, nil
+}
+
+func (r *inmemRepository) UpdateTripStatus(ctx context.Context, tripID string, transition *domain.TripStatusTransition, driver *pbd.Driver) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trip, err := r.transitionTrip(tripID, transition)
+	if err != nil {
+		return err
+	}
 
 	if driver != nil {
 		trip.Driver = &pb.TripDriver{
@@ -68,3 +138,92 @@ func (r *inmemRepository) UpdateTrip(ctx context.Context, tripID string, status
 	}
 	return nil
 }
+
+func (r *inmemRepository) CancelTrip(ctx context.Context, tripID string, transition *domain.TripStatusTransition, cancellation *domain.TripCancellation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trip, err := r.transitionTrip(tripID, transition)
+	if err != nil {
+		return err
+	}
+
+	trip.Cancellation = cancellation
+	return nil
+}
+
+func (r *inmemRepository) RateTrip(ctx context.Context, tripID string, rating *domain.TripRating) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trip, ok := r.trips[tripID]
+	if !ok {
+		return fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+	}
+
+	if !trip.Status.IsRateable() {
+		return fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotRateable, tripID, trip.Status)
+	}
+
+	if trip.Rating != nil {
+		return fmt.Errorf("%w: %s", domain.ErrTripAlreadyRated, tripID)
+	}
+
+	trip.Rating = rating
+	return nil
+}
+
+func (r *inmemRepository) UpdatePickup(ctx context.Context, tripID string, pickup *types.Coordinate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trip, ok := r.trips[tripID]
+	if !ok {
+		return fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+	}
+
+	if !trip.Status.CanMovePickup() {
+		return fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotEditable, tripID, trip.Status)
+	}
+
+	trip.Pickup = pickup
+	return nil
+}
+
+func (r *inmemRepository) AddStop(ctx context.Context, tripID string, stop *domain.TripStop, maxStops int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trip, ok := r.trips[tripID]
+	if !ok {
+		return fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+	}
+
+	if !trip.Status.CanAddStop() {
+		return fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotEditable, tripID, trip.Status)
+	}
+
+	if len(trip.Stops) >= maxStops {
+		return fmt.Errorf("%w: trip %s already has %d", domain.ErrTooManyStops, tripID, len(trip.Stops))
+	}
+
+	trip.Stops = append(trip.Stops, stop)
+	return nil
+}
+
+// transitionTrip must be called with the write lock held.
+func (r *inmemRepository) transitionTrip(tripID string, transition *domain.TripStatusTransition) (*domain.TripModel, error) {
+	trip, ok := r.trips[tripID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+	}
+
+	if trip.Status != transition.From || !trip.Status.CanTransitionTo(transition.To) {
+		return nil, &domain.TripTransitionError{TripID: tripID, From: trip.Status, To: transition.To}
+	}
+
+	trip.Status = transition.To
+	trip.History = append(trip.History, transition)
+
+	return trip, nil
+}
@@ -2,15 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"ride-sharing/services/trip-service/internal/domain"
 	"ride-sharing/shared/db"
 	pbd "ride-sharing/shared/proto/driver"
+	"ride-sharing/shared/types"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type mongoRepository struct {
@@ -52,25 +56,169 @@ func (r *mongoRepository) GetTripByID(ctx context.Context, id string) (*domain.T
 	return &trip, nil
 }
 
-func (r *mongoRepository) UpdateTrip(ctx context.Context, tripID string, status string, driver *pbd.Driver) error {
+func (r *mongoRepository) CountUserTrips(ctx context.Context, userID string) (int, error) {
+	count, err := r.db.Collection(db.TripsCollection).CountDocuments(ctx, bson.M{
+		"userID": userID,
+		"status": bson.M{"$nin": []domain.TripStatus{domain.TripStatusCancelled, domain.TripStatusExpired}},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (r *mongoRepository) UpdateTripStatus(ctx context.Context, tripID string, transition *domain.TripStatusTransition, driver *pbd.Driver) error {
+	set := bson.M{}
+	if driver != nil {
+		set["driver"] = driver
+	}
+
+	return r.transitionTrip(ctx, tripID, transition, set)
+}
+
+func (r *mongoRepository) CancelTrip(ctx context.Context, tripID string, transition *domain.TripStatusTransition, cancellation *domain.TripCancellation) error {
+	return r.transitionTrip(ctx, tripID, transition, bson.M{"cancellation": cancellation})
+}
+
+func (r *mongoRepository) RateTrip(ctx context.Context, tripID string, rating *domain.TripRating) error {
 	_id, err := primitive.ObjectIDFromHex(tripID)
 	if err != nil {
 		return err
 	}
 
-	update := bson.M{"$set": bson.M{"status": status}}
+	// The filter makes rating a trip twice impossible, even concurrently
+	filter := bson.M{
+		"_id":    _id,
+		"status": bson.M{"$in": []domain.TripStatus{domain.TripStatusCompleted, domain.TripStatusPaid}},
+		"rating": bson.M{"$exists": false},
+	}
 
-	if driver != nil {
-		update["$set"].(bson.M)["driver"] = driver
+	result, err := r.db.Collection(db.TripsCollection).UpdateOne(ctx, filter, bson.M{"$set": bson.M{"rating": rating}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		trip, err := r.GetTripByID(ctx, tripID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+			}
+			return err
+		}
+
+		if trip.Rating != nil {
+			return fmt.Errorf("%w: %s", domain.ErrTripAlreadyRated, tripID)
+		}
+		return fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotRateable, tripID, trip.Status)
+	}
+
+	return nil
+}
+
+var (
+	pickupMovableStatuses = []domain.TripStatus{domain.TripStatusRequested, domain.TripStatusDriverAssigned, domain.TripStatusDriverArriving}
+	stopAddableStatuses   = append(pickupMovableStatuses, domain.TripStatusInProgress)
+)
+
+func (r *mongoRepository) UpdatePickup(ctx context.Context, tripID string, pickup *types.Coordinate) error {
+	return r.updateTrip(ctx, tripID,
+		bson.M{"status": bson.M{"$in": pickupMovableStatuses}},
+		bson.M{"$set": bson.M{"pickup": pickup}},
+		func(trip *domain.TripModel) error {
+			return fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotEditable, tripID, trip.Status)
+		},
+	)
+}
+
+func (r *mongoRepository) AddStop(ctx context.Context, tripID string, stop *domain.TripStop, maxStops int) error {
+	if maxStops <= 0 {
+		return fmt.Errorf("%w: trip %s can't have stops", domain.ErrTooManyStops, tripID)
 	}
 
-	result, err := r.db.Collection(db.TripsCollection).UpdateOne(ctx, bson.M{"_id": _id}, update)
+	return r.updateTrip(ctx, tripID,
+		bson.M{
+			"status": bson.M{"$in": stopAddableStatuses},
+			// The trip has fewer than maxStops if the stop at index maxStops-1 doesn't exist
+			fmt.Sprintf("stops.%d", maxStops-1): bson.M{"$exists": false},
+		},
+		bson.M{"$push": bson.M{"stops": stop}},
+		func(trip *domain.TripModel) error {
+			if !trip.Status.CanAddStop() {
+				return fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotEditable, tripID, trip.Status)
+			}
+			return fmt.Errorf("%w: trip %s already has %d", domain.ErrTooManyStops, tripID, len(trip.Stops))
+		},
+	)
+}
+
+// updateTrip applies the update to the trip if it matches the conditions.
+// When it doesn't, rejected explains why from the current state of the trip.
+func (r *mongoRepository) updateTrip(ctx context.Context, tripID string, conditions, update bson.M, rejected func(trip *domain.TripModel) error) error {
+	_id, err := primitive.ObjectIDFromHex(tripID)
 	if err != nil {
 		return err
 	}
 
-	if result.ModifiedCount == 0 {
-		return fmt.Errorf("trip not found: %s", tripID)
+	conditions["_id"] = _id
+	result, err := r.db.Collection(db.TripsCollection).UpdateOne(ctx, conditions, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	trip, err := r.GetTripByID(ctx, tripID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+		}
+		return err
+	}
+
+	return rejected(trip)
+}
+
+// transitionTrip moves the trip along the given transition, applying the extra fields in set.
+func (r *mongoRepository) transitionTrip(ctx context.Context, tripID string, transition *domain.TripStatusTransition, set bson.M) error {
+	_id, err := primitive.ObjectIDFromHex(tripID)
+	if err != nil {
+		return err
+	}
+
+	if !transition.From.CanTransitionTo(transition.To) {
+		return &domain.TripTransitionError{TripID: tripID, From: transition.From, To: transition.To}
+	}
+
+	set["status"] = transition.To
+
+	update := bson.M{
+		"$set":  set,
+		"$push": bson.M{"history": transition},
+	}
+
+	// Only match the trip while it is still in the status the transition was validated against,
+	// so concurrent updates cannot move it along an illegal path.
+	filter := bson.M{"_id": _id, "status": transition.From}
+
+	result, err := r.db.Collection(db.TripsCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		trip, err := r.GetTripByID(ctx, tripID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+			}
+			return err
+		}
+
+		return &domain.TripTransitionError{TripID: tripID, From: trip.Status, To: transition.To}
 	}
 
 	return nil
@@ -95,6 +243,9 @@ func (r *mongoRepository) GetRideFareByID(ctx context.Context, id string) (*doma
 
 	result := r.db.Collection(db.RideFaresCollection).FindOne(ctx, bson.M{"_id": _id})
 	if result.Err() != nil {
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrFareNotFound, id)
+		}
 		return nil, result.Err()
 	}
 
@@ -106,3 +257,73 @@ func (r *mongoRepository) GetRideFareByID(ctx context.Context, id string) (*doma
 
 	return &fare, nil
 }
+
+func (r *mongoRepository) ConsumeRideFare(ctx context.Context, id string) error {
+	_id, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	filter := bson.M{
+		"_id":       _id,
+		"consumed":  bson.M{"$ne": true},
+		"expiresAt": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{"consumed": true, "consumedAt": now}}
+
+	result, err := r.db.Collection(db.RideFaresCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	// Find out why the fare could not be consumed
+	fare, err := r.GetRideFareByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if fare.Consumed {
+		return domain.ErrFareAlreadyUsed
+	}
+
+	return domain.ErrFareExpired
+}
+
+func (r *mongoRepository) ReleaseRideFare(ctx context.Context, id string) error {
+	_id, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set":   bson.M{"consumed": false},
+		"$unset": bson.M{"consumedAt": ""},
+	}
+
+	result, err := r.db.Collection(db.RideFaresCollection).UpdateOne(ctx, bson.M{"_id": _id}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrFareNotFound, id)
+	}
+
+	return nil
+}
+
+// EnsureIndexes creates the indexes the repository relies on.
+// Ride fares are removed by a TTL index once they have been expired for longer than fareRetention.
+func (r *mongoRepository) EnsureIndexes(ctx context.Context, fareRetention time.Duration) error {
+	_, err := r.db.Collection(db.RideFaresCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(fareRetention.Seconds())),
+	})
+
+	return err
+}
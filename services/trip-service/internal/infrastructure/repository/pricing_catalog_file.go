@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+// filePricingCatalogSource reads the pricing catalog from a JSON file.
+type filePricingCatalogSource struct {
+	path string
+}
+
+func NewFilePricingCatalogSource(path string) *filePricingCatalogSource {
+	return &filePricingCatalogSource{path: path}
+}
+
+func (s *filePricingCatalogSource) LoadPricingCatalog(ctx context.Context) (*domain.PricingCatalog, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing catalog file: %w", err)
+	}
+
+	var catalog domain.PricingCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse pricing catalog file: %w", err)
+	}
+
+	return &catalog, nil
+}
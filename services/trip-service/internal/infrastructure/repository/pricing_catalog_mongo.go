@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoPricingCatalogSource reads the pricing catalog from a collection holding one document per service area.
+type mongoPricingCatalogSource struct {
+	db *mongo.Database
+}
+
+func NewMongoPricingCatalogSource(db *mongo.Database) *mongoPricingCatalogSource {
+	return &mongoPricingCatalogSource{db: db}
+}
+
+func (s *mongoPricingCatalogSource) LoadPricingCatalog(ctx context.Context) (*domain.PricingCatalog, error) {
+	cursor, err := s.db.Collection(db.PricingCatalogCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	var areas []*domain.ServiceArea
+	if err := cursor.All(ctx, &areas); err != nil {
+		return nil, err
+	}
+
+	return &domain.PricingCatalog{Areas: areas}, nil
+}
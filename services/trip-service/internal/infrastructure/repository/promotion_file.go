@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+// SeedPromotionsFromFile saves the promotions listed in a JSON file, replacing existing ones with the same code.
+func SeedPromotionsFromFile(ctx context.Context, repo domain.PromotionRepository, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read promotions file: %w", err)
+	}
+
+	var promotions []*domain.Promotion
+	if err := json.Unmarshal(data, &promotions); err != nil {
+		return 0, fmt.Errorf("failed to parse promotions file: %w", err)
+	}
+
+	for _, promotion := range promotions {
+		promotion.Code = domain.NormalizePromoCode(promotion.Code)
+		if err := repo.SavePromotion(ctx, promotion); err != nil {
+			return 0, fmt.Errorf("failed to save promotion %s: %w", promotion.Code, err)
+		}
+	}
+
+	return len(promotions), nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+type inmemPromotionRepository struct {
+	promotions map[string]*domain.Promotion
+	// redemptions counts the uses of every promotion per user, keyed by redemptionKey
+	redemptions map[string]int
+	// firstRides holds the redemptionKey of the first ride promotion each user redeemed
+	firstRides map[string]string
+	mu         sync.RWMutex
+}
+
+func NewInmemPromotionRepository() *inmemPromotionRepository {
+	return &inmemPromotionRepository{
+		promotions:  make(map[string]*domain.Promotion),
+		redemptions: make(map[string]int),
+		firstRides:  make(map[string]string),
+	}
+}
+
+func (r *inmemPromotionRepository) GetPromotion(ctx context.Context, code string) (*domain.Promotion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	promotion, ok := r.promotions[code]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrPromotionNotFound, code)
+	}
+
+	return promotion, nil
+}
+
+func (r *inmemPromotionRepository) SavePromotion(ctx context.Context, promotion *domain.Promotion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.promotions[promotion.Code] = promotion
+
+	return nil
+}
+
+func (r *inmemPromotionRepository) CountRedemptions(ctx context.Context, code, userID string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.redemptions[redemptionKey(code, userID)], nil
+}
+
+func (r *inmemPromotionRepository) RedeemPromotion(ctx context.Context, code, userID string, maxUses int, firstRideOnly bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := redemptionKey(code, userID)
+	if firstRideOnly {
+		maxUses = 1
+	}
+
+	if maxUses > 0 && r.redemptions[key] >= maxUses {
+		return domain.ErrPromotionLimitReached
+	}
+
+	if firstRideOnly {
+		if holder, ok := r.firstRides[userID]; ok && holder != key {
+			return fmt.Errorf("%w: %s", domain.ErrPromotionFirstRideOnly, code)
+		}
+		r.firstRides[userID] = key
+	}
+
+	r.redemptions[key]++
+
+	return nil
+}
+
+func (r *inmemPromotionRepository) ReleasePromotion(ctx context.Context, code, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := redemptionKey(code, userID)
+	if r.redemptions[key] > 0 {
+		r.redemptions[key]--
+	}
+
+	if r.redemptions[key] == 0 && r.firstRides[userID] == key {
+		delete(r.firstRides, userID)
+	}
+
+	return nil
+}
+
+func redemptionKey(code, userID string) string {
+	return code + ":" + userID
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// firstRideIndex keeps users from holding the redemptions of several first ride promotions at once
+const firstRideIndex = "first_ride_redemption"
+
+type mongoPromotionRepository struct {
+	db *mongo.Database
+}
+
+// promotionRedemption counts the uses of a promotion by a user, one document per code and user.
+type promotionRedemption struct {
+	ID        string    `bson:"_id"`
+	Code      string    `bson:"code"`
+	UserID    string    `bson:"userID"`
+	Count     int       `bson:"count"`
+	FirstRide bool      `bson:"firstRide"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+func NewMongoPromotionRepository(db *mongo.Database) *mongoPromotionRepository {
+	return &mongoPromotionRepository{db: db}
+}
+
+// EnsureIndexes creates the indexes the repository relies on.
+func (r *mongoPromotionRepository) EnsureIndexes(ctx context.Context) error {
+	// Released redemptions drop to a count of 0 and leave the index, so the user can redeem one again
+	_, err := r.db.Collection(db.PromotionRedemptionsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "userID", Value: 1}},
+		Options: options.Index().
+			SetName(firstRideIndex).
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"firstRide": true, "count": bson.M{"$gt": 0}}),
+	})
+
+	return err
+}
+
+func (r *mongoPromotionRepository) GetPromotion(ctx context.Context, code string) (*domain.Promotion, error) {
+	var promotion domain.Promotion
+	err := r.db.Collection(db.PromotionsCollection).FindOne(ctx, bson.M{"_id": code}).Decode(&promotion)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrPromotionNotFound, code)
+		}
+		return nil, err
+	}
+
+	return &promotion, nil
+}
+
+func (r *mongoPromotionRepository) SavePromotion(ctx context.Context, promotion *domain.Promotion) error {
+	_, err := r.db.Collection(db.PromotionsCollection).ReplaceOne(
+		ctx,
+		bson.M{"_id": promotion.Code},
+		promotion,
+		options.Replace().SetUpsert(true),
+	)
+
+	return err
+}
+
+func (r *mongoPromotionRepository) CountRedemptions(ctx context.Context, code, userID string) (int, error) {
+	var redemption promotionRedemption
+	err := r.db.Collection(db.PromotionRedemptionsCollection).FindOne(ctx, bson.M{"_id": redemptionKey(code, userID)}).Decode(&redemption)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return redemption.Count, nil
+}
+
+func (r *mongoPromotionRepository) RedeemPromotion(ctx context.Context, code, userID string, maxUses int, firstRideOnly bool) error {
+	if firstRideOnly {
+		maxUses = 1
+	}
+
+	filter := bson.M{"_id": redemptionKey(code, userID)}
+	if maxUses > 0 {
+		filter["count"] = bson.M{"$lt": maxUses}
+	}
+
+	update := bson.M{
+		"$inc": bson.M{"count": 1},
+		"$set": bson.M{"code": code, "userID": userID, "firstRide": firstRideOnly, "updatedAt": time.Now()},
+	}
+
+	// When the user reached the limit the filter does not match the existing document,
+	// so the upsert collides with it on _id instead of counting one more use.
+	// A first ride redemption collides on firstRideIndex with the one of another first ride promotion.
+	_, err := r.db.Collection(db.PromotionRedemptionsCollection).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		if strings.Contains(err.Error(), firstRideIndex) {
+			return fmt.Errorf("%w: %s", domain.ErrPromotionFirstRideOnly, code)
+		}
+		return domain.ErrPromotionLimitReached
+	}
+
+	return err
+}
+
+func (r *mongoPromotionRepository) ReleasePromotion(ctx context.Context, code, userID string) error {
+	_, err := r.db.Collection(db.PromotionRedemptionsCollection).UpdateOne(
+		ctx,
+		bson.M{"_id": redemptionKey(code, userID), "count": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"count": -1}, "$set": bson.M{"updatedAt": time.Now()}},
+	)
+
+	return err
+}
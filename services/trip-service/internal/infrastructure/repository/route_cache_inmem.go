@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+)
+
+type lruEntry struct {
+	key       string
+	route     *tripTypes.OsrmApiResponse
+	expiresAt time.Time
+}
+
+// inmemRouteCache is a least recently used route cache bounded by entry count and TTL.
+type inmemRouteCache struct {
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // front is the most recently used entry
+	mu         sync.Mutex
+}
+
+func NewInmemRouteCache(ttl time.Duration, maxEntries int) *inmemRouteCache {
+	return &inmemRouteCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (c *inmemRouteCache) Get(ctx context.Context, key string) (*tripTypes.OsrmApiResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil, nil
+	}
+
+	c.order.MoveToFront(elem)
+
+	return entry.route, nil
+}
+
+func (c *inmemRouteCache) Set(ctx context.Context, key string, route *tripTypes.OsrmApiResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(c.ttl)
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.route = route
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{
+		key:       key,
+		route:     route,
+		expiresAt: expiresAt,
+	})
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+	}
+
+	return nil
+}
+
+func (c *inmemRouteCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*lruEntry).key)
+}
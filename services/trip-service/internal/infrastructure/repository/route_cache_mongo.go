@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type routeCacheDocument struct {
+	Key       string                     `bson:"_id"`
+	Route     *tripTypes.OsrmApiResponse `bson:"route"`
+	ExpiresAt time.Time                  `bson:"expiresAt"`
+}
+
+// mongoRouteCache shares cached routes between trip service instances.
+// Expired entries are removed by a TTL index, the size bound is enforced on write.
+type mongoRouteCache struct {
+	db         *mongo.Database
+	ttl        time.Duration
+	maxEntries int64
+}
+
+func NewMongoRouteCache(ctx context.Context, db *mongo.Database, ttl time.Duration, maxEntries int) (*mongoRouteCache, error) {
+	c := &mongoRouteCache{
+		db:         db,
+		ttl:        ttl,
+		maxEntries: int64(maxEntries),
+	}
+
+	if err := c.ensureIndexes(ctx); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *mongoRouteCache) ensureIndexes(ctx context.Context) error {
+	_, err := c.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+
+	return err
+}
+
+func (c *mongoRouteCache) Get(ctx context.Context, key string) (*tripTypes.OsrmApiResponse, error) {
+	// The TTL monitor only runs periodically, so filter out expired entries ourselves
+	filter := bson.M{"_id": key, "expiresAt": bson.M{"$gt": time.Now()}}
+
+	var doc routeCacheDocument
+	if err := c.collection().FindOne(ctx, filter).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return doc.Route, nil
+}
+
+func (c *mongoRouteCache) Set(ctx context.Context, key string, route *tripTypes.OsrmApiResponse) error {
+	doc := routeCacheDocument{
+		Key:       key,
+		Route:     route,
+		ExpiresAt: time.Now().Add(c.ttl),
+	}
+
+	_, err := c.collection().ReplaceOne(ctx, bson.M{"_id": key}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+
+	return c.evictOverflow(ctx)
+}
+
+// evictOverflow deletes the entries closest to expiry once the cache grows past maxEntries.
+func (c *mongoRouteCache) evictOverflow(ctx context.Context) error {
+	if c.maxEntries <= 0 {
+		return nil
+	}
+
+	count, err := c.collection().EstimatedDocumentCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	overflow := count - c.maxEntries
+	if overflow <= 0 {
+		return nil
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "expiresAt", Value: 1}}).
+		SetLimit(overflow).
+		SetProjection(bson.M{"_id": 1})
+
+	cursor, err := c.collection().Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return err
+	}
+
+	var oldest []routeCacheDocument
+	if err := cursor.All(ctx, &oldest); err != nil {
+		return err
+	}
+
+	keys := make([]string, len(oldest))
+	for i, doc := range oldest {
+		keys[i] = doc.Key
+	}
+
+	_, err = c.collection().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": keys}})
+	return err
+}
+
+func (c *mongoRouteCache) collection() *mongo.Collection {
+	return c.db.Collection(db.RouteCacheCollection)
+}
@@ -0,0 +1,128 @@
+package routing
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+
+	"github.com/mmcloughlin/geohash"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Cache backends accepted in CacheConfig.Backend
+const (
+	CacheBackendNone   = "none"
+	CacheBackendMemory = "memory"
+	CacheBackendMongo  = "mongo"
+)
+
+type CacheConfig struct {
+	Backend string
+	// Precision is the geohash length coordinates are snapped to, 7 is roughly 150m
+	Precision  uint
+	TTL        time.Duration
+	MaxEntries int
+	// StatsInterval is how often the hit and miss counters are logged, 0 disables it
+	StatsInterval time.Duration
+}
+
+func DefaultCacheConfig() *CacheConfig {
+	return &CacheConfig{
+		Backend:       CacheBackendMemory,
+		Precision:     7,
+		TTL:           15 * time.Minute,
+		MaxEntries:    10000,
+		StatsInterval: 5 * time.Minute,
+	}
+}
+
+// CacheStats are the counters of a caching provider
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// cachedProvider serves routes from a cache keyed by the geohash-snapped pickup and destination,
+// so riders previewing the same trip again do not hit the underlying provider.
+type cachedProvider struct {
+	provider  domain.RouteProvider
+	cache     domain.RouteCache
+	precision uint
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func NewCachedProvider(provider domain.RouteProvider, cache domain.RouteCache, precision uint) *cachedProvider {
+	return &cachedProvider{
+		provider:  provider,
+		cache:     cache,
+		precision: precision,
+	}
+}
+
+func (p *cachedProvider) GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*tripTypes.OsrmApiResponse, error) {
+	key := p.cacheKey(pickup, destination)
+	span := trace.SpanFromContext(ctx)
+
+	route, err := p.cache.Get(ctx, key)
+	if err != nil {
+		// A broken cache should not prevent riders from getting a route
+		log.Printf("Failed to read route cache: %v", err)
+	}
+
+	if route != nil {
+		p.hits.Add(1)
+		span.SetAttributes(attribute.Bool("route.cache_hit", true))
+		return route, nil
+	}
+
+	p.misses.Add(1)
+	span.SetAttributes(attribute.Bool("route.cache_hit", false))
+
+	route, err = p.provider.GetRoute(ctx, pickup, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.cache.Set(ctx, key, route); err != nil {
+		log.Printf("Failed to write route cache: %v", err)
+	}
+
+	return route, nil
+}
+
+// Stats returns the cache hit and miss counters since the provider was created.
+func (p *cachedProvider) Stats() CacheStats {
+	return CacheStats{
+		Hits:   p.hits.Load(),
+		Misses: p.misses.Load(),
+	}
+}
+
+// ReportStats logs the counters every interval until the context is cancelled.
+func (p *cachedProvider) ReportStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := p.Stats()
+			log.Printf("Route cache stats: hits=%d misses=%d", stats.Hits, stats.Misses)
+		}
+	}
+}
+
+func (p *cachedProvider) cacheKey(pickup, destination *types.Coordinate) string {
+	return geohash.EncodeWithPrecision(pickup.Latitude, pickup.Longitude, p.precision) + ":" +
+		geohash.EncodeWithPrecision(destination.Latitude, destination.Longitude, p.precision)
+}
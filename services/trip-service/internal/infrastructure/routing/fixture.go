@@ -0,0 +1,41 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+)
+
+// fixtureProvider returns canned routes so the service can run and be tested offline.
+// Unknown coordinate pairs get a deterministic straight route.
+type fixtureProvider struct {
+	routes   map[string]*tripTypes.OsrmApiResponse
+	fallback *haversineProvider
+}
+
+func NewFixtureProvider() *fixtureProvider {
+	return &fixtureProvider{
+		routes:   make(map[string]*tripTypes.OsrmApiResponse),
+		fallback: NewHaversineProvider(DefaultHaversineConfig()),
+	}
+}
+
+// WithRoute registers the route returned for the given pickup and destination.
+func (p *fixtureProvider) WithRoute(pickup, destination *types.Coordinate, route *tripTypes.OsrmApiResponse) *fixtureProvider {
+	p.routes[fixtureKey(pickup, destination)] = route
+	return p
+}
+
+func (p *fixtureProvider) GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*tripTypes.OsrmApiResponse, error) {
+	if route, ok := p.routes[fixtureKey(pickup, destination)]; ok {
+		return route, nil
+	}
+
+	return p.fallback.GetRoute(ctx, pickup, destination)
+}
+
+func fixtureKey(pickup, destination *types.Coordinate) string {
+	return fmt.Sprintf("%.6f,%.6f;%.6f,%.6f", pickup.Latitude, pickup.Longitude, destination.Latitude, destination.Longitude)
+}
@@ -0,0 +1,71 @@
+package routing
+
+import (
+	"context"
+	"math"
+
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+)
+
+const earthRadiusMeters = 6371000.0
+
+// HaversineConfig tunes the straight-line estimation
+type HaversineConfig struct {
+	// AverageSpeedKmh is used to derive the duration from the distance
+	AverageSpeedKmh float64
+	// DetourFactor accounts for roads not being straight lines
+	DetourFactor float64
+}
+
+func DefaultHaversineConfig() *HaversineConfig {
+	return &HaversineConfig{
+		AverageSpeedKmh: 30,
+		DetourFactor:    1.3,
+	}
+}
+
+// haversineProvider estimates a route as the great-circle line between both points.
+// It needs no network access and is used as the fallback when the primary provider fails.
+type haversineProvider struct {
+	config *HaversineConfig
+}
+
+func NewHaversineProvider(config *HaversineConfig) *haversineProvider {
+	return &haversineProvider{config: config}
+}
+
+func (p *haversineProvider) GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*tripTypes.OsrmApiResponse, error) {
+	distance := HaversineDistance(pickup, destination) * p.config.DetourFactor
+	duration := distance / (p.config.AverageSpeedKmh * 1000 / 3600)
+
+	return newStraightRoute(pickup, destination, distance, duration), nil
+}
+
+// HaversineDistance returns the great-circle distance between two coordinates in meters.
+func HaversineDistance(a, b *types.Coordinate) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := (b.Latitude - a.Latitude) * math.Pi / 180
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
+
+// newStraightRoute builds a single-leg route between both points.
+// Coordinates are stored as [longitude, latitude] like the OSRM GeoJSON geometry.
+func newStraightRoute(pickup, destination *types.Coordinate, distance, duration float64) *tripTypes.OsrmApiResponse {
+	route := &tripTypes.OsrmApiResponse{}
+	route.Routes = make([]tripTypes.OsrmRoute, 1)
+	route.Routes[0].Distance = distance
+	route.Routes[0].Duration = duration
+	route.Routes[0].Geometry.Coordinates = [][]float64{
+		{pickup.Longitude, pickup.Latitude},
+		{destination.Longitude, destination.Latitude},
+	}
+
+	return route
+}
@@ -0,0 +1,84 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+)
+
+// OSRMConfig holds the configuration of an OSRM HTTP server
+type OSRMConfig struct {
+	BaseURL string
+	Profile string
+	Timeout time.Duration
+}
+
+func DefaultOSRMConfig() *OSRMConfig {
+	return &OSRMConfig{
+		BaseURL: "http://router.project-osrm.org",
+		Profile: "driving",
+		Timeout: 5 * time.Second,
+	}
+}
+
+type osrmProvider struct {
+	config *OSRMConfig
+	client *http.Client
+}
+
+func NewOSRMProvider(config *OSRMConfig) *osrmProvider {
+	return &osrmProvider{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+func (p *osrmProvider) GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*tripTypes.OsrmApiResponse, error) {
+	url := fmt.Sprintf(
+		"%s/route/v1/%s/%f,%f;%f,%f?overview=full&geometries=geojson",
+		strings.TrimRight(p.config.BaseURL, "/"),
+		p.config.Profile,
+		pickup.Longitude,
+		pickup.Latitude,
+		destination.Longitude,
+		destination.Latitude,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OSRM request: %v", err)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch route from OSRM API: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSRM API responded with status %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response: %v", err)
+	}
+
+	var routeRes tripTypes.OsrmApiResponse
+	if err := json.Unmarshal(body, &routeRes); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	if len(routeRes.Routes) == 0 {
+		return nil, fmt.Errorf("OSRM API returned no routes")
+	}
+
+	return &routeRes, nil
+}
@@ -0,0 +1,92 @@
+/*
+Package routing provides the route providers used by the trip service to compute
+pickup to destination routes.
+*/
+package routing
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+)
+
+// Provider names accepted in Config.Provider
+const (
+	ProviderOSRM      = "osrm"
+	ProviderHaversine = "haversine"
+	ProviderFixture   = "fixture"
+)
+
+type Config struct {
+	Provider string
+	// FallbackEnabled falls back to the haversine provider when the primary provider fails
+	FallbackEnabled bool
+	OSRM            *OSRMConfig
+	Haversine       *HaversineConfig
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Provider:        ProviderOSRM,
+		FallbackEnabled: true,
+		OSRM:            DefaultOSRMConfig(),
+		Haversine:       DefaultHaversineConfig(),
+	}
+}
+
+// NewProvider builds the route provider selected in the configuration.
+// wrap, when set, is applied to the selected provider alone, e.g. to cache its routes:
+// the straight line estimates of the fallback provider must not outlive an outage of the primary one.
+func NewProvider(cfg *Config, wrap func(domain.RouteProvider) domain.RouteProvider) (domain.RouteProvider, error) {
+	var primary domain.RouteProvider
+
+	switch cfg.Provider {
+	case ProviderOSRM:
+		primary = NewOSRMProvider(cfg.OSRM)
+	case ProviderHaversine:
+		primary = NewHaversineProvider(cfg.Haversine)
+	case ProviderFixture:
+		primary = NewFixtureProvider()
+	default:
+		return nil, fmt.Errorf("unknown route provider: %q", cfg.Provider)
+	}
+
+	if wrap != nil {
+		primary = wrap(primary)
+	}
+
+	// Only OSRM can fail, the other providers compute their routes locally
+	if cfg.Provider != ProviderOSRM || !cfg.FallbackEnabled {
+		return primary, nil
+	}
+
+	return NewFallbackProvider(primary, NewHaversineProvider(cfg.Haversine)), nil
+}
+
+// fallbackProvider asks the primary provider first and the fallback one when it fails.
+type fallbackProvider struct {
+	primary  domain.RouteProvider
+	fallback domain.RouteProvider
+}
+
+func NewFallbackProvider(primary, fallback domain.RouteProvider) *fallbackProvider {
+	return &fallbackProvider{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+func (p *fallbackProvider) GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*tripTypes.OsrmApiResponse, error) {
+	route, err := p.primary.GetRoute(ctx, pickup, destination)
+	if err == nil {
+		return route, nil
+	}
+
+	log.Printf("Primary route provider failed, using fallback: %v", err)
+
+	return p.fallback.GetRoute(ctx, pickup, destination)
+}
@@ -0,0 +1,221 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+)
+
+var (
+	ferryBuilding  = &types.Coordinate{Latitude: 37.7955, Longitude: -122.3937}
+	missionDolores = &types.Coordinate{Latitude: 37.7596, Longitude: -122.4269}
+)
+
+func fixtureRoute(distance, duration float64) *tripTypes.OsrmApiResponse {
+	route := &tripTypes.OsrmApiResponse{Routes: make([]tripTypes.OsrmRoute, 1)}
+	route.Routes[0].Distance = distance
+	route.Routes[0].Duration = duration
+	route.Routes[0].Geometry.Coordinates = [][]float64{{-122.3937, 37.7955}, {-122.41, 37.78}, {-122.4269, 37.7596}}
+
+	return route
+}
+
+// countingProvider counts the routes asked to the provider it wraps, or fails when err is set.
+type countingProvider struct {
+	provider *fixtureProvider
+	err      error
+	calls    int
+}
+
+func (p *countingProvider) GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*tripTypes.OsrmApiResponse, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return p.provider.GetRoute(ctx, pickup, destination)
+}
+
+// mapCache is a route cache without expiry.
+type mapCache map[string]*tripTypes.OsrmApiResponse
+
+func (c mapCache) Get(ctx context.Context, key string) (*tripTypes.OsrmApiResponse, error) {
+	return c[key], nil
+}
+
+func (c mapCache) Set(ctx context.Context, key string, route *tripTypes.OsrmApiResponse) error {
+	c[key] = route
+	return nil
+}
+
+func TestFixtureProviderReturnsRegisteredRoute(t *testing.T) {
+	want := fixtureRoute(6100, 900)
+	provider := NewFixtureProvider().WithRoute(ferryBuilding, missionDolores, want)
+
+	got, err := provider.GetRoute(context.Background(), ferryBuilding, missionDolores)
+	if err != nil {
+		t.Fatalf("GetRoute() error = %v", err)
+	}
+	if got != want {
+		t.Fatalf("GetRoute() = %+v, want the registered route", got)
+	}
+
+	// Routes are not symmetric, the way back is not registered
+	back, err := provider.GetRoute(context.Background(), missionDolores, ferryBuilding)
+	if err != nil {
+		t.Fatalf("GetRoute() error = %v", err)
+	}
+	if back == want {
+		t.Fatal("GetRoute() returned the registered route for the way back")
+	}
+}
+
+func TestFixtureProviderFallsBackToStraightRoute(t *testing.T) {
+	provider := NewFixtureProvider()
+
+	got, err := provider.GetRoute(context.Background(), ferryBuilding, missionDolores)
+	if err != nil {
+		t.Fatalf("GetRoute() error = %v", err)
+	}
+
+	config := DefaultHaversineConfig()
+	wantDistance := HaversineDistance(ferryBuilding, missionDolores) * config.DetourFactor
+	wantDuration := wantDistance / (config.AverageSpeedKmh * 1000 / 3600)
+
+	route := got.Routes[0]
+	if math.Abs(route.Distance-wantDistance) > 1e-6 || math.Abs(route.Duration-wantDuration) > 1e-6 {
+		t.Fatalf("route is %.1fm in %.1fs, want %.1fm in %.1fs", route.Distance, route.Duration, wantDistance, wantDuration)
+	}
+
+	// Coordinates follow OSRM's [longitude, latitude] order
+	coordinates := route.Geometry.Coordinates
+	if len(coordinates) != 2 || coordinates[0][0] != ferryBuilding.Longitude || coordinates[1][1] != missionDolores.Latitude {
+		t.Fatalf("geometry = %v, want a straight line from pickup to destination", coordinates)
+	}
+}
+
+func TestHaversineDistance(t *testing.T) {
+	// About 4.9km between the Ferry Building and Mission Dolores
+	if got := HaversineDistance(ferryBuilding, missionDolores); got < 4850 || got > 5050 {
+		t.Fatalf("HaversineDistance() = %.0fm, want about 4900m", got)
+	}
+
+	if got := HaversineDistance(ferryBuilding, ferryBuilding); got != 0 {
+		t.Fatalf("HaversineDistance() to itself = %f, want 0", got)
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		provider string
+		wantErr  bool
+	}{
+		{provider: ProviderOSRM},
+		{provider: ProviderHaversine},
+		{provider: ProviderFixture},
+		{provider: "google", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Provider = tt.provider
+
+			provider, err := NewProvider(cfg, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && provider == nil {
+				t.Fatal("NewProvider() returned no provider")
+			}
+		})
+	}
+}
+
+func TestNewProviderCachesPrimaryRoutesOnly(t *testing.T) {
+	cfg := DefaultConfig()
+	// Nothing listens on port 1, OSRM fails right away
+	cfg.OSRM.BaseURL = "http://127.0.0.1:1"
+
+	cache := mapCache{}
+	provider, err := NewProvider(cfg, func(primary domain.RouteProvider) domain.RouteProvider {
+		return NewCachedProvider(primary, cache, DefaultCacheConfig().Precision)
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	route, err := provider.GetRoute(context.Background(), ferryBuilding, missionDolores)
+	if err != nil || len(route.Routes) == 0 {
+		t.Fatalf("GetRoute() = %+v, %v, want the fallback route", route, err)
+	}
+
+	if len(cache) != 0 {
+		t.Fatalf("cache holds %d routes, want the fallback route left out", len(cache))
+	}
+}
+
+func TestFallbackProvider(t *testing.T) {
+	registered := fixtureRoute(6100, 900)
+	primary := &countingProvider{provider: NewFixtureProvider().WithRoute(ferryBuilding, missionDolores, registered)}
+	fallback := &countingProvider{provider: NewFixtureProvider()}
+	provider := NewFallbackProvider(primary, fallback)
+
+	got, err := provider.GetRoute(context.Background(), ferryBuilding, missionDolores)
+	if err != nil || got != registered {
+		t.Fatalf("GetRoute() = %+v, %v, want the primary route", got, err)
+	}
+	if fallback.calls != 0 {
+		t.Fatalf("fallback was called %d times while the primary provider works", fallback.calls)
+	}
+
+	primary.err = errors.New("osrm is down")
+	got, err = provider.GetRoute(context.Background(), ferryBuilding, missionDolores)
+	if err != nil {
+		t.Fatalf("GetRoute() error = %v, want the fallback route", err)
+	}
+	if got == registered || fallback.calls != 1 {
+		t.Fatalf("GetRoute() did not use the fallback provider, %d calls", fallback.calls)
+	}
+}
+
+func TestCachedProviderSnapsNearbyCoordinates(t *testing.T) {
+	upstream := &countingProvider{provider: NewFixtureProvider()}
+	provider := NewCachedProvider(upstream, mapCache{}, DefaultCacheConfig().Precision)
+	ctx := context.Background()
+
+	first, err := provider.GetRoute(ctx, ferryBuilding, missionDolores)
+	if err != nil {
+		t.Fatalf("GetRoute() error = %v", err)
+	}
+
+	// A few meters away falls in the same geohash cells
+	nearby := &types.Coordinate{Latitude: ferryBuilding.Latitude + 0.00001, Longitude: ferryBuilding.Longitude}
+	second, err := provider.GetRoute(ctx, nearby, missionDolores)
+	if err != nil {
+		t.Fatalf("GetRoute() error = %v", err)
+	}
+
+	if second != first || upstream.calls != 1 {
+		t.Fatalf("nearby pickup was not served from the cache, %d upstream calls", upstream.calls)
+	}
+
+	if _, err := provider.GetRoute(ctx, missionDolores, ferryBuilding); err != nil {
+		t.Fatalf("GetRoute() error = %v", err)
+	}
+
+	if stats := provider.Stats(); stats.Hits != 1 || stats.Misses != 2 {
+		t.Fatalf("Stats() = %+v, want 1 hit and 2 misses", stats)
+	}
+
+	primary := &countingProvider{provider: NewFixtureProvider(), err: errors.New("osrm is down")}
+	failing := NewCachedProvider(primary, mapCache{}, DefaultCacheConfig().Precision)
+	if _, err := failing.GetRoute(ctx, ferryBuilding, missionDolores); err == nil {
+		t.Fatal("GetRoute() error = nil, want the provider error")
+	}
+}
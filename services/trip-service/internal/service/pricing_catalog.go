@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+// PricingCatalog keeps the current pricing catalog in memory and reloads it from its source,
+// so pricing changes do not need a restart.
+type PricingCatalog struct {
+	source  domain.PricingCatalogSource
+	current atomic.Pointer[domain.PricingCatalog]
+}
+
+// NewPricingCatalog loads the catalog from the source, a nil source serves the default catalog.
+func NewPricingCatalog(ctx context.Context, source domain.PricingCatalogSource) (*PricingCatalog, error) {
+	c := &PricingCatalog{source: source}
+
+	if source == nil {
+		c.current.Store(domain.DefaultPricingCatalog())
+		return c, nil
+	}
+
+	if err := c.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Current returns the catalog in use, it must not be modified.
+func (c *PricingCatalog) Current() *domain.PricingCatalog {
+	return c.current.Load()
+}
+
+// Reload replaces the catalog in use if the source holds a valid one.
+func (c *PricingCatalog) Reload(ctx context.Context) error {
+	catalog, err := c.source.LoadPricingCatalog(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := catalog.Validate(); err != nil {
+		return err
+	}
+
+	c.current.Store(catalog)
+
+	return nil
+}
+
+// Watch reloads the catalog every interval until the context is cancelled.
+// A failed reload keeps the previous catalog in use.
+func (c *PricingCatalog) Watch(ctx context.Context, interval time.Duration) {
+	if c.source == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Reload(ctx); err != nil {
+				log.Printf("Failed to reload pricing catalog, keeping the previous one: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+// checkPromotion returns the promotion behind code if the rider is allowed to use it right now.
+func (s *service) checkPromotion(ctx context.Context, code, userID string) (*domain.Promotion, error) {
+	promotion, err := s.promotions.GetPromotion(ctx, domain.NormalizePromoCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	if !promotion.IsActive(time.Now()) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrPromotionExpired, promotion.Code)
+	}
+
+	if promotion.MaxUsesPerUser > 0 {
+		uses, err := s.promotions.CountRedemptions(ctx, promotion.Code, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		if uses >= promotion.MaxUsesPerUser {
+			return nil, fmt.Errorf("%w: %s", domain.ErrPromotionLimitReached, promotion.Code)
+		}
+	}
+
+	if promotion.FirstRideOnly {
+		trips, err := s.repo.CountUserTrips(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		if trips > 0 {
+			return nil, fmt.Errorf("%w: %s", domain.ErrPromotionFirstRideOnly, promotion.Code)
+		}
+	}
+
+	return promotion, nil
+}
+
+// redeemPromotion checks the promotion quoted with the fare again and counts its use.
+func (s *service) redeemPromotion(ctx context.Context, fare *domain.RideFareModel) error {
+	promotion, err := s.checkPromotion(ctx, fare.PromoCode, fare.UserID)
+	if err != nil {
+		return err
+	}
+
+	// The usage limit is enforced atomically here, checkPromotion only gives early feedback.
+	// Concurrent trips with first ride promotions cannot all redeem one, the trip count only catches earlier trips.
+	return s.promotions.RedeemPromotion(ctx, promotion.Code, fare.UserID, promotion.MaxUsesPerUser, promotion.FirstRideOnly)
+}
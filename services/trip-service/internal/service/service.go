@@ -2,88 +2,336 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"log"
+	"math"
 	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/money"
 	"ride-sharing/shared/proto/trip"
 	"ride-sharing/shared/types"
+	"time"
 
 	tripTypes "ride-sharing/services/trip-service/pkg/types"
 	pbd "ride-sharing/shared/proto/driver"
 
+	"github.com/mmcloughlin/geohash"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type service struct {
-	repo domain.TripRepository
+	repo               domain.TripRepository
+	routeProvider      domain.RouteProvider
+	pricingCatalog     *PricingCatalog
+	fareConfig         *tripTypes.FareConfig
+	cancellationPolicy *tripTypes.CancellationPolicy
+	changePolicy       *tripTypes.TripChangePolicy
+	surge              *SurgeEngine
+	promotions         domain.PromotionRepository
 }
 
-func NewService(repo domain.TripRepository) *service {
+func NewService(
+	repo domain.TripRepository,
+	routeProvider domain.RouteProvider,
+	pricingCatalog *PricingCatalog,
+	fareConfig *tripTypes.FareConfig,
+	cancellationPolicy *tripTypes.CancellationPolicy,
+	changePolicy *tripTypes.TripChangePolicy,
+	surge *SurgeEngine,
+	promotions domain.PromotionRepository,
+) *service {
 	return &service{
-		repo: repo,
+		repo:               repo,
+		routeProvider:      routeProvider,
+		pricingCatalog:     pricingCatalog,
+		fareConfig:         fareConfig,
+		cancellationPolicy: cancellationPolicy,
+		changePolicy:       changePolicy,
+		surge:              surge,
+		promotions:         promotions,
 	}
 }
 
 func (s *service) CreateTrip(ctx context.Context, fare *domain.RideFareModel) (*domain.TripModel, error) {
+	if fare.PromoCode != "" {
+		if err := s.redeemPromotion(ctx, fare); err != nil {
+			return nil, err
+		}
+	}
+
+	// Consuming the fare first guarantees a single trip per fare even with concurrent requests
+	if err := s.repo.ConsumeRideFare(ctx, fare.ID.Hex()); err != nil {
+		s.releasePromotion(ctx, fare)
+		return nil, err
+	}
+
 	trip := &domain.TripModel{
 		ID:       primitive.NewObjectID(),
 		UserID:   fare.UserID,
-		Status:   "pending",
+		Status:   domain.TripStatusRequested,
 		RideFare: fare,
 		Driver:   &trip.TripDriver{},
+		History: []*domain.TripStatusTransition{
+			domain.NewTripStatusTransition("", domain.TripStatusRequested, domain.TripActor{Role: domain.ActorRider, ID: fare.UserID}),
+		},
 	}
 
-	return s.repo.CreateTrip(ctx, trip)
+	// The fare already carries the surge multiplier that was quoted, it is not recomputed here
+	trip, err := s.repo.CreateTrip(ctx, trip)
+	if err != nil {
+		// The rider can try again with the same fare rather than previewing the trip again
+		s.releaseFare(ctx, fare)
+		return nil, err
+	}
+
+	s.surge.TripRequested(trip.ID.Hex(), fare.PickupGeohash)
+
+	return trip, nil
+}
+
+// releaseFare makes the fare and its promotion usable again, for a trip that did not happen.
+func (s *service) releaseFare(ctx context.Context, fare *domain.RideFareModel) {
+	if err := s.repo.ReleaseRideFare(ctx, fare.ID.Hex()); err != nil {
+		log.Printf("Failed to release fare %s: %v", fare.ID.Hex(), err)
+	}
+	s.releasePromotion(ctx, fare)
+}
+
+// releasePromotion gives back the promotion redeemed for a fare whose trip did not happen.
+func (s *service) releasePromotion(ctx context.Context, fare *domain.RideFareModel) {
+	if fare.PromoCode == "" {
+		return
+	}
+
+	if err := s.promotions.ReleasePromotion(ctx, fare.PromoCode, fare.UserID); err != nil {
+		log.Printf("Failed to release promotion %s of user %s: %v", fare.PromoCode, fare.UserID, err)
+	}
 }
 
 func (s *service) GetTripByID(ctx context.Context, id string) (*domain.TripModel, error) {
 	return s.repo.GetTripByID(ctx, id)
 }
 
-func (s *service) UpdateTrip(ctx context.Context, tripID string, status string, driver *pbd.Driver) error {
-	return s.repo.UpdateTrip(ctx, tripID, status, driver)
+func (s *service) UpdateTripStatus(ctx context.Context, tripID string, status domain.TripStatus, actor domain.TripActor, driver *pbd.Driver) error {
+	trip, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return err
+	}
+
+	if trip == nil {
+		return fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+	}
+
+	if !trip.Status.CanTransitionTo(status) {
+		return &domain.TripTransitionError{TripID: tripID, From: trip.Status, To: status}
+	}
+
+	transition := domain.NewTripStatusTransition(trip.Status, status, actor)
+	if err := s.repo.UpdateTripStatus(ctx, tripID, transition, driver); err != nil {
+		return err
+	}
+
+	if transition.From == domain.TripStatusRequested {
+		s.surge.TripClosed(tripID)
+	}
+
+	// Trips expiring without a driver give back their fare and promotion, the rider can request again
+	if status == domain.TripStatusExpired && trip.RideFare != nil {
+		s.releaseFare(ctx, trip.RideFare)
+	}
+
+	return nil
 }
 
-func (s *service) GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*tripTypes.OsrmApiResponse, error) {
-	url := fmt.Sprintf(
-		"http://router.project-osrm.org/route/v1/driving/%f,%f;%f,%f?overview=full&geometries=geojson",
-		pickup.Longitude,
-		pickup.Latitude,
-		destination.Longitude,
-		destination.Latitude,
-	)
-
-	res, err := http.Get(url)
+func (s *service) UpdateDriverAvailability(ctx context.Context, driverID, geohash string, available bool) {
+	s.surge.DriverAvailability(driverID, geohash, available)
+}
+
+func (s *service) CancelTrip(ctx context.Context, tripID string, actor domain.TripActor, reason string) (*domain.TripModel, error) {
+	trip, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if trip == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+	}
+
+	switch actor.Role {
+	case domain.ActorRider:
+		if trip.UserID != actor.ID {
+			return nil, domain.ErrNotTripParticipant
+		}
+	case domain.ActorDriver:
+		if trip.Driver.GetId() == "" || trip.Driver.GetId() != actor.ID {
+			return nil, domain.ErrNotTripParticipant
+		}
+	default:
+		return nil, domain.ErrNotTripParticipant
+	}
+
+	if !trip.Status.CanTransitionTo(domain.TripStatusCancelled) {
+		return nil, &domain.TripTransitionError{TripID: tripID, From: trip.Status, To: domain.TripStatusCancelled}
+	}
+
+	currency := trip.RideFare.TotalPrice.Currency
+	cancellation := &domain.TripCancellation{
+		Actor:  actor,
+		Reason: reason,
+		Fee:    money.Zero(currency),
+		At:     time.Now().UTC(),
+	}
+
+	// Riders are only charged once a driver has been dispatched to them
+	driverDispatched := trip.Status == domain.TripStatusDriverAssigned || trip.Status == domain.TripStatusDriverArriving
+	if actor.Role == domain.ActorRider && driverDispatched {
+		cancellation.Fee = money.New(s.cancellationPolicy.FeeInCents, currency)
+	}
+
+	transition := domain.NewTripStatusTransition(trip.Status, domain.TripStatusCancelled, actor)
+	if err := s.repo.CancelTrip(ctx, tripID, transition, cancellation); err != nil {
+		return nil, err
+	}
+
+	s.surge.TripClosed(tripID)
+
+	// A cancelled trip does not use up the promotion it was booked with
+	s.releasePromotion(ctx, trip.RideFare)
+
+	return s.repo.GetTripByID(ctx, tripID)
+}
+
+func (s *service) RateTrip(ctx context.Context, tripID, userID string, stars int, comment string) (*domain.TripModel, error) {
+	if stars < domain.MinTripRating || stars > domain.MaxTripRating {
+		return nil, fmt.Errorf("%w: %d stars, expected %d to %d", domain.ErrInvalidRating, stars, domain.MinTripRating, domain.MaxTripRating)
+	}
+
+	trip, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if trip == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+	}
+
+	if trip.UserID != userID {
+		return nil, domain.ErrNotTripParticipant
+	}
+
+	if trip.Driver.GetId() == "" || !trip.Status.IsRateable() {
+		return nil, fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotRateable, tripID, trip.Status)
+	}
+
+	rating := &domain.TripRating{
+		Stars:   stars,
+		Comment: comment,
+		At:      time.Now().UTC(),
+	}
+	if err := s.repo.RateTrip(ctx, tripID, rating); err != nil {
+		return nil, err
+	}
+
+	trip.Rating = rating
+	return trip, nil
+}
+
+func (s *service) UpdatePickup(ctx context.Context, tripID, userID string, pickup *types.Coordinate) (*domain.TripModel, error) {
+	if !domain.ValidLocation(pickup) {
+		return nil, fmt.Errorf("%w: pickup %v", domain.ErrInvalidLocation, pickup)
+	}
+
+	trip, err := s.riderTrip(ctx, tripID, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch route from OSRM API: %v", err)
+		return nil, err
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
+	if !trip.Status.CanMovePickup() {
+		return nil, fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotEditable, tripID, trip.Status)
+	}
+
+	// Moves are measured from the quoted pickup so the pin can't be walked away in small steps
+	if quoted := trip.RideFare.Pickup; quoted != nil {
+		if distance := domain.DistanceMeters(quoted, pickup); distance > s.changePolicy.MaxPickupMoveMeters {
+			return nil, fmt.Errorf("%w: %.0fm away, at most %.0fm allowed", domain.ErrPickupTooFar, distance, s.changePolicy.MaxPickupMoveMeters)
+		}
+	}
+
+	if err := s.repo.UpdatePickup(ctx, tripID, pickup); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTripByID(ctx, tripID)
+}
+
+func (s *service) AddStop(ctx context.Context, tripID, userID string, location *types.Coordinate) (*domain.TripModel, error) {
+	if !domain.ValidLocation(location) {
+		return nil, fmt.Errorf("%w: stop %v", domain.ErrInvalidLocation, location)
+	}
+
+	trip, err := s.riderTrip(ctx, tripID, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read the response: %v", err)
+		return nil, err
+	}
+
+	if !trip.Status.CanAddStop() {
+		return nil, fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotEditable, tripID, trip.Status)
 	}
 
-	var routeRes tripTypes.OsrmApiResponse
-	if err := json.Unmarshal(body, &routeRes); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %v", err)
+	if len(trip.Stops) >= s.changePolicy.MaxStops {
+		return nil, fmt.Errorf("%w: at most %d allowed", domain.ErrTooManyStops, s.changePolicy.MaxStops)
 	}
 
-	return &routeRes, nil
+	stop := &domain.TripStop{
+		Location: location,
+		AddedAt:  time.Now().UTC(),
+	}
+	if err := s.repo.AddStop(ctx, tripID, stop, s.changePolicy.MaxStops); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTripByID(ctx, tripID)
+}
+
+// riderTrip gets a trip the user is the rider of.
+func (s *service) riderTrip(ctx context.Context, tripID, userID string) (*domain.TripModel, error) {
+	trip, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if trip == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+	}
+
+	if trip.UserID != userID {
+		return nil, domain.ErrNotTripParticipant
+	}
+
+	return trip, nil
+}
+
+func (s *service) GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*tripTypes.OsrmApiResponse, error) {
+	return s.routeProvider.GetRoute(ctx, pickup, destination)
 }
 
 func (s *service) GenerateTripFares(ctx context.Context, rideFares []*domain.RideFareModel, userID string, route *tripTypes.OsrmApiResponse) ([]*domain.RideFareModel, error) {
+	expiresAt := time.Now().Add(s.fareConfig.TTL).UTC()
 	fares := make([]*domain.RideFareModel, len(rideFares))
 	for i, fare := range rideFares {
 		id := primitive.NewObjectID()
 		fare := &domain.RideFareModel{
-			ID:                id,
-			UserID:            userID,
-			TotalPriceInCents: fare.TotalPriceInCents,
-			PackageSlug:       fare.PackageSlug,
-			Route:             route,
+			ID:              id,
+			UserID:          userID,
+			TotalPrice:      fare.TotalPrice,
+			PackageSlug:     fare.PackageSlug,
+			ServiceArea:     fare.ServiceArea,
+			LineItems:       fare.LineItems,
+			SurgeMultiplier: fare.SurgeMultiplier,
+			PickupGeohash:   fare.PickupGeohash,
+			Pickup:          fare.Pickup,
+			PromoCode:       fare.PromoCode,
+			Route:           route,
+			ExpiresAt:       expiresAt,
 		}
 
 		if err := s.repo.SaveRideFare(ctx, fare); err != nil {
@@ -103,61 +351,188 @@ func (s *service) GetAndValidateFare(ctx context.Context, fareID, userID string)
 	}
 
 	if fare == nil {
-		return nil, fmt.Errorf("fare does not exist")
+		return nil, domain.ErrFareNotFound
 	}
 
 	if userID != fare.UserID {
-		return nil, fmt.Errorf("fare does not belong to the user")
+		return nil, domain.ErrFareNotOwned
+	}
+
+	if fare.Consumed {
+		return nil, domain.ErrFareAlreadyUsed
+	}
+
+	if fare.IsExpired(time.Now()) {
+		return nil, domain.ErrFareExpired
 	}
 
 	return fare, nil
 }
 
-func (s *service) EstimatePackagesPriceWithRoute(route *tripTypes.OsrmApiResponse) []*domain.RideFareModel {
-	baseFares := getBaseFares()
-	estimatedFares := make([]*domain.RideFareModel, len(baseFares))
+func (s *service) EstimatePackagesPriceWithRoute(ctx context.Context, userID string, pickup *types.Coordinate, route *tripTypes.OsrmApiResponse, promoCode string) ([]*domain.RideFareModel, error) {
+	area := s.pricingCatalog.Current().AreaFor(pickup)
+	if area == nil {
+		return nil, fmt.Errorf("no service area covers the pickup location")
+	}
 
-	for i, fare := range baseFares {
-		estimatedFares[i] = estimateFareRoute(fare, route)
+	var promotion *domain.Promotion
+	if promoCode != "" {
+		var err error
+		if promotion, err = s.checkPromotion(ctx, promoCode, userID); err != nil {
+			return nil, err
+		}
 	}
 
-	return estimatedFares
-}
+	surgeMultiplier := s.surge.Multiplier(pickup)
+	pickupGeohash := geohash.Encode(pickup.Latitude, pickup.Longitude)
 
-func estimateFareRoute(fare *domain.RideFareModel, route *tripTypes.OsrmApiResponse) *domain.RideFareModel {
-	pricingConfig := tripTypes.DefaultPricingConfig()
-	carPackagePrice := fare.TotalPriceInCents
+	estimatedFares := make([]*domain.RideFareModel, len(area.Packages))
+	for i, pkg := range area.Packages {
+		fare, err := estimateFareRoute(area, pkg, route, surgeMultiplier, promotion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate the %s fare: %w", pkg.Slug, err)
+		}
 
-	distanceKm := route.Routes[0].Distance
-	durationInMinutes := route.Routes[0].Duration
+		fare.PickupGeohash = pickupGeohash
+		fare.Pickup = pickup
+		estimatedFares[i] = fare
+	}
 
-	distanceFare := distanceKm * pricingConfig.PricePerUnitOfDistance
-	timeFare := durationInMinutes * pricingConfig.PricePerMinute
-	totalPrice := carPackagePrice + distanceFare + timeFare
+	return estimatedFares, nil
+}
 
-	return &domain.RideFareModel{
-		TotalPriceInCents: totalPrice,
-		PackageSlug:       fare.PackageSlug,
+func (s *service) ListPackages(ctx context.Context, areaSlug string, location *types.Coordinate) (*domain.ServiceArea, error) {
+	catalog := s.pricingCatalog.Current()
+
+	var area *domain.ServiceArea
+	switch {
+	case areaSlug != "":
+		area = catalog.Area(areaSlug)
+	case location != nil:
+		area = catalog.AreaFor(location)
+	default:
+		area = catalog.DefaultArea()
+	}
+
+	if area == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrServiceAreaNotFound, areaSlug)
 	}
+
+	return area, nil
 }
 
-func getBaseFares() []*domain.RideFareModel {
-	return []*domain.RideFareModel{
-		{
-			PackageSlug:       "suv",
-			TotalPriceInCents: 200,
-		},
+func estimateFareRoute(
+	area *domain.ServiceArea,
+	pkg *domain.PackagePricing,
+	route *tripTypes.OsrmApiResponse,
+	surgeMultiplier float64,
+	promotion *domain.Promotion,
+) (*domain.RideFareModel, error) {
+	// OSRM reports the distance in meters and the duration in seconds
+	distanceKm := route.Routes[0].Distance / 1000
+	durationInMinutes := route.Routes[0].Duration / 60
+
+	// Every line item is rounded to whole minor units on its own so that they add up to the total
+	lineItems := []*domain.FareLineItem{
 		{
-			PackageSlug:       "sedan",
-			TotalPriceInCents: 350,
+			Type:        domain.FareLineItemBase,
+			Description: "Base fare",
+			Amount:      pkg.Amount(pkg.BaseFareInCents),
 		},
 		{
-			PackageSlug:       "van",
-			TotalPriceInCents: 400,
+			Type:        domain.FareLineItemDistance,
+			Description: fmt.Sprintf("Distance (%.1f km)", distanceKm),
+			Amount:      pkg.Amount(pkg.PricePerKmInCents).Mul(distanceKm),
 		},
 		{
-			PackageSlug:       "luxury",
-			TotalPriceInCents: 1000,
+			Type:        domain.FareLineItemTime,
+			Description: fmt.Sprintf("Time (%.0f min)", durationInMinutes),
+			Amount:      pkg.Amount(pkg.PricePerMinuteInCents).Mul(durationInMinutes),
 		},
 	}
+
+	rideFare, err := domain.SumFareLineItems(pkg.Currency, lineItems)
+	if err != nil {
+		return nil, err
+	}
+
+	if minimumFare := pkg.Amount(pkg.MinimumFareInCents); rideFare.Amount < minimumFare.Amount {
+		adjustment, err := minimumFare.Sub(rideFare)
+		if err != nil {
+			return nil, err
+		}
+
+		lineItems = append(lineItems, &domain.FareLineItem{
+			Type:        domain.FareLineItemMinimumFare,
+			Description: "Minimum fare adjustment",
+			Amount:      adjustment,
+		})
+	}
+
+	// Surge applies to the ride itself, not to fees and taxes
+	if surgeMultiplier > 1 {
+		rideFare, err := domain.SumFareLineItems(pkg.Currency, lineItems)
+		if err != nil {
+			return nil, err
+		}
+
+		lineItems = append(lineItems, &domain.FareLineItem{
+			Type:        domain.FareLineItemSurge,
+			Description: fmt.Sprintf("Surge (%gx)", surgeMultiplier),
+			Amount:      rideFare.Mul(surgeMultiplier - 1),
+		})
+	}
+
+	// Promotions discount the ride, fees and taxes are still due
+	promoCode := ""
+	if promotion != nil && promotion.AppliesTo(pkg.Slug) {
+		rideFare, err := domain.SumFareLineItems(pkg.Currency, lineItems)
+		if err != nil {
+			return nil, err
+		}
+
+		if discount := promotion.Discount(rideFare); discount.IsPositive() {
+			promoCode = promotion.Code
+			lineItems = append(lineItems, &domain.FareLineItem{
+				Type:        domain.FareLineItemDiscount,
+				Description: fmt.Sprintf("Promo %s", promotion.Code),
+				Amount:      discount.Neg(),
+			})
+		}
+	}
+
+	if pkg.BookingFeeInCents > 0 {
+		lineItems = append(lineItems, &domain.FareLineItem{
+			Type:        domain.FareLineItemBookingFee,
+			Description: "Booking fee",
+			Amount:      pkg.Amount(pkg.BookingFeeInCents),
+		})
+	}
+
+	if pkg.TaxRatePercent > 0 {
+		taxable, err := domain.SumFareLineItems(pkg.Currency, lineItems)
+		if err != nil {
+			return nil, err
+		}
+
+		lineItems = append(lineItems, &domain.FareLineItem{
+			Type:        domain.FareLineItemTax,
+			Description: fmt.Sprintf("Tax (%g%%)", pkg.TaxRatePercent),
+			Amount:      taxable.Percent(pkg.TaxRatePercent),
+		})
+	}
+
+	totalPrice, err := domain.SumFareLineItems(pkg.Currency, lineItems)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.RideFareModel{
+		TotalPrice:      totalPrice,
+		PackageSlug:     pkg.Slug,
+		ServiceArea:     area.Slug,
+		LineItems:       lineItems,
+		SurgeMultiplier: math.Max(surgeMultiplier, 1),
+		PromoCode:       promoCode,
+	}, nil
 }
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	pbd "ride-sharing/shared/proto/driver"
+	"ride-sharing/shared/types"
+
+	"github.com/mmcloughlin/geohash"
+)
+
+// SurgeEngine tracks open trip requests and available drivers per geohash cell
+// and turns their ratio into a fare multiplier.
+// The counts live in memory. Supply is loaded from driver service once at startup
+// and then follows driver availability events; until it is loaded there is no surge,
+// as every cell would look empty of drivers.
+//
+// The engine only sees the trips requested through its own instance, so trip-service
+// must run as a single replica while it prices surge: with several replicas every one
+// would count its own share of demand and quote multipliers that differ between them.
+// Scaling out needs the counts moved to a store shared by the replicas.
+type SurgeEngine struct {
+	config *tripTypes.SurgeConfig
+
+	mu sync.Mutex
+	// requests holds the cell and request time of every trip waiting for a driver
+	requests map[string]surgeRequest
+	// drivers holds the cell of every available driver
+	drivers map[string]string
+	// supplyLoaded is set once the drivers available at startup were loaded
+	supplyLoaded bool
+	// changed holds the drivers whose availability changed before the supply was loaded,
+	// their events are newer than what driver service lists
+	changed map[string]struct{}
+}
+
+type surgeRequest struct {
+	cell        string
+	requestedAt time.Time
+}
+
+func NewSurgeEngine(config *tripTypes.SurgeConfig) *SurgeEngine {
+	if config == nil {
+		config = tripTypes.DefaultSurgeConfig()
+	}
+
+	return &SurgeEngine{
+		config:   config,
+		requests: make(map[string]surgeRequest),
+		drivers:  make(map[string]string),
+		changed:  make(map[string]struct{}),
+	}
+}
+
+// TripRequested counts the trip as demand in the cell of its pickup.
+func (e *SurgeEngine) TripRequested(tripID, pickupGeohash string) {
+	if pickupGeohash == "" {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests[tripID] = surgeRequest{cell: e.cell(pickupGeohash), requestedAt: time.Now()}
+}
+
+// TripClosed stops counting the trip as demand, once a driver was assigned or it was cancelled.
+func (e *SurgeEngine) TripClosed(tripID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.requests, tripID)
+}
+
+// DriverAvailability counts the driver as supply in its cell while it is available.
+func (e *SurgeEngine) DriverAvailability(driverID, driverGeohash string, available bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.supplyLoaded {
+		e.changed[driverID] = struct{}{}
+	}
+
+	if !available || driverGeohash == "" {
+		delete(e.drivers, driverID)
+		return
+	}
+
+	e.drivers[driverID] = e.cell(driverGeohash)
+}
+
+// LoadSupply counts the drivers as supply, on top of the ones whose availability was already reported,
+// and turns surge on.
+func (e *SurgeEngine) LoadSupply(drivers []*pbd.Driver) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, driver := range drivers {
+		if _, ok := e.changed[driver.Id]; ok || driver.Geohash == "" {
+			continue
+		}
+		e.drivers[driver.Id] = e.cell(driver.Geohash)
+	}
+
+	e.supplyLoaded = true
+	e.changed = nil
+}
+
+// SeedSupply loads the drivers available in driver service, retrying until it answers.
+func (e *SurgeEngine) SeedSupply(ctx context.Context, pool domain.DriverPool) {
+	if !e.config.Enabled {
+		return
+	}
+
+	interval := e.config.SupplyRetryInterval
+	if interval <= 0 {
+		interval = tripTypes.DefaultSurgeConfig().SupplyRetryInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		drivers, err := pool.AvailableDrivers(ctx)
+		if err == nil {
+			e.LoadSupply(drivers)
+			log.Printf("Loaded %d available drivers as surge supply", len(drivers))
+			return
+		}
+		log.Printf("Failed to load available drivers, surge stays off: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Multiplier returns the surge multiplier for a pickup at coord, 1 when there is no surge.
+func (e *SurgeEngine) Multiplier(coord *types.Coordinate) float64 {
+	if !e.config.Enabled || coord == nil {
+		return 1
+	}
+
+	cell := e.cell(geohash.Encode(coord.Latitude, coord.Longitude))
+
+	e.mu.Lock()
+	loaded := e.supplyLoaded
+	demand, supply := e.countLocked(cell)
+	e.mu.Unlock()
+
+	if !loaded || demand < e.config.MinDemand {
+		return 1
+	}
+
+	// Without any driver around every request is unmatched
+	ratio := float64(demand) / math.Max(float64(supply), 1)
+
+	return e.applyCurve(ratio)
+}
+
+// countLocked must be called with the lock held, it also drops expired requests.
+func (e *SurgeEngine) countLocked(cell string) (demand, supply int) {
+	now := time.Now()
+	for tripID, request := range e.requests {
+		if e.config.DemandTTL > 0 && now.Sub(request.requestedAt) > e.config.DemandTTL {
+			delete(e.requests, tripID)
+			continue
+		}
+
+		if request.cell == cell {
+			demand++
+		}
+	}
+
+	for _, driverCell := range e.drivers {
+		if driverCell == cell {
+			supply++
+		}
+	}
+
+	return demand, supply
+}
+
+func (e *SurgeEngine) applyCurve(ratio float64) float64 {
+	curve := e.config.Curve
+	if len(curve) == 0 {
+		return 1
+	}
+
+	multiplier := curve[len(curve)-1].Multiplier
+	if ratio <= curve[0].Ratio {
+		multiplier = curve[0].Multiplier
+	} else {
+		for i := 1; i < len(curve); i++ {
+			if ratio <= curve[i].Ratio {
+				from, to := curve[i-1], curve[i]
+				multiplier = from.Multiplier + (ratio-from.Ratio)/(to.Ratio-from.Ratio)*(to.Multiplier-from.Multiplier)
+				break
+			}
+		}
+	}
+
+	if e.config.MaxMultiplier > 0 {
+		multiplier = math.Min(multiplier, e.config.MaxMultiplier)
+	}
+
+	if e.config.Step > 0 {
+		multiplier = math.Round(multiplier/e.config.Step) * e.config.Step
+		// Get rid of the float noise of the rounding, e.g. 1.3000000000000003
+		multiplier = math.Round(multiplier*1e6) / 1e6
+	}
+
+	return math.Max(multiplier, 1)
+}
+
+func (e *SurgeEngine) cell(hash string) string {
+	if uint(len(hash)) > e.config.Precision {
+		return hash[:e.config.Precision]
+	}
+
+	return hash
+}
@@ -1,17 +1,24 @@
 package types
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	pb "ride-sharing/shared/proto/trip"
 )
 
 type OsrmApiResponse struct {
-	Routes []struct {
-		Distance float64 `json:"distance"`
-		Duration float64 `json:"duration"`
-		Geometry struct {
-			Coordinates [][]float64 `json:"coordinates"`
-		} `json:"geometry"`
-	} `json:"routes"`
+	Routes []OsrmRoute `json:"routes"`
+}
+
+type OsrmRoute struct {
+	Distance float64 `json:"distance"`
+	Duration float64 `json:"duration"`
+	Geometry struct {
+		Coordinates [][]float64 `json:"coordinates"`
+	} `json:"geometry"`
 }
 
 func (o *OsrmApiResponse) ToProto() *pb.Route {
@@ -36,14 +43,132 @@ func (o *OsrmApiResponse) ToProto() *pb.Route {
 	}
 }
 
-type PricingConfig struct {
-	PricePerUnitOfDistance float64
-	PricePerMinute         float64
+// PricingCatalogConfig selects where the pricing catalog is loaded from
+type PricingCatalogConfig struct {
+	// Source is one of "default", "file" or "mongo"
+	Source         string
+	FilePath       string
+	ReloadInterval time.Duration
+}
+
+func DefaultPricingCatalogConfig() *PricingCatalogConfig {
+	return &PricingCatalogConfig{
+		Source:         "default",
+		ReloadInterval: 30 * time.Second,
+	}
+}
+
+// CancellationPolicy holds the fees charged when a trip is cancelled
+type CancellationPolicy struct {
+	// FeeInCents is charged to riders who cancel after a driver has been assigned,
+	// in the minor units of the trip's currency
+	FeeInCents int64
 }
 
-func DefaultPricingConfig() *PricingConfig {
-	return &PricingConfig{
-		PricePerUnitOfDistance: 1.5,
-		PricePerMinute:         0.25,
+func DefaultCancellationPolicy() *CancellationPolicy {
+	return &CancellationPolicy{
+		FeeInCents: 500,
 	}
 }
+
+// TripChangePolicy limits the changes riders can make to a trip once it is requested
+type TripChangePolicy struct {
+	// MaxPickupMoveMeters is how far from the quoted pickup the pickup can be moved, the fare is not requoted
+	MaxPickupMoveMeters float64
+	// MaxStops is how many stops can be added to a trip
+	MaxStops int
+}
+
+func DefaultTripChangePolicy() *TripChangePolicy {
+	return &TripChangePolicy{
+		MaxPickupMoveMeters: 500,
+		MaxStops:            3,
+	}
+}
+
+// FareConfig controls how long generated ride fares can be used
+type FareConfig struct {
+	// TTL is how long a rider can start a trip from a fare after previewing it
+	TTL time.Duration
+	// Retention is how long fares are kept after expiring before being cleaned up
+	Retention time.Duration
+}
+
+func DefaultFareConfig() *FareConfig {
+	return &FareConfig{
+		TTL:       10 * time.Minute,
+		Retention: 24 * time.Hour,
+	}
+}
+
+// SurgeCurvePoint maps a demand/supply ratio to a surge multiplier.
+type SurgeCurvePoint struct {
+	Ratio      float64
+	Multiplier float64
+}
+
+// SurgeConfig controls how fares are surged when trip requests outnumber available drivers
+type SurgeConfig struct {
+	Enabled bool
+	// Precision is the geohash length of the cells demand and supply are tracked in
+	Precision uint
+	// Curve is interpolated linearly between points and flat outside of them
+	Curve []SurgeCurvePoint
+	// MaxMultiplier caps the multiplier whatever the curve says
+	MaxMultiplier float64
+	// MinDemand is the number of open trip requests a cell needs before it surges
+	MinDemand int
+	// Step rounds multipliers, e.g. 0.1 so riders see 1.3x rather than 1.2874x
+	Step float64
+	// DemandTTL drops trip requests that never left the requested status
+	DemandTTL time.Duration
+	// SupplyRetryInterval is how long to wait before asking driver service again for the drivers online at startup
+	SupplyRetryInterval time.Duration
+}
+
+func DefaultSurgeConfig() *SurgeConfig {
+	return &SurgeConfig{
+		Enabled:   true,
+		Precision: 5,
+		Curve: []SurgeCurvePoint{
+			{Ratio: 1, Multiplier: 1},
+			{Ratio: 1.5, Multiplier: 1.3},
+			{Ratio: 2, Multiplier: 1.6},
+			{Ratio: 3, Multiplier: 2},
+		},
+		MaxMultiplier:       3,
+		MinDemand:           3,
+		Step:                0.1,
+		DemandTTL:           10 * time.Minute,
+		SupplyRetryInterval: 5 * time.Second,
+	}
+}
+
+// ParseSurgeCurve parses a curve written as "ratio:multiplier" pairs separated by commas,
+// e.g. "1:1,1.5:1.3,2:1.6". Points must be sorted by ratio.
+func ParseSurgeCurve(value string) ([]SurgeCurvePoint, error) {
+	var curve []SurgeCurvePoint
+	for _, pair := range strings.Split(value, ",") {
+		ratio, multiplier, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid surge curve point %q", pair)
+		}
+
+		point := SurgeCurvePoint{}
+		var err error
+		if point.Ratio, err = strconv.ParseFloat(ratio, 64); err != nil {
+			return nil, fmt.Errorf("invalid surge curve ratio %q: %w", ratio, err)
+		}
+		if point.Multiplier, err = strconv.ParseFloat(multiplier, 64); err != nil {
+			return nil, fmt.Errorf("invalid surge curve multiplier %q: %w", multiplier, err)
+		}
+
+		if len(curve) > 0 && point.Ratio <= curve[len(curve)-1].Ratio {
+			return nil, fmt.Errorf("surge curve ratios must be increasing, got %v after %v", point.Ratio, curve[len(curve)-1].Ratio)
+		}
+
+		curve = append(curve, point)
+	}
+
+	return curve, nil
+}
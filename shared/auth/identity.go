@@ -0,0 +1,153 @@
+// Package auth verifies the JSON Web Tokens users authenticate with and carries their identity
+// through request contexts and gRPC metadata.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type Role string
+
+const (
+	RoleRider  Role = "rider"
+	RoleDriver Role = "driver"
+	RoleAdmin  Role = "admin"
+)
+
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if !slices.Contains([]Role{RoleRider, RoleDriver, RoleAdmin}, role) {
+		return "", fmt.Errorf("%w: unknown role %q", ErrInvalidToken, s)
+	}
+
+	return role, nil
+}
+
+// Identity is who a request is made by.
+type Identity struct {
+	UserID string
+	Role   Role
+}
+
+// Metadata keys the identity is sent to downstream services with
+const (
+	MetadataUserID = "x-user-id"
+	MetadataRole   = "x-user-role"
+)
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// FromContext returns the identity of the request, nil for unauthenticated ones.
+func FromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(contextKey{}).(*Identity)
+	return identity
+}
+
+// outgoingContext adds the identity of ctx, if any, to the metadata of the gRPC calls made with it.
+func outgoingContext(ctx context.Context) context.Context {
+	identity := FromContext(ctx)
+	if identity == nil {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, MetadataUserID, identity.UserID, MetadataRole, string(identity.Role))
+}
+
+// DialOptions propagate the identity of the calling request to the services called.
+func DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+		}),
+	}
+}
+
+// incomingContext adds the identity sent in the metadata of a gRPC call, if any, to its context.
+func incomingContext(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+
+	userIDs := md.Get(MetadataUserID)
+	if len(userIDs) == 0 || userIDs[0] == "" {
+		return ctx, nil
+	}
+
+	roles := md.Get(MetadataRole)
+	if len(roles) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "user role is missing")
+	}
+
+	role, err := ParseRole(roles[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return NewContext(ctx, &Identity{UserID: userIDs[0], Role: role}), nil
+}
+
+// identityStream overrides the context of a server stream with the one carrying the identity.
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
+
+// ServerOptions make the identity propagated by DialOptions available to handlers through FromContext.
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			ctx, err := incomingContext(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, err := incomingContext(stream.Context())
+			if err != nil {
+				return err
+			}
+			return handler(srv, &identityStream{ServerStream: stream, ctx: ctx})
+		}),
+	}
+}
+
+// CheckUser lets a call act on behalf of userID only when made by that user or an admin.
+// Calls without an identity are made by other services, which are trusted.
+func CheckUser(ctx context.Context, userID string) error {
+	identity := FromContext(ctx)
+	if identity == nil || identity.Role == RoleAdmin || identity.UserID == userID {
+		return nil
+	}
+
+	return status.Errorf(codes.PermissionDenied, "user %s cannot act on behalf of %s", identity.UserID, userID)
+}
+
+// CheckRole lets a call through only when made with one of roles, or by another service.
+func CheckRole(ctx context.Context, roles ...Role) error {
+	identity := FromContext(ctx)
+	if identity == nil || slices.Contains(roles, identity.Role) {
+		return nil
+	}
+
+	return status.Errorf(codes.PermissionDenied, "role %s is not allowed", identity.Role)
+}
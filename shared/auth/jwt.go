@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+type Algorithm string
+
+const (
+	HS256 Algorithm = "HS256"
+	RS256 Algorithm = "RS256"
+)
+
+// Config is how tokens are verified. Only tokens signed with Algorithm are accepted.
+type Config struct {
+	Algorithm Algorithm
+	// Secret verifies HS256 tokens
+	Secret []byte
+	// JWKS verifies RS256 tokens with the key matching their kid, PublicKey is used when it is not set
+	JWKS      *JWKS
+	PublicKey *rsa.PublicKey
+	// Issuer and Audience, when set, must match the iss and aud claims
+	Issuer   string
+	Audience string
+	// RoleClaim is the claim holding the role of the user, the user ID is the sub claim
+	RoleClaim string
+	// Leeway absorbs the clock skew between the issuer and the gateway when checking exp and nbf
+	Leeway time.Duration
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Algorithm: HS256,
+		RoleClaim: "role",
+		Leeway:    30 * time.Second,
+	}
+}
+
+// Verifier is safe for concurrent use.
+type Verifier struct {
+	config *Config
+	now    func() time.Time
+}
+
+func NewVerifier(config *Config) (*Verifier, error) {
+	switch config.Algorithm {
+	case HS256:
+		if len(config.Secret) == 0 {
+			return nil, errors.New("HS256 needs a secret")
+		}
+	case RS256:
+		if config.JWKS == nil && config.PublicKey == nil {
+			return nil, errors.New("RS256 needs a JWKS or a public key")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", config.Algorithm)
+	}
+
+	if config.RoleClaim == "" {
+		config.RoleClaim = "role"
+	}
+
+	return &Verifier{config: config, now: time.Now}, nil
+}
+
+type header struct {
+	Algorithm Algorithm `json:"alg"`
+	Type      string    `json:"typ,omitempty"`
+	KeyID     string    `json:"kid,omitempty"`
+}
+
+// Verify checks the signature and claims of the token and returns the identity it was issued for.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, err
+	}
+
+	// Only the configured algorithm is accepted, so an RS256 public key can't be used as an HS256 secret or "none" be sent
+	if h.Algorithm != v.config.Algorithm {
+		return nil, fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidToken, h.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+
+	if err := v.verifySignature(ctx, h, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	if err := v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+
+	userID, _ := claims["sub"].(string)
+	if userID == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrInvalidToken)
+	}
+
+	roleClaim, _ := claims[v.config.RoleClaim].(string)
+	role, err := ParseRole(roleClaim)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Identity{UserID: userID, Role: role}, nil
+}
+
+func (v *Verifier) verifySignature(ctx context.Context, h header, signingInput string, signature []byte) error {
+	switch v.config.Algorithm {
+	case HS256:
+		mac := hmac.New(sha256.New, v.config.Secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+	case RS256:
+		key := v.config.PublicKey
+		if v.config.JWKS != nil {
+			var err error
+			if key, err = v.config.JWKS.Key(ctx, h.KeyID); err != nil {
+				return err
+			}
+		}
+
+		digest := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+	}
+
+	return nil
+}
+
+func (v *Verifier) checkClaims(claims map[string]any) error {
+	now := v.now()
+
+	// Tokens that never expire are not accepted
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("%w: missing exp claim", ErrInvalidToken)
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(v.config.Leeway)) {
+		return ErrTokenExpired
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(v.config.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return fmt.Errorf("%w: token not valid yet", ErrInvalidToken)
+	}
+
+	if v.config.Issuer != "" && claims["iss"] != v.config.Issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+
+	if v.config.Audience != "" && !hasAudience(claims["aud"], v.config.Audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// hasAudience reports whether the aud claim, a string or a list of strings, holds the audience.
+func hasAudience(aud any, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []any:
+		return slices.Contains(aud, any(audience))
+	}
+
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// SignHS256 issues a token for the identity valid for ttl, for tools and environments without an identity provider.
+func SignHS256(identity *Identity, secret []byte, ttl time.Duration) (string, error) {
+	header, err := json.Marshal(header{Algorithm: HS256, Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims, err := json.Marshal(map[string]any{
+		"sub":  identity.UserID,
+		"role": identity.Role,
+		"iat":  now.Unix(),
+		"exp":  now.Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
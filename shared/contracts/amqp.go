@@ -13,14 +13,45 @@ const (
 	TripEventDriverAssigned      = "trip.event.driver_assigned"
 	TripEventNoDriversFound      = "trip.event.no_drivers_found"
 	TripEventDriverNotInterested = "trip.event.driver_not_interested"
+	TripEventCompleted           = "trip.event.completed"
+	TripEventCancelled           = "trip.event.cancelled"
+	TripEventRated               = "trip.event.rated"
+	// TripEventUpdated is published when the rider moves the pickup or adds a stop
+	TripEventUpdated = "trip.event.updated"
 
 	// Driver commands (driver.cmd.*)
 	DriverCmdTripRequest = "driver.cmd.trip_request"
 	DriverCmdTripAccept  = "driver.cmd.trip_accept"
 	DriverCmdTripDecline = "driver.cmd.trip_decline"
+	DriverCmdTripArrive  = "driver.cmd.trip_arrive"
+	DriverCmdTripStart   = "driver.cmd.trip_start"
+	DriverCmdTripEnd     = "driver.cmd.trip_end"
+	DriverCmdTripCancel  = "driver.cmd.trip_cancel"
 	DriverCmdLocation    = "driver.cmd.location"
 	DriverCmdRegister    = "driver.cmd.register"
 
+	// Driver events (driver.event.*)
+	DriverEventAvailability = "driver.event.availability"
+	// DriverEventOfferAccepted is published once driver service put the driver on the trip it accepted,
+	// trip service only assigns drivers it confirmed
+	DriverEventOfferAccepted = "driver.event.offer_accepted"
+	// DriverEventNearby carries the updates of the nearby drivers a rider watches, it only goes over the WebSocket
+	DriverEventNearby = "driver.event.nearby"
+
+	// Rider commands (rider.cmd.*)
+	RiderCmdTripCancel           = "rider.cmd.trip_cancel"
+	RiderCmdWatchNearbyDrivers   = "rider.cmd.watch_nearby_drivers"
+	RiderCmdUnwatchNearbyDrivers = "rider.cmd.unwatch_nearby_drivers"
+	RiderCmdUpdatePickup         = "rider.cmd.update_pickup"
+	RiderCmdAddStop              = "rider.cmd.add_stop"
+	RiderCmdRateDriver           = "rider.cmd.rate_driver"
+	RiderCmdRequestReceipt       = "rider.cmd.request_receipt"
+
+	// Rider events (rider.event.*), they only go over the WebSocket
+	// RiderEventCommandAck answers a rider command that succeeded, RiderEventCommandError one that failed
+	RiderEventCommandAck   = "rider.event.command_ack"
+	RiderEventCommandError = "rider.event.command_error"
+
 	// Payment events (payment.event.*)
 	PaymentEventSessionCreated = "payment.event.session_created"
 	PaymentEventSuccess        = "payment.event.success"
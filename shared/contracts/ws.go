@@ -6,9 +6,92 @@ import "encoding/json"
 type WSMessage struct {
 	Type string `json:"type"`
 	Data any    `json:"data"`
+	// Seq increases with each message sent to a user, they reconnect with the last one they got
+	// to receive the ones they missed
+	Seq uint64 `json:"seq,omitempty"`
 }
 
 type WSDriverMessage struct {
 	Type string          `json:"type"`
 	Data json.RawMessage `json:"data"`
 }
+
+type WSRiderMessage struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+	// RequestID is echoed back in the ack or error answering the command so riders can match them
+	RequestID string `json:"requestID,omitempty"`
+}
+
+// WSTripCancelData is the payload of the trip cancel command sent by riders and drivers.
+type WSTripCancelData struct {
+	TripID string `json:"tripID"`
+	Reason string `json:"reason"`
+}
+
+// WSDriverLocationData is the payload of the location command drivers send while online.
+// The geohash computed by the client is ignored, the driver service derives its own.
+type WSDriverLocationData struct {
+	Location struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"location"`
+	Geohash string `json:"geohash,omitempty"`
+}
+
+// WSWatchNearbyDriversData is the payload of the command riders send to see the cars around an area.
+// Either the viewport or the center and radius are set, a new command replaces the previous watch.
+type WSWatchNearbyDriversData struct {
+	Viewport *struct {
+		SouthWest WSLocation `json:"southWest"`
+		NorthEast WSLocation `json:"northEast"`
+	} `json:"viewport,omitempty"`
+	Center       *WSLocation `json:"center,omitempty"`
+	RadiusMeters float64     `json:"radiusMeters,omitempty"`
+	PackageSlug  string      `json:"packageSlug,omitempty"`
+}
+
+type WSLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// WSUpdatePickupData is the payload of the command moving the pickup pin of a trip.
+type WSUpdatePickupData struct {
+	TripID string     `json:"tripID"`
+	Pickup WSLocation `json:"pickup"`
+}
+
+// WSAddStopData is the payload of the command adding a stop to a trip.
+type WSAddStopData struct {
+	TripID   string     `json:"tripID"`
+	Location WSLocation `json:"location"`
+}
+
+// WSRateDriverData is the payload of the command rating the driver of a finished trip.
+type WSRateDriverData struct {
+	TripID  string `json:"tripID"`
+	Rating  int32  `json:"rating"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// WSRequestReceiptData is the payload of the command asking for the receipt of a paid trip.
+type WSRequestReceiptData struct {
+	TripID string `json:"tripID"`
+}
+
+// WSCommandAckData answers a rider command that succeeded, Result depends on the command.
+type WSCommandAckData struct {
+	RequestID string `json:"requestID,omitempty"`
+	Command   string `json:"command"`
+	Result    any    `json:"result,omitempty"`
+}
+
+// WSCommandErrorData answers a rider command that failed.
+type WSCommandErrorData struct {
+	RequestID string `json:"requestID,omitempty"`
+	Command   string `json:"command"`
+	// Code is the gRPC status code of the failure, e.g. InvalidArgument or FailedPrecondition
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -13,8 +13,15 @@ import (
 )
 
 const (
-	TripsCollection     = "trips"
-	RideFaresCollection = "ride_fares"
+	TripsCollection                = "trips"
+	RideFaresCollection            = "ride_fares"
+	RouteCacheCollection           = "route_cache"
+	PricingCatalogCollection       = "pricing_catalog"
+	PromotionsCollection           = "promotions"
+	PromotionRedemptionsCollection = "promotion_redemptions"
+	DriverProfilesCollection       = "driver_profiles"
+	PaymentIntentsCollection       = "payment_intents"
+	LedgerEntriesCollection        = "ledger_entries"
 )
 
 // MongoConfig holds MongoDB connection configuration
@@ -33,7 +33,8 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
-// Note that on multiple instances of the API gateway, the connection manager needs to store the connections on a separate shared storage.
+// The connection manager only knows the connections of its own gateway instance,
+// NotificationDispatcher finds the instance of other users in the presence registry.
 func NewConnectionManager() *ConnectionManager {
 	return &ConnectionManager{
 		connections: make(map[string]*connWrapper),
@@ -59,10 +60,13 @@ func (cm *ConnectionManager) Add(id string, conn *websocket.Conn) {
 	log.Printf("Added connection for user %s", id)
 }
 
-func (cm *ConnectionManager) Remove(id string) {
+// Remove removes the connection of the user if it is still conn, a newer connection of the same user is kept.
+func (cm *ConnectionManager) Remove(id string, conn *websocket.Conn) {
 	cm.mutex.Lock()
 	defer cm.mutex.Unlock()
-	delete(cm.connections, id)
+	if wrapper, exists := cm.connections[id]; exists && wrapper.conn == conn {
+		delete(cm.connections, id)
+	}
 }
 
 func (cm *ConnectionManager) Get(id string) (*websocket.Conn, bool) {
@@ -75,6 +79,18 @@ func (cm *ConnectionManager) Get(id string) (*websocket.Conn, bool) {
 	return wrapper.conn, true
 }
 
+// IDs returns the users connected to this instance.
+func (cm *ConnectionManager) IDs() []string {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+
+	ids := make([]string, 0, len(cm.connections))
+	for id := range cm.connections {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 func (cm *ConnectionManager) SendMessage(id string, message contracts.WSMessage) error {
 	cm.mutex.RLock()
 	wrapper, exists := cm.connections[id]
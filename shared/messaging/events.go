@@ -1,6 +1,9 @@
 package messaging
 
 import (
+	"time"
+
+	"ride-sharing/shared/money"
 	pbd "ride-sharing/shared/proto/driver"
 	pb "ride-sharing/shared/proto/trip"
 )
@@ -9,37 +12,87 @@ const (
 	FindAvailableDriversQueue        = "find_available_drivers"
 	DriverCmdTripRequestQueue        = "driver_cmd_trip_request"
 	DriverTripResponseQueue          = "driver_trip_response"
+	DriverOfferResponseQueue         = "driver_offer_response"
 	NotifyDriverNoDriversFoundQueue  = "notify_driver_no_drivers_found"
+	TripNoDriversFoundQueue          = "trip_no_drivers_found"
 	NotifyDriverAssignedQueue        = "notify_driver_assigned"
+	NotifyTripCompletedQueue         = "notify_trip_completed"
+	NotifyTripCancelledQueue         = "notify_trip_cancelled"
+	NotifyTripUpdatedQueue           = "notify_trip_updated"
+	DriverTripStatusQueue            = "driver_trip_status"
+	TripDriverAvailabilityQueue      = "trip_driver_availability"
+	DriverLocationQueue              = "driver_location"
 	PaymentTripResponseQueue         = "payment_trip_response"
 	NotifyPaymentSessionCreatedQueue = "notify_payment_session_created"
 	NotifyPaymentSuccessQueue        = "notify_payment_success"
+	PaymentLedgerQueue               = "payment_ledger"
 	DeadLetterQueue                  = "dead_letter_queue"
+	// GatewayInstanceQueuePrefix is followed by the ID of the gateway instance the queue belongs to
+	GatewayInstanceQueuePrefix = "gateway_instance_"
 )
 
 type TripEventData struct {
 	Trip *pb.Trip `json:"trip"`
 }
 
+type TripCancelledData struct {
+	Trip            *pb.Trip    `json:"trip"`
+	CancelledBy     string      `json:"cancelledBy"`
+	Reason          string      `json:"reason"`
+	CancellationFee money.Money `json:"cancellationFee"`
+}
+
+// TripRatedData is the rating a rider gave the driver of a trip
+type TripRatedData struct {
+	TripID   string `json:"tripID"`
+	DriverID string `json:"driverID"`
+	RiderID  string `json:"riderID"`
+	Rating   int    `json:"rating"`
+}
+
 type DriverTripResponseData struct {
 	Driver  *pbd.Driver `json:"driver"`
 	TripID  string      `json:"tripID"`
 	RiderID string      `json:"riderID"`
 }
 
+// DriverAvailabilityData is published whenever a driver starts or stops being available for trips.
+type DriverAvailabilityData struct {
+	DriverID    string `json:"driverID"`
+	PackageSlug string `json:"packageSlug"`
+	Geohash     string `json:"geohash"`
+	Available   bool   `json:"available"`
+}
+
+// DriverLocationData is a position reported by a driver's app.
+type DriverLocationData struct {
+	DriverID  string  `json:"driverID"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// RecordedAt is when the gateway received the position
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
 type PaymentEventSessionCreatedData struct {
-	TripID    string  `json:"tripID"`
-	SessionID string  `json:"sessionID"`
-	Amount    float64 `json:"amount"`
-	Currency  string  `json:"currency"`
+	TripID    string      `json:"tripID"`
+	SessionID string      `json:"sessionID"`
+	Amount    money.Money `json:"amount"`
 }
 
 type PaymentTripResponseData struct {
-	TripID   string  `json:"tripID"`
-	UserID   string  `json:"userID"`
-	DriverID string  `json:"driverID"`
-	Amount   float64 `json:"amount"`
-	Currency string  `json:"currency"`
+	TripID      string      `json:"tripID"`
+	UserID      string      `json:"userID"`
+	DriverID    string      `json:"driverID"`
+	Amount      money.Money `json:"amount"`
+	Description string      `json:"description,omitempty"`
+	// LineItems is the itemized breakdown of Amount, when available
+	LineItems []PaymentLineItem `json:"lineItems,omitempty"`
+}
+
+type PaymentLineItem struct {
+	Type        string      `json:"type"`
+	Description string      `json:"description"`
+	Amount      money.Money `json:"amount"`
 }
 
 type PaymentStatusUpdateData struct {
@@ -0,0 +1,560 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/presence"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// handoffMessageType asks the instance a user was connected to for the messages it kept for them
+	handoffMessageType = "gateway.handoff"
+	// handoffReplyMessageType answers a handoff with the messages, in order
+	handoffReplyMessageType = "gateway.handoff_reply"
+	// forwardAttempts bounds how many instances a message follows a user through when the ones they were on are gone
+	forwardAttempts = 3
+)
+
+// NotificationDispatcherConfig controls how long messages are kept for users to get them after reconnecting
+type NotificationDispatcherConfig struct {
+	// InstanceID identifies this gateway instance in the presence registry, it must be unique among instances
+	InstanceID string
+	// OutboxSize is how many messages are kept per user for replay, the oldest are dropped first
+	OutboxSize int
+	// OutboxTTL is how long a message is kept for replay.
+	// Users stay registered to their last instance as long after disconnecting, so it gets their messages meanwhile.
+	OutboxTTL time.Duration
+	// HandoffTimeout is how long the messages of a user who reconnected to another instance wait
+	// for the ones their previous instance kept, so they are all sent in order
+	HandoffTimeout time.Duration
+}
+
+func DefaultNotificationDispatcherConfig() NotificationDispatcherConfig {
+	return NotificationDispatcherConfig{
+		OutboxSize:     100,
+		OutboxTTL:      5 * time.Minute,
+		HandoffTimeout: 2 * time.Second,
+	}
+}
+
+type handoffRequest struct {
+	// InstanceID is the instance the user is connected to now
+	InstanceID string `json:"instanceID"`
+	// LastSeq is the last message the user got, when they said so
+	LastSeq *uint64 `json:"lastSeq,omitempty"`
+}
+
+type outboxEntry struct {
+	message  contracts.WSMessage
+	storedAt time.Time
+}
+
+// userOutbox keeps the messages of a user in sequence order until they expire.
+type userOutbox struct {
+	entries []outboxEntry
+	// lastSeq is the last sequence number given to a message of the user
+	lastSeq uint64
+	// sentSeq is the last kept message written to the socket of the user, or the one they told they got
+	sentSeq uint64
+	// flushing is set while kept messages are being written, others wait their turn
+	flushing bool
+	// handoff is set while waiting for the messages of another instance, it's the number of the handoff
+	handoff uint64
+}
+
+// pending returns the first kept message the user didn't get, if any.
+func (o *userOutbox) pending(cutoff time.Time) (contracts.WSMessage, bool) {
+	i := sort.Search(len(o.entries), func(i int) bool {
+		return o.entries[i].message.Seq > o.sentSeq
+	})
+
+	for ; i < len(o.entries); i++ {
+		if !o.entries[i].storedAt.Before(cutoff) {
+			return o.entries[i].message, true
+		}
+	}
+
+	return contracts.WSMessage{}, false
+}
+
+// NotificationDispatcher consumes the notify queues once per gateway instance and delivers each message
+// to the WebSocket of the user it is for, the OwnerID of the message.
+// With several instances, the notify queues are shared between them and messages for users connected
+// to another instance are forwarded to it, as found in the presence registry.
+//
+// Every message gets a sequence number increasing per user, and is kept in the outbox of the user
+// so they get what they missed when they connect again, from the last sequence number they got.
+type NotificationDispatcher struct {
+	rb       *Rabbitmq
+	connMgr  *ConnectionManager
+	presence presence.Registry
+	config   NotificationDispatcherConfig
+
+	mu       sync.Mutex
+	outboxes map[string]*userOutbox
+	handoffs uint64
+}
+
+// NewNotificationDispatcher replaces the non-positive values of config with their defaults.
+func NewNotificationDispatcher(rb *Rabbitmq, connMgr *ConnectionManager, registry presence.Registry, config NotificationDispatcherConfig) *NotificationDispatcher {
+	defaults := DefaultNotificationDispatcherConfig()
+	if config.OutboxSize <= 0 {
+		log.Printf("Invalid outbox size %d, using %d", config.OutboxSize, defaults.OutboxSize)
+		config.OutboxSize = defaults.OutboxSize
+	}
+	// The expiry and refresh loops tick at fractions of it
+	if config.OutboxTTL <= 0 {
+		log.Printf("Invalid outbox TTL %s, using %s", config.OutboxTTL, defaults.OutboxTTL)
+		config.OutboxTTL = defaults.OutboxTTL
+	}
+	if config.HandoffTimeout <= 0 {
+		log.Printf("Invalid handoff timeout %s, using %s", config.HandoffTimeout, defaults.HandoffTimeout)
+		config.HandoffTimeout = defaults.HandoffTimeout
+	}
+
+	return &NotificationDispatcher{
+		rb:       rb,
+		connMgr:  connMgr,
+		presence: registry,
+		config:   config,
+		outboxes: make(map[string]*userOutbox),
+	}
+}
+
+// Start consumes the shared queues and the queue of this instance until ctx is done.
+func (d *NotificationDispatcher) Start(ctx context.Context, queues ...string) error {
+	instanceQueue, err := d.rb.DeclareInstanceQueue(d.config.InstanceID)
+	if err != nil {
+		return err
+	}
+
+	for _, queue := range append(queues, instanceQueue) {
+		msgs, err := d.rb.Channel.Consume(
+			queue, // queue
+			"",    // consumer
+			false, // auto-ack
+			false, // exclusive
+			false, // no-local
+			false, // no-wait
+			nil,   // args
+		)
+		if err != nil {
+			return err
+		}
+
+		if queue == instanceQueue {
+			go d.consume(msgs, func(msg amqp.Delivery, body contracts.AmqpMessage) {
+				d.handleForwarded(ctx, msg.Type, body)
+			})
+		} else {
+			go d.consume(msgs, func(msg amqp.Delivery, body contracts.AmqpMessage) {
+				d.route(ctx, msg.RoutingKey, body)
+			})
+		}
+	}
+
+	go d.expireLoop(ctx)
+	go d.refreshLoop(ctx)
+
+	return nil
+}
+
+func (d *NotificationDispatcher) consume(msgs <-chan amqp.Delivery, handle func(amqp.Delivery, contracts.AmqpMessage)) {
+	for msg := range msgs {
+		var msgBody contracts.AmqpMessage
+		if err := json.Unmarshal(msg.Body, &msgBody); err != nil {
+			log.Println("Failed to unmarshal message:", err)
+			_ = msg.Reject(false)
+			continue
+		}
+
+		// The message is delivered, forwarded or kept, either way the gateway is responsible for it now
+		handle(msg, msgBody)
+
+		if err := msg.Ack(false); err != nil {
+			log.Printf("Failed to ack message: %v", err)
+		}
+	}
+}
+
+// route delivers a message from a shared queue here if the user is connected to this instance
+// or to none, and forwards it to the instance of the user otherwise.
+func (d *NotificationDispatcher) route(ctx context.Context, msgType string, msg contracts.AmqpMessage) {
+	instanceID, ok, err := d.presence.Lookup(ctx, msg.OwnerID)
+	if err != nil {
+		log.Printf("Failed to look up the instance of user %s, delivering here: %v", msg.OwnerID, err)
+	}
+
+	if err == nil && ok && d.forward(ctx, instanceID, msgType, msg) {
+		return
+	}
+
+	d.deliverAmqp(msgType, msg)
+}
+
+// forward sends a message to the instance of its user. When that instance is gone, this one takes over
+// the registration of the user so the instance they connect to next asks it for the messages it keeps for them,
+// unless they already connected to another instance, which the message is then sent to.
+// It returns false when the message is to be kept here instead.
+func (d *NotificationDispatcher) forward(ctx context.Context, instanceID, msgType string, msg contracts.AmqpMessage) bool {
+	for attempt := 0; attempt < forwardAttempts && instanceID != d.config.InstanceID; attempt++ {
+		err := d.rb.PublishToInstance(ctx, instanceID, msgType, msg)
+		if err == nil {
+			return true
+		}
+		if !errors.Is(err, ErrInstanceGone) {
+			log.Printf("Failed to forward message %s to instance %s, keeping it here: %v", msgType, instanceID, err)
+			return false
+		}
+
+		replaced, err := d.presence.Replace(ctx, msg.OwnerID, instanceID, d.config.InstanceID, d.config.OutboxTTL)
+		if err != nil {
+			log.Printf("Failed to take user %s over from instance %s: %v", msg.OwnerID, instanceID, err)
+			return false
+		}
+		if replaced {
+			return false
+		}
+
+		next, ok, err := d.presence.Lookup(ctx, msg.OwnerID)
+		if err != nil || !ok {
+			log.Printf("Failed to look up the instance user %s moved to, keeping message %s here: %v", msg.OwnerID, msgType, err)
+			return false
+		}
+		instanceID = next
+	}
+
+	return false
+}
+
+// handleForwarded handles a message another instance sent this one.
+func (d *NotificationDispatcher) handleForwarded(ctx context.Context, msgType string, msg contracts.AmqpMessage) {
+	switch msgType {
+	case handoffMessageType:
+		var req handoffRequest
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			log.Printf("Failed to unmarshal handoff of user %s: %v", msg.OwnerID, err)
+			return
+		}
+
+		d.handoff(ctx, msg.OwnerID, req)
+	case handoffReplyMessageType:
+		var messages []contracts.WSMessage
+		if err := json.Unmarshal(msg.Data, &messages); err != nil {
+			log.Printf("Failed to unmarshal handoff reply of user %s: %v", msg.OwnerID, err)
+		}
+
+		d.endHandoff(msg.OwnerID, 0, messages)
+	default:
+		d.deliverAmqp(msgType, msg)
+	}
+}
+
+func (d *NotificationDispatcher) deliverAmqp(msgType string, msg contracts.AmqpMessage) {
+	var payload any
+	if msg.Data != nil {
+		if err := json.Unmarshal(msg.Data, &payload); err != nil {
+			log.Printf("Failed to unmarshal payload of message %s: %v", msgType, err)
+			return
+		}
+	}
+
+	d.Deliver(msg.OwnerID, contracts.WSMessage{
+		Type: msgType,
+		Data: payload,
+	})
+}
+
+// Deliver sends the message to the user and keeps it in their outbox for replay.
+// Messages are sent in sequence order, after the ones the user didn't get yet.
+func (d *NotificationDispatcher) Deliver(userID string, message contracts.WSMessage) {
+	if userID == "" {
+		log.Printf("Dropping message %s without a recipient", message.Type)
+		return
+	}
+
+	d.mu.Lock()
+	outbox := d.outbox(userID)
+	message.Seq = nextSeq(outbox)
+	outbox.entries = d.trim(append(outbox.entries, outboxEntry{
+		message:  message,
+		storedAt: time.Now(),
+	}))
+	d.mu.Unlock()
+
+	d.flush(userID)
+}
+
+// DeliverEphemeral sends a message not worth replaying, like a position update superseded by the next one.
+// It carries the sequence number of the last kept message sent, so a reconnection resumes after that one.
+func (d *NotificationDispatcher) DeliverEphemeral(userID string, message contracts.WSMessage) error {
+	if _, ok := d.connMgr.Get(userID); !ok {
+		return ErrConnectionNotFound
+	}
+
+	d.mu.Lock()
+	message.Seq = d.outbox(userID).sentSeq
+	d.mu.Unlock()
+
+	return d.connMgr.SendMessage(userID, message)
+}
+
+// Connected registers the user as connected to this instance and sends them the messages after lastSeq,
+// or the ones that couldn't be sent when lastSeq is nil. If the user was connected to another instance,
+// the messages that instance kept for them are sent first.
+func (d *NotificationDispatcher) Connected(ctx context.Context, userID string, lastSeq *uint64) {
+	previous, ok, err := d.presence.Lookup(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to look up the previous instance of user %s: %v", userID, err)
+	}
+
+	if err := d.presence.Register(ctx, userID, d.config.InstanceID, d.config.OutboxTTL); err != nil {
+		log.Printf("Failed to register user %s: %v", userID, err)
+	}
+
+	movedHere := ok && previous != d.config.InstanceID
+
+	d.mu.Lock()
+	outbox := d.outbox(userID)
+	if lastSeq != nil {
+		outbox.sentSeq = *lastSeq
+		outbox.lastSeq = max(outbox.lastSeq, *lastSeq)
+	}
+
+	var handoff uint64
+	if movedHere {
+		d.handoffs++
+		handoff = d.handoffs
+		outbox.handoff = handoff
+	}
+	d.mu.Unlock()
+
+	if movedHere {
+		data, _ := json.Marshal(handoffRequest{
+			InstanceID: d.config.InstanceID,
+			LastSeq:    lastSeq,
+		})
+		if err := d.rb.PublishToInstance(ctx, previous, handoffMessageType, contracts.AmqpMessage{
+			OwnerID: userID,
+			Data:    data,
+		}); err != nil {
+			log.Printf("Failed to ask instance %s for the messages of user %s: %v", previous, userID, err)
+			d.endHandoff(userID, handoff, nil)
+		} else {
+			// The previous instance may be gone, its messages are given up on after a while
+			time.AfterFunc(d.config.HandoffTimeout, func() {
+				d.endHandoff(userID, handoff, nil)
+			})
+		}
+	}
+
+	d.flush(userID)
+}
+
+// handoff sends the messages kept for a user to the instance they connected to, they are no longer kept here.
+func (d *NotificationDispatcher) handoff(ctx context.Context, userID string, req handoffRequest) {
+	if req.InstanceID == d.config.InstanceID {
+		return
+	}
+
+	var messages []contracts.WSMessage
+
+	d.mu.Lock()
+	if outbox, ok := d.outboxes[userID]; ok {
+		after := outbox.sentSeq
+		if req.LastSeq != nil {
+			after = *req.LastSeq
+		}
+
+		cutoff := time.Now().Add(-d.config.OutboxTTL)
+		for _, entry := range outbox.entries {
+			if entry.message.Seq > after && !entry.storedAt.Before(cutoff) {
+				messages = append(messages, entry.message)
+			}
+		}
+
+		delete(d.outboxes, userID)
+	}
+	d.mu.Unlock()
+
+	// The reply goes even without messages so the other instance doesn't wait for them
+	data, err := json.Marshal(messages)
+	if err != nil {
+		log.Printf("Failed to marshal the messages of user %s: %v", userID, err)
+		return
+	}
+
+	if d.forward(ctx, req.InstanceID, handoffReplyMessageType, contracts.AmqpMessage{
+		OwnerID: userID,
+		Data:    data,
+	}) {
+		return
+	}
+
+	// The instance the user connected to is gone, the messages wait here for the next one
+	log.Printf("Failed to hand the messages of user %s to instance %s, keeping them", userID, req.InstanceID)
+	d.endHandoff(userID, 0, messages)
+}
+
+// endHandoff adds the messages another instance kept for the user to their outbox and sends them.
+// A handoff of 0 ends whichever is under way, others only end the handoff with that number.
+func (d *NotificationDispatcher) endHandoff(userID string, handoff uint64, messages []contracts.WSMessage) {
+	d.mu.Lock()
+	outbox := d.outbox(userID)
+	if handoff != 0 && outbox.handoff != handoff {
+		d.mu.Unlock()
+		return
+	}
+	outbox.handoff = 0
+
+	now := time.Now()
+	for _, message := range messages {
+		// Messages coming after the handoff timed out are dropped if newer ones were sent already
+		if message.Seq <= outbox.sentSeq {
+			continue
+		}
+
+		i := sort.Search(len(outbox.entries), func(i int) bool {
+			return outbox.entries[i].message.Seq >= message.Seq
+		})
+		if i < len(outbox.entries) && outbox.entries[i].message.Seq == message.Seq {
+			continue
+		}
+
+		outbox.entries = append(outbox.entries, outboxEntry{})
+		copy(outbox.entries[i+1:], outbox.entries[i:])
+		outbox.entries[i] = outboxEntry{message: message, storedAt: now}
+		outbox.lastSeq = max(outbox.lastSeq, message.Seq)
+	}
+	outbox.entries = d.trim(outbox.entries)
+	d.mu.Unlock()
+
+	d.flush(userID)
+}
+
+// flush sends the kept messages the user didn't get yet, in order.
+func (d *NotificationDispatcher) flush(userID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	outbox, ok := d.outboxes[userID]
+	// The flush under way sends the messages kept meanwhile too
+	if !ok || outbox.flushing || outbox.handoff != 0 {
+		return
+	}
+	outbox.flushing = true
+	defer func() { outbox.flushing = false }()
+
+	for {
+		message, ok := outbox.pending(time.Now().Add(-d.config.OutboxTTL))
+		if !ok {
+			return
+		}
+
+		d.mu.Unlock()
+		err := d.connMgr.SendMessage(userID, message)
+		d.mu.Lock()
+
+		if err != nil {
+			if !errors.Is(err, ErrConnectionNotFound) {
+				log.Printf("Failed to send message %s to user %s: %v", message.Type, userID, err)
+			}
+			return
+		}
+
+		outbox.sentSeq = max(outbox.sentSeq, message.Seq)
+	}
+}
+
+// outbox must be called with mu held.
+func (d *NotificationDispatcher) outbox(userID string) *userOutbox {
+	outbox, ok := d.outboxes[userID]
+	if !ok {
+		outbox = &userOutbox{}
+		d.outboxes[userID] = outbox
+	}
+
+	return outbox
+}
+
+// nextSeq must be called with mu held. Sequence numbers are timestamps in microseconds kept increasing,
+// so they keep going up when the user moves to another instance without instances agreeing on them.
+func nextSeq(outbox *userOutbox) uint64 {
+	outbox.lastSeq = max(outbox.lastSeq+1, uint64(time.Now().UnixMicro()))
+	return outbox.lastSeq
+}
+
+// trim drops the oldest messages over the outbox size.
+func (d *NotificationDispatcher) trim(entries []outboxEntry) []outboxEntry {
+	if over := len(entries) - d.config.OutboxSize; over > 0 {
+		entries = entries[over:]
+	}
+
+	return entries
+}
+
+// refreshLoop keeps the users connected to this instance registered to it.
+func (d *NotificationDispatcher) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(d.config.OutboxTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, userID := range d.connMgr.IDs() {
+				if err := d.presence.Register(ctx, userID, d.config.InstanceID, d.config.OutboxTTL); err != nil {
+					log.Printf("Failed to refresh the registration of user %s: %v", userID, err)
+				}
+			}
+		}
+	}
+}
+
+func (d *NotificationDispatcher) expireLoop(ctx context.Context) {
+	ticker := time.NewTicker(d.config.OutboxTTL / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.expire(time.Now().Add(-d.config.OutboxTTL))
+		}
+	}
+}
+
+// expire drops the messages kept since before cutoff, and the outboxes of users gone with nothing left in them.
+func (d *NotificationDispatcher) expire(cutoff time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for userID, outbox := range d.outboxes {
+		entries := outbox.entries[:0]
+		for _, entry := range outbox.entries {
+			if !entry.storedAt.Before(cutoff) {
+				entries = append(entries, entry)
+			}
+		}
+		outbox.entries = entries
+
+		if len(entries) > 0 || outbox.flushing || outbox.handoff != 0 {
+			continue
+		}
+
+		if _, connected := d.connMgr.Get(userID); !connected {
+			delete(d.outboxes, userID)
+		}
+	}
+}
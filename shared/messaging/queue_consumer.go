@@ -1,68 +0,0 @@
-package messaging
-
-import (
-	"encoding/json"
-	"log"
-
-	"ride-sharing/shared/contracts"
-)
-
-type QueueConsumer struct {
-	rb        *Rabbitmq
-	connMgr   *ConnectionManager
-	queueName string
-}
-
-func NewQueueConsumer(rb *Rabbitmq, connMgr *ConnectionManager, queueName string) *QueueConsumer {
-	return &QueueConsumer{
-		rb:        rb,
-		connMgr:   connMgr,
-		queueName: queueName,
-	}
-}
-
-func (qc *QueueConsumer) Start() error {
-	msgs, err := qc.rb.Channel.Consume(
-		qc.queueName,
-		"",
-		true,
-		false,
-		false,
-		false,
-		nil,
-	)
-	if err != nil {
-		return err
-	}
-
-	go func() {
-		for msg := range msgs {
-			var msgBody contracts.AmqpMessage
-			if err := json.Unmarshal(msg.Body, &msgBody); err != nil {
-				log.Println("Failed to unmarshal message:", err)
-				continue
-			}
-
-			userID := msgBody.OwnerID
-
-			var payload any
-			if msgBody.Data != nil {
-				if err := json.Unmarshal(msgBody.Data, &payload); err != nil {
-					log.Println("Failed to unmarshal payload:", err)
-					continue
-				}
-			}
-
-			clientMsg := contracts.WSMessage{
-				Type: msg.RoutingKey,
-				Data: payload,
-			}
-
-			if err := qc.connMgr.SendMessage(userID, clientMsg); err != nil {
-				log.Printf("Failed to send message to user %s: %v", userID, err)
-			}
-		}
-	}()
-
-	return nil
-}
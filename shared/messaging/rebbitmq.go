@@ -3,11 +3,13 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"ride-sharing/shared/contracts"
 	"ride-sharing/shared/retry"
 	"ride-sharing/shared/tracing"
+	"sync"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -15,11 +17,23 @@ import (
 const (
 	TripExchange       = "trip"
 	DeadLetterExchange = "dlx"
+	// GatewayExchange routes messages to the API gateway instance holding the socket of their user,
+	// the routing key is the ID of the instance
+	GatewayExchange = "gateway"
 )
 
+// ErrInstanceGone is returned for messages sent to a gateway instance whose queue no longer exists.
+var ErrInstanceGone = errors.New("gateway instance is gone")
+
 type Rabbitmq struct {
 	conn    *amqp.Connection
 	Channel *amqp.Channel
+
+	// instanceMu serializes the publishes to gateway instances, so a returned message is the one just published.
+	// They go through their own channel in confirm mode, opened on first use.
+	instanceMu      sync.Mutex
+	instanceChannel *amqp.Channel
+	instanceReturns chan amqp.Return
 }
 
 func NewRebbitmq(uri string) (*Rabbitmq, error) {
@@ -206,11 +220,23 @@ func (r *Rabbitmq) setupExchangesAndQueues() error {
 		return fmt.Errorf("failed to declare exchange: %s: %v", TripExchange, err)
 	}
 
+	err = r.Channel.ExchangeDeclare(
+		GatewayExchange, // name
+		"direct",        // type
+		true,            // durable
+		false,           // auto-deleted
+		false,           // internal
+		false,           // no-wait
+		nil,             // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare exchange: %s: %v", GatewayExchange, err)
+	}
+
 	if err := r.declareAndBindQueue(
 		FindAvailableDriversQueue,
 		[]string{
 			contracts.TripEventCreated,
-			contracts.TripEventDriverNotInterested,
 		},
 		TripExchange,
 	); err != nil {
@@ -229,6 +255,19 @@ func (r *Rabbitmq) setupExchangesAndQueues() error {
 
 	if err := r.declareAndBindQueue(
 		DriverTripResponseQueue,
+		[]string{
+			contracts.DriverEventOfferAccepted,
+			contracts.DriverCmdTripArrive,
+			contracts.DriverCmdTripStart,
+			contracts.DriverCmdTripEnd,
+		},
+		TripExchange,
+	); err != nil {
+		return err
+	}
+
+	if err := r.declareAndBindQueue(
+		DriverOfferResponseQueue,
 		[]string{
 			contracts.DriverCmdTripAccept,
 			contracts.DriverCmdTripDecline,
@@ -248,6 +287,17 @@ func (r *Rabbitmq) setupExchangesAndQueues() error {
 		return err
 	}
 
+	// Trip service expires the trips from its own copy of the no drivers found events
+	if err := r.declareAndBindQueue(
+		TripNoDriversFoundQueue,
+		[]string{
+			contracts.TripEventNoDriversFound,
+		},
+		TripExchange,
+	); err != nil {
+		return err
+	}
+
 	if err := r.declareAndBindQueue(
 		NotifyDriverAssignedQueue,
 		[]string{
@@ -258,6 +308,69 @@ func (r *Rabbitmq) setupExchangesAndQueues() error {
 		return err
 	}
 
+	if err := r.declareAndBindQueue(
+		NotifyTripCompletedQueue,
+		[]string{
+			contracts.TripEventCompleted,
+		},
+		TripExchange,
+	); err != nil {
+		return err
+	}
+
+	if err := r.declareAndBindQueue(
+		NotifyTripCancelledQueue,
+		[]string{
+			contracts.TripEventCancelled,
+		},
+		TripExchange,
+	); err != nil {
+		return err
+	}
+
+	if err := r.declareAndBindQueue(
+		NotifyTripUpdatedQueue,
+		[]string{
+			contracts.TripEventUpdated,
+		},
+		TripExchange,
+	); err != nil {
+		return err
+	}
+
+	if err := r.declareAndBindQueue(
+		DriverTripStatusQueue,
+		[]string{
+			contracts.TripEventDriverAssigned,
+			contracts.TripEventCompleted,
+			contracts.TripEventCancelled,
+			contracts.TripEventRated,
+		},
+		TripExchange,
+	); err != nil {
+		return err
+	}
+
+	if err := r.declareAndBindQueue(
+		TripDriverAvailabilityQueue,
+		[]string{
+			contracts.DriverEventAvailability,
+		},
+		TripExchange,
+	); err != nil {
+		return err
+	}
+
+	if err := r.declareAndBindQueue(
+		DriverLocationQueue,
+		[]string{
+			contracts.DriverCmdLocation,
+		},
+		TripExchange,
+	); err != nil {
+		return err
+	}
+
 	if err := r.declareAndBindQueue(
 		PaymentTripResponseQueue,
 		[]string{
@@ -288,6 +401,17 @@ func (r *Rabbitmq) setupExchangesAndQueues() error {
 		return err
 	}
 
+	// Payment service credits drivers from its own copy of the payment success events
+	if err := r.declareAndBindQueue(
+		PaymentLedgerQueue,
+		[]string{
+			contracts.PaymentEventSuccess,
+		},
+		TripExchange,
+	); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -324,10 +448,104 @@ func (r *Rabbitmq) declareAndBindQueue(queueName string, messageTypes []string,
 	return nil
 }
 
+// DeclareInstanceQueue declares the queue of a gateway instance, it goes away with the instance.
+func (r *Rabbitmq) DeclareInstanceQueue(instanceID string) (string, error) {
+	q, err := r.Channel.QueueDeclare(
+		GatewayInstanceQueuePrefix+instanceID, // name
+		false,                                 // durable
+		true,                                  // delete when unused
+		true,                                  // exclusive
+		false,                                 // no-wait
+		nil,                                   // arguments
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to declare queue of instance %s: %v", instanceID, err)
+	}
+
+	if err := r.Channel.QueueBind(q.Name, instanceID, GatewayExchange, false, nil); err != nil {
+		return "", fmt.Errorf("failed to bind queue of instance %s: %v", instanceID, err)
+	}
+
+	return q.Name, nil
+}
+
+// PublishToInstance sends a message to a gateway instance, msgType is the routing key it was originally sent with.
+func (r *Rabbitmq) PublishToInstance(ctx context.Context, instanceID, msgType string, message contracts.AmqpMessage) error {
+	jsonMessage, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %v", err)
+	}
+
+	// Messages for a socket are of no use once the instance holding it is gone, they aren't persisted
+	msg := amqp.Publishing{
+		ContentType: "application/json",
+		Type:        msgType,
+		Body:        jsonMessage,
+	}
+
+	return tracing.TracedPublisher(ctx, GatewayExchange, instanceID, msg, r.publishMandatory)
+}
+
+// publishMandatory publishes a message that must reach a queue, and waits for the broker to confirm it.
+// It returns ErrInstanceGone if no queue is bound to the routing key, instead of the message being dropped.
+func (r *Rabbitmq) publishMandatory(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	r.instanceMu.Lock()
+	defer r.instanceMu.Unlock()
+
+	if r.instanceChannel == nil || r.instanceChannel.IsClosed() {
+		ch, err := r.conn.Channel()
+		if err != nil {
+			return fmt.Errorf("failed to create channel: %v", err)
+		}
+		if err := ch.Confirm(false); err != nil {
+			ch.Close()
+			return fmt.Errorf("failed to put channel in confirm mode: %v", err)
+		}
+
+		r.instanceChannel = ch
+		r.instanceReturns = ch.NotifyReturn(make(chan amqp.Return, 1))
+	}
+
+	confirmation, err := r.instanceChannel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		exchange,   // exchange
+		routingKey, // routing key
+		true,       // mandatory
+		false,      // immediate
+		msg,
+	)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The broker returns an unroutable message before confirming it, so it is already there
+	select {
+	case returned, ok := <-r.instanceReturns:
+		if ok {
+			return fmt.Errorf("%w: %s", ErrInstanceGone, returned.RoutingKey)
+		}
+	default:
+	}
+
+	if !acked {
+		return fmt.Errorf("message to %s was not confirmed", routingKey)
+	}
+
+	return nil
+}
+
 func (r *Rabbitmq) Close() {
 	if r.conn != nil {
 		r.conn.Close()
 	}
+	if r.instanceChannel != nil {
+		r.instanceChannel.Close()
+	}
 	if r.Channel != nil {
 		r.Channel.Close()
 	}
@@ -0,0 +1,147 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFromMajor(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     Money
+	}{
+		{amount: 12.34, currency: "usd", want: Money{Amount: 1234, Currency: "USD"}},
+		// 0.1 + 0.2 is 0.30000000000000004 in floating point
+		{amount: 0.1 + 0.2, currency: "USD", want: Money{Amount: 30, Currency: "USD"}},
+		{amount: 0.005, currency: "USD", want: Money{Amount: 1, Currency: "USD"}},
+		{amount: -0.005, currency: "USD", want: Money{Amount: -1, Currency: "USD"}},
+		{amount: 1500, currency: "JPY", want: Money{Amount: 1500, Currency: "JPY"}},
+		{amount: 1.2345, currency: "KWD", want: Money{Amount: 1235, Currency: "KWD"}},
+	}
+
+	for _, tt := range tests {
+		if got := FromMajor(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FromMajor(%v, %s) = %+v, want %+v", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: New(1234, "USD"), want: "12.34 USD"},
+		{money: New(5, "USD"), want: "0.05 USD"},
+		{money: New(-1205, "EUR"), want: "-12.05 EUR"},
+		{money: New(-5, "USD"), want: "-0.05 USD"},
+		{money: New(1500, "JPY"), want: "1500 JPY"},
+		{money: New(1005, "KWD"), want: "1.005 KWD"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestAddAndSub(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		add     Money
+		sub     Money
+		wantErr bool
+	}{
+		{name: "same currency", a: New(1000, "USD"), b: New(250, "USD"), add: New(1250, "USD"), sub: New(750, "USD")},
+		{name: "zero value takes the other currency", a: Money{}, b: New(250, "USD"), add: New(250, "USD"), sub: New(-250, "USD")},
+		{name: "zero value on the right", a: New(1000, "USD"), b: Money{}, add: New(1000, "USD"), sub: New(1000, "USD")},
+		{name: "different currencies", a: New(1000, "USD"), b: New(250, "EUR"), wantErr: true},
+		{name: "zero amount still has a currency", a: Zero("EUR"), b: New(250, "USD"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			add, err := tt.a.Add(tt.b)
+			if tt.wantErr {
+				if !errors.Is(err, ErrCurrencyMismatch) {
+					t.Fatalf("Add() error = %v, want ErrCurrencyMismatch", err)
+				}
+				if _, err := tt.a.Sub(tt.b); !errors.Is(err, ErrCurrencyMismatch) {
+					t.Fatalf("Sub() error = %v, want ErrCurrencyMismatch", err)
+				}
+				return
+			}
+
+			if err != nil || add != tt.add {
+				t.Fatalf("Add() = %+v, %v, want %+v", add, err, tt.add)
+			}
+			if sub, err := tt.a.Sub(tt.b); err != nil || sub != tt.sub {
+				t.Fatalf("Sub() = %+v, %v, want %+v", sub, err, tt.sub)
+			}
+		})
+	}
+}
+
+func TestMulAndPercent(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Money
+		want Money
+	}{
+		{name: "surge multiplier", got: New(1999, "USD").Mul(1.5), want: New(2999, "USD")},
+		{name: "half rounds away from zero", got: New(5, "USD").Mul(0.5), want: New(3, "USD")},
+		{name: "negative half rounds away from zero", got: New(-5, "USD").Mul(0.5), want: New(-3, "USD")},
+		{name: "percent", got: New(2500, "USD").Percent(20), want: New(500, "USD")},
+		{name: "percent rounds", got: New(999, "USD").Percent(15), want: New(150, "USD")},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	if got, err := New(100, "USD").Cmp(New(200, "USD")); err != nil || got != -1 {
+		t.Errorf("Cmp() = %d, %v, want -1", got, err)
+	}
+	if got, err := New(200, "USD").Cmp(New(100, "USD")); err != nil || got != 1 {
+		t.Errorf("Cmp() = %d, %v, want 1", got, err)
+	}
+	if got, err := (Money{}).Cmp(Zero("USD")); err != nil || got != 0 {
+		t.Errorf("Cmp() = %d, %v, want 0", got, err)
+	}
+	if _, err := New(100, "USD").Cmp(New(100, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Cmp() error = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestSum(t *testing.T) {
+	total, err := Sum("USD", New(1000, "USD"), New(-150, "USD"), Money{})
+	if err != nil || total != New(850, "USD") {
+		t.Fatalf("Sum() = %+v, %v, want 8.50 USD", total, err)
+	}
+
+	if total, err := Sum("USD"); err != nil || total != Zero("USD") {
+		t.Fatalf("Sum() of nothing = %+v, %v, want 0.00 USD", total, err)
+	}
+
+	if _, err := Sum("USD", New(1000, "USD"), New(100, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("Sum() error = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestProto(t *testing.T) {
+	m := New(1234, "usd")
+	if got := FromProto(m.ToProto()); got != m {
+		t.Fatalf("FromProto(ToProto()) = %+v, want %+v", got, m)
+	}
+
+	if got := FromProto(nil); got != (Money{}) {
+		t.Fatalf("FromProto(nil) = %+v, want the zero value", got)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Package presence keeps track of which API gateway instance holds the WebSocket of each user,
+so events for a user can be routed to that instance when several of them run.
+*/
+package presence
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Registry maps user IDs to the gateway instance holding their connection.
+// Entries expire unless registered again, so instances that die don't keep users forever.
+type Registry interface {
+	// Register records that the user is connected to the instance, for ttl.
+	Register(ctx context.Context, userID, instanceID string, ttl time.Duration) error
+	// Lookup returns the instance the user was last connected to, ok is false if there is none.
+	Lookup(ctx context.Context, userID string) (instanceID string, ok bool, err error)
+	// Replace registers the user to newInstanceID for ttl if they are registered to oldInstanceID or to none,
+	// in a single step. It returns false if they registered to another instance meanwhile.
+	Replace(ctx context.Context, userID, oldInstanceID, newInstanceID string, ttl time.Duration) (bool, error)
+}
+
+type memoryEntry struct {
+	instanceID string
+	expiresAt  time.Time
+}
+
+// MemoryRegistry keeps the presence of users in memory, which is only shared with a single gateway instance.
+type MemoryRegistry struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+func (r *MemoryRegistry) Register(ctx context.Context, userID, instanceID string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.entries[userID] = memoryEntry{
+		instanceID: instanceID,
+		expiresAt:  now.Add(ttl),
+	}
+
+	// Expired entries are dropped once per ttl, so there are at most as many as users seen within two ttls
+	if now.Sub(r.lastSweep) > ttl {
+		for id, entry := range r.entries {
+			if now.After(entry.expiresAt) {
+				delete(r.entries, id)
+			}
+		}
+		r.lastSweep = now
+	}
+
+	return nil
+}
+
+func (r *MemoryRegistry) Lookup(ctx context.Context, userID string) (string, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[userID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return "", false, nil
+	}
+
+	return entry.instanceID, true, nil
+}
+
+func (r *MemoryRegistry) Replace(ctx context.Context, userID, oldInstanceID, newInstanceID string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[userID]
+	if ok && time.Now().Before(entry.expiresAt) && entry.instanceID != oldInstanceID {
+		return false, nil
+	}
+
+	r.entries[userID] = memoryEntry{
+		instanceID: newInstanceID,
+		expiresAt:  time.Now().Add(ttl),
+	}
+
+	return true, nil
+}
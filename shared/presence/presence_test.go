@@ -0,0 +1,63 @@
+package presence
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryRegistry(t *testing.T) {
+	registry := NewMemoryRegistry()
+	ctx := context.Background()
+
+	if err := registry.Register(ctx, "rider-1", "gateway-a", time.Minute); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Register(ctx, "rider-2", "gateway-a", 10*time.Millisecond); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// Registering again moves the user to the new instance
+	if err := registry.Register(ctx, "rider-1", "gateway-b", time.Minute); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if instanceID, ok, _ := registry.Lookup(ctx, "rider-1"); !ok || instanceID != "gateway-b" {
+		t.Fatalf("Lookup() = %q, %v, want gateway-b", instanceID, ok)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok, _ := registry.Lookup(ctx, "rider-2"); ok {
+		t.Fatal("Lookup() of an expired user found it")
+	}
+}
+
+func TestMemoryRegistryReplace(t *testing.T) {
+	testReplace(t, NewMemoryRegistry())
+}
+
+// testReplace checks that users are only moved away from the instance they are expected to be registered to.
+func testReplace(t *testing.T, registry Registry) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := registry.Register(ctx, "rider-1", "gateway-a", time.Minute); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if replaced, err := registry.Replace(ctx, "rider-1", "gateway-c", "gateway-b", time.Minute); err != nil || replaced {
+		t.Fatalf("Replace() of another instance = %v, %v, want false", replaced, err)
+	}
+	if replaced, err := registry.Replace(ctx, "rider-1", "gateway-a", "gateway-b", time.Minute); err != nil || !replaced {
+		t.Fatalf("Replace() = %v, %v, want true", replaced, err)
+	}
+	if instanceID, ok, err := registry.Lookup(ctx, "rider-1"); err != nil || !ok || instanceID != "gateway-b" {
+		t.Fatalf("Lookup() = %q, %v, %v, want gateway-b", instanceID, ok, err)
+	}
+
+	// Users registered nowhere are taken over too
+	if replaced, err := registry.Replace(ctx, "rider-2", "gateway-a", "gateway-b", time.Minute); err != nil || !replaced {
+		t.Fatalf("Replace() of an unknown user = %v, %v, want true", replaced, err)
+	}
+}
@@ -0,0 +1,217 @@
+package presence
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keyPrefix namespaces the presence keys in a Redis database shared with other uses
+const keyPrefix = "presence:user:"
+
+var errNil = errors.New("redis: nil")
+
+// replaceScript sets the key to ARGV[2] for ARGV[3] milliseconds if it is unset or set to ARGV[1],
+// the script runs atomically so no other instance registers the user in between.
+const replaceScript = `local current = redis.call('GET', KEYS[1])
+if current == false or current == ARGV[1] then
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+	return 1
+end
+return 0`
+
+// RedisConfig locates the Redis server, or any server speaking its protocol, presence is kept in.
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	// Timeout bounds dialing and each command
+	Timeout time.Duration
+}
+
+func DefaultRedisConfig() RedisConfig {
+	return RedisConfig{
+		Addr:    "redis:6379",
+		Timeout: 2 * time.Second,
+	}
+}
+
+// RedisRegistry keeps the presence of users in Redis, shared by all gateway instances.
+// It speaks the Redis protocol itself over a single connection, commands are few and small.
+type RedisRegistry struct {
+	config RedisConfig
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func NewRedisRegistry(config RedisConfig) *RedisRegistry {
+	return &RedisRegistry{config: config}
+}
+
+func (r *RedisRegistry) Register(ctx context.Context, userID, instanceID string, ttl time.Duration) error {
+	_, err := r.do(ctx, "SET", keyPrefix+userID, instanceID, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+func (r *RedisRegistry) Lookup(ctx context.Context, userID string) (string, bool, error) {
+	reply, err := r.do(ctx, "GET", keyPrefix+userID)
+	if errors.Is(err, errNil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return reply, true, nil
+}
+
+func (r *RedisRegistry) Replace(ctx context.Context, userID, oldInstanceID, newInstanceID string, ttl time.Duration) (bool, error) {
+	reply, err := r.do(ctx, "EVAL", replaceScript, "1", keyPrefix+userID, oldInstanceID, newInstanceID, strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return false, err
+	}
+
+	return reply == "1", nil
+}
+
+func (r *RedisRegistry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.closeConn()
+}
+
+// do sends a command and reads its reply, reconnecting first if the previous command broke the connection.
+func (r *RedisRegistry) do(ctx context.Context, args ...string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		if err := r.connect(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	reply, err := r.roundTrip(ctx, args)
+	var replyErr replyError
+	if err != nil && !errors.Is(err, errNil) && !errors.As(err, &replyErr) {
+		// The connection is in an unknown state, the next command starts over on a new one
+		r.closeConn()
+	}
+
+	return reply, err
+}
+
+// connect must be called with mu held.
+func (r *RedisRegistry) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: r.config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", r.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to redis at %s: %w", r.config.Addr, err)
+	}
+
+	r.conn = conn
+	r.reader = bufio.NewReader(conn)
+
+	if r.config.Password != "" {
+		if _, err := r.roundTrip(ctx, []string{"AUTH", r.config.Password}); err != nil {
+			r.closeConn()
+			return fmt.Errorf("failed to authenticate to redis: %w", err)
+		}
+	}
+
+	if r.config.DB != 0 {
+		if _, err := r.roundTrip(ctx, []string{"SELECT", strconv.Itoa(r.config.DB)}); err != nil {
+			r.closeConn()
+			return fmt.Errorf("failed to select redis database %d: %w", r.config.DB, err)
+		}
+	}
+
+	return nil
+}
+
+// closeConn must be called with mu held.
+func (r *RedisRegistry) closeConn() error {
+	if r.conn == nil {
+		return nil
+	}
+
+	err := r.conn.Close()
+	r.conn = nil
+	r.reader = nil
+	return err
+}
+
+func (r *RedisRegistry) roundTrip(ctx context.Context, args []string) (string, error) {
+	deadline := time.Now().Add(r.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := r.conn.SetDeadline(deadline); err != nil {
+		return "", err
+	}
+
+	// Commands are sent as arrays of bulk strings
+	var cmd strings.Builder
+	fmt.Fprintf(&cmd, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&cmd, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(r.conn, cmd.String()); err != nil {
+		return "", err
+	}
+
+	return readReply(r.reader)
+}
+
+// replyError is an error reply of the server, the connection is still usable after one.
+type replyError string
+
+func (e replyError) Error() string {
+	return "redis: " + string(e)
+}
+
+// readReply reads a simple string, error, integer or bulk string reply, the only ones the registry gets.
+func readReply(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return "", errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", replyError(line[1:])
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", fmt.Errorf("redis: invalid bulk string length %q", line[1:])
+		}
+		if size < 0 {
+			return "", errNil
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return "", err
+		}
+		return string(buf[:size]), nil
+	default:
+		return "", fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
@@ -0,0 +1,333 @@
+package presence
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    string
+		wantErr error
+	}{
+		{name: "simple string", reply: "+OK\r\n", want: "OK"},
+		{name: "integer", reply: ":42\r\n", want: "42"},
+		{name: "bulk string", reply: "$10\r\ngateway-a1\r\n", want: "gateway-a1"},
+		{name: "bulk string with line breaks", reply: "$4\r\na\r\nb\r\n", want: "a\r\nb"},
+		{name: "empty bulk string", reply: "$0\r\n\r\n", want: ""},
+		{name: "nil bulk string", reply: "$-1\r\n", wantErr: errNil},
+		{name: "error", reply: "-ERR unknown command\r\n", wantErr: replyError("ERR unknown command")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readReply(bufio.NewReader(strings.NewReader(tt.reply)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readReply() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("readReply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadReplyMalformed(t *testing.T) {
+	for _, reply := range []string{"\r\n", "*1\r\n", "$abc\r\n", "$10\r\nshort\r\n", "+OK"} {
+		if _, err := readReply(bufio.NewReader(strings.NewReader(reply))); err == nil {
+			t.Errorf("readReply(%q) error = nil, want an error", reply)
+		}
+	}
+}
+
+// fakeRedis answers the few commands the registry sends, like a Redis server would.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	values   map[string]string
+	expiries map[string]time.Time
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeRedis{
+		listener: listener,
+		password: password,
+		values:   make(map[string]string),
+		expiries: make(map[string]time.Time),
+	}
+	go server.serve()
+
+	return server
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, strings.Join(args, " "))
+		reply := s.execute(args, &authenticated)
+		s.mu.Unlock()
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// execute must be called with mu held.
+func (s *fakeRedis) execute(args []string, authenticated *bool) string {
+	name := strings.ToUpper(args[0])
+	if name == "AUTH" {
+		if len(args) != 2 || args[1] != s.password {
+			return "-WRONGPASS invalid password\r\n"
+		}
+		*authenticated = true
+		return "+OK\r\n"
+	}
+
+	if !*authenticated {
+		return "-NOAUTH Authentication required.\r\n"
+	}
+
+	switch name {
+	case "SELECT":
+		return "+OK\r\n"
+	case "SET":
+		if len(args) != 5 || strings.ToUpper(args[3]) != "PX" {
+			return "-ERR syntax error\r\n"
+		}
+		ttl, err := strconv.Atoi(args[4])
+		if err != nil || ttl <= 0 {
+			return "-ERR invalid expire time in 'set' command\r\n"
+		}
+		s.values[args[1]] = args[2]
+		s.expiries[args[1]] = time.Now().Add(time.Duration(ttl) * time.Millisecond)
+		return "+OK\r\n"
+	case "GET":
+		value, ok := s.lookup(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "EVAL":
+		// Only the script of the registry is known, run as it would by Redis
+		if len(args) != 7 || args[1] != replaceScript || args[2] != "1" {
+			return "-ERR unknown script\r\n"
+		}
+		ttl, err := strconv.Atoi(args[6])
+		if err != nil || ttl <= 0 {
+			return "-ERR invalid expire time in 'set' command\r\n"
+		}
+		if current, ok := s.lookup(args[3]); ok && current != args[4] {
+			return ":0\r\n"
+		}
+		s.values[args[3]] = args[5]
+		s.expiries[args[3]] = time.Now().Add(time.Duration(ttl) * time.Millisecond)
+		return ":1\r\n"
+	default:
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+// lookup must be called with mu held.
+func (s *fakeRedis) lookup(key string) (string, bool) {
+	value, ok := s.values[key]
+	if !ok || time.Now().After(s.expiries[key]) {
+		return "", false
+	}
+
+	return value, true
+}
+
+func (s *fakeRedis) sent() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.commands...)
+}
+
+// readCommand reads an array of bulk strings, the way clients send commands.
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := strconv.Atoi(strings.TrimSuffix(line[1:], "\r\n"))
+	if err != nil || line[0] != '*' {
+		return nil, fmt.Errorf("unexpected command %q", line)
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		if args[i], err = readReply(reader); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+func testRedisConfig(server *fakeRedis) RedisConfig {
+	config := DefaultRedisConfig()
+	config.Addr = server.listener.Addr().String()
+	return config
+}
+
+func TestRedisRegistry(t *testing.T) {
+	server := newFakeRedis(t, "")
+	registry := NewRedisRegistry(testRedisConfig(server))
+	defer registry.Close()
+
+	ctx := context.Background()
+
+	if _, ok, err := registry.Lookup(ctx, "rider-1"); err != nil || ok {
+		t.Fatalf("Lookup() of an unknown user = %v, %v, want nothing", ok, err)
+	}
+
+	if err := registry.Register(ctx, "rider-1", "gateway-a", time.Minute); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	instanceID, ok, err := registry.Lookup(ctx, "rider-1")
+	if err != nil || !ok || instanceID != "gateway-a" {
+		t.Fatalf("Lookup() = %q, %v, %v, want gateway-a", instanceID, ok, err)
+	}
+
+	if got := server.sent(); got[1] != "SET presence:user:rider-1 gateway-a PX 60000" {
+		t.Fatalf("commands = %q, want the user key set with a TTL in milliseconds", got)
+	}
+}
+
+func TestRedisRegistryReplace(t *testing.T) {
+	server := newFakeRedis(t, "")
+	registry := NewRedisRegistry(testRedisConfig(server))
+	defer registry.Close()
+
+	testReplace(t, registry)
+}
+
+func TestRedisRegistryEntriesExpire(t *testing.T) {
+	server := newFakeRedis(t, "")
+	registry := NewRedisRegistry(testRedisConfig(server))
+	defer registry.Close()
+
+	ctx := context.Background()
+	if err := registry.Register(ctx, "driver-1", "gateway-b", 20*time.Millisecond); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok, err := registry.Lookup(ctx, "driver-1"); err != nil || ok {
+		t.Fatalf("Lookup() of an expired user = %v, %v, want nothing", ok, err)
+	}
+}
+
+func TestRedisRegistryAuthenticatesAndSelectsDatabase(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	config := testRedisConfig(server)
+	config.Password = "secret"
+	config.DB = 3
+
+	registry := NewRedisRegistry(config)
+	defer registry.Close()
+
+	if err := registry.Register(context.Background(), "rider-1", "gateway-a", time.Minute); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if got := server.sent(); len(got) != 3 || got[0] != "AUTH secret" || got[1] != "SELECT 3" {
+		t.Fatalf("commands = %q, want AUTH and SELECT before SET", got)
+	}
+
+	config.Password = "wrong"
+	wrong := NewRedisRegistry(config)
+	defer wrong.Close()
+
+	if err := wrong.Register(context.Background(), "rider-1", "gateway-a", time.Minute); err == nil {
+		t.Fatal("Register() with a wrong password error = nil")
+	}
+}
+
+func TestRedisRegistryReconnects(t *testing.T) {
+	server := newFakeRedis(t, "")
+	registry := NewRedisRegistry(testRedisConfig(server))
+	defer registry.Close()
+
+	ctx := context.Background()
+	if err := registry.Register(ctx, "rider-1", "gateway-a", time.Minute); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// Break the connection under the registry, the failing command starts a new one for the next
+	registry.mu.Lock()
+	registry.conn.Close()
+	registry.mu.Unlock()
+
+	if _, _, err := registry.Lookup(ctx, "rider-1"); err == nil {
+		t.Fatal("Lookup() on a closed connection error = nil")
+	}
+
+	instanceID, ok, err := registry.Lookup(ctx, "rider-1")
+	if err != nil || !ok || instanceID != "gateway-a" {
+		t.Fatalf("Lookup() after reconnecting = %q, %v, %v, want gateway-a", instanceID, ok, err)
+	}
+}
+
+func TestRedisRegistryUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	config := DefaultRedisConfig()
+	config.Addr = addr
+	config.Timeout = 100 * time.Millisecond
+
+	registry := NewRedisRegistry(config)
+	if err := registry.Register(context.Background(), "rider-1", "gateway-a", time.Minute); err == nil {
+		t.Fatal("Register() against no server error = nil")
+	}
+}
@@ -117,6 +117,210 @@ func (x *RegisterDriverResponse) GetDriver() *Driver {
 	return nil
 }
 
+type GetDriverRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverID      string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverRequest) Reset() {
+	*x = GetDriverRequest{}
+	mi := &file_driver_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverRequest) ProtoMessage() {}
+
+func (x *GetDriverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetDriverRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+type GetDriverResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Driver *Driver                `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
+	// One of offline, available, offered or on_trip
+	State string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// Trip the driver was offered or is driving, empty otherwise
+	TripID string `protobuf:"bytes,3,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	// Set while the driver has stopped reporting its location, it is not offered trips then
+	Stale bool `protobuf:"varint,4,opt,name=stale,proto3" json:"stale,omitempty"`
+	// Unix timestamp in seconds of the last registration or location update
+	LastSeenAt    int64 `protobuf:"varint,5,opt,name=lastSeenAt,proto3" json:"lastSeenAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverResponse) Reset() {
+	*x = GetDriverResponse{}
+	mi := &file_driver_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverResponse) ProtoMessage() {}
+
+func (x *GetDriverResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverResponse.ProtoReflect.Descriptor instead.
+func (*GetDriverResponse) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetDriverResponse) GetDriver() *Driver {
+	if x != nil {
+		return x.Driver
+	}
+	return nil
+}
+
+func (x *GetDriverResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *GetDriverResponse) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *GetDriverResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *GetDriverResponse) GetLastSeenAt() int64 {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return 0
+}
+
+type ListAvailableDriversRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAvailableDriversRequest) Reset() {
+	*x = ListAvailableDriversRequest{}
+	mi := &file_driver_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAvailableDriversRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAvailableDriversRequest) ProtoMessage() {}
+
+func (x *ListAvailableDriversRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAvailableDriversRequest.ProtoReflect.Descriptor instead.
+func (*ListAvailableDriversRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{4}
+}
+
+type ListAvailableDriversResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Drivers       []*Driver              `protobuf:"bytes,1,rep,name=drivers,proto3" json:"drivers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAvailableDriversResponse) Reset() {
+	*x = ListAvailableDriversResponse{}
+	mi := &file_driver_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAvailableDriversResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAvailableDriversResponse) ProtoMessage() {}
+
+func (x *ListAvailableDriversResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAvailableDriversResponse.ProtoReflect.Descriptor instead.
+func (*ListAvailableDriversResponse) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListAvailableDriversResponse) GetDrivers() []*Driver {
+	if x != nil {
+		return x.Drivers
+	}
+	return nil
+}
+
 type Driver struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -126,13 +330,15 @@ type Driver struct {
 	Geohash        string                 `protobuf:"bytes,5,opt,name=geohash,proto3" json:"geohash,omitempty"`
 	PackageSlug    string                 `protobuf:"bytes,6,opt,name=packageSlug,proto3" json:"packageSlug,omitempty"`
 	Location       *Location              `protobuf:"bytes,7,opt,name=location,proto3" json:"location,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Vehicle the driver is online with, carPlate is its plate
+	Vehicle       *Vehicle `protobuf:"bytes,8,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Driver) Reset() {
 	*x = Driver{}
-	mi := &file_driver_proto_msgTypes[2]
+	mi := &file_driver_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -144,7 +350,7 @@ func (x *Driver) String() string {
 func (*Driver) ProtoMessage() {}
 
 func (x *Driver) ProtoReflect() protoreflect.Message {
-	mi := &file_driver_proto_msgTypes[2]
+	mi := &file_driver_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -157,7 +363,7 @@ func (x *Driver) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Driver.ProtoReflect.Descriptor instead.
 func (*Driver) Descriptor() ([]byte, []int) {
-	return file_driver_proto_rawDescGZIP(), []int{2}
+	return file_driver_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Driver) GetId() string {
@@ -209,6 +415,89 @@ func (x *Driver) GetLocation() *Location {
 	return nil
 }
 
+func (x *Driver) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+type Vehicle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plate         string                 `protobuf:"bytes,1,opt,name=plate,proto3" json:"plate,omitempty"`
+	Make          string                 `protobuf:"bytes,2,opt,name=make,proto3" json:"make,omitempty"`
+	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Colour        string                 `protobuf:"bytes,4,opt,name=colour,proto3" json:"colour,omitempty"`
+	Seats         int32                  `protobuf:"varint,5,opt,name=seats,proto3" json:"seats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vehicle) Reset() {
+	*x = Vehicle{}
+	mi := &file_driver_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vehicle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vehicle) ProtoMessage() {}
+
+func (x *Vehicle) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vehicle.ProtoReflect.Descriptor instead.
+func (*Vehicle) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Vehicle) GetPlate() string {
+	if x != nil {
+		return x.Plate
+	}
+	return ""
+}
+
+func (x *Vehicle) GetMake() string {
+	if x != nil {
+		return x.Make
+	}
+	return ""
+}
+
+func (x *Vehicle) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Vehicle) GetColour() string {
+	if x != nil {
+		return x.Colour
+	}
+	return ""
+}
+
+func (x *Vehicle) GetSeats() int32 {
+	if x != nil {
+		return x.Seats
+	}
+	return 0
+}
+
 type Location struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
@@ -219,7 +508,7 @@ type Location struct {
 
 func (x *Location) Reset() {
 	*x = Location{}
-	mi := &file_driver_proto_msgTypes[3]
+	mi := &file_driver_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -231,7 +520,7 @@ func (x *Location) String() string {
 func (*Location) ProtoMessage() {}
 
 func (x *Location) ProtoReflect() protoreflect.Message {
-	mi := &file_driver_proto_msgTypes[3]
+	mi := &file_driver_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -244,7 +533,7 @@ func (x *Location) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Location.ProtoReflect.Descriptor instead.
 func (*Location) Descriptor() ([]byte, []int) {
-	return file_driver_proto_rawDescGZIP(), []int{3}
+	return file_driver_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Location) GetLatitude() float64 {
@@ -261,6 +550,444 @@ func (x *Location) GetLongitude() float64 {
 	return 0
 }
 
+type WatchNearbyDriversRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Either a viewport or a center with a radius
+	Viewport     *Viewport `protobuf:"bytes,1,opt,name=viewport,proto3" json:"viewport,omitempty"`
+	Center       *Location `protobuf:"bytes,2,opt,name=center,proto3" json:"center,omitempty"`
+	RadiusMeters float64   `protobuf:"fixed64,3,opt,name=radiusMeters,proto3" json:"radiusMeters,omitempty"`
+	// Restricts the drivers to a package, empty watches every package
+	PackageSlug   string `protobuf:"bytes,4,opt,name=packageSlug,proto3" json:"packageSlug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchNearbyDriversRequest) Reset() {
+	*x = WatchNearbyDriversRequest{}
+	mi := &file_driver_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchNearbyDriversRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchNearbyDriversRequest) ProtoMessage() {}
+
+func (x *WatchNearbyDriversRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchNearbyDriversRequest.ProtoReflect.Descriptor instead.
+func (*WatchNearbyDriversRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchNearbyDriversRequest) GetViewport() *Viewport {
+	if x != nil {
+		return x.Viewport
+	}
+	return nil
+}
+
+func (x *WatchNearbyDriversRequest) GetCenter() *Location {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+func (x *WatchNearbyDriversRequest) GetRadiusMeters() float64 {
+	if x != nil {
+		return x.RadiusMeters
+	}
+	return 0
+}
+
+func (x *WatchNearbyDriversRequest) GetPackageSlug() string {
+	if x != nil {
+		return x.PackageSlug
+	}
+	return ""
+}
+
+type Viewport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SouthWest     *Location              `protobuf:"bytes,1,opt,name=southWest,proto3" json:"southWest,omitempty"`
+	NorthEast     *Location              `protobuf:"bytes,2,opt,name=northEast,proto3" json:"northEast,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Viewport) Reset() {
+	*x = Viewport{}
+	mi := &file_driver_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Viewport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Viewport) ProtoMessage() {}
+
+func (x *Viewport) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Viewport.ProtoReflect.Descriptor instead.
+func (*Viewport) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Viewport) GetSouthWest() *Location {
+	if x != nil {
+		return x.SouthWest
+	}
+	return nil
+}
+
+func (x *Viewport) GetNorthEast() *Location {
+	if x != nil {
+		return x.NorthEast
+	}
+	return nil
+}
+
+type NearbyDriversUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Drivers that entered the area or moved since the previous update
+	Upserted []*NearbyDriver `protobuf:"bytes,1,rep,name=upserted,proto3" json:"upserted,omitempty"`
+	// Drivers that left the area or stopped being available
+	Removed []string `protobuf:"bytes,2,rep,name=removed,proto3" json:"removed,omitempty"`
+	// Set on the first update of a stream, it replaces whatever drivers were known until then
+	Snapshot      bool `protobuf:"varint,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NearbyDriversUpdate) Reset() {
+	*x = NearbyDriversUpdate{}
+	mi := &file_driver_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NearbyDriversUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearbyDriversUpdate) ProtoMessage() {}
+
+func (x *NearbyDriversUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearbyDriversUpdate.ProtoReflect.Descriptor instead.
+func (*NearbyDriversUpdate) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *NearbyDriversUpdate) GetUpserted() []*NearbyDriver {
+	if x != nil {
+		return x.Upserted
+	}
+	return nil
+}
+
+func (x *NearbyDriversUpdate) GetRemoved() []string {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+func (x *NearbyDriversUpdate) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+// NearbyDriver only tells where a car roughly is, its id is only stable for the duration of the stream
+type NearbyDriver struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PackageSlug   string                 `protobuf:"bytes,2,opt,name=packageSlug,proto3" json:"packageSlug,omitempty"`
+	Location      *Location              `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NearbyDriver) Reset() {
+	*x = NearbyDriver{}
+	mi := &file_driver_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NearbyDriver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearbyDriver) ProtoMessage() {}
+
+func (x *NearbyDriver) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearbyDriver.ProtoReflect.Descriptor instead.
+func (*NearbyDriver) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *NearbyDriver) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NearbyDriver) GetPackageSlug() string {
+	if x != nil {
+		return x.PackageSlug
+	}
+	return ""
+}
+
+func (x *NearbyDriver) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type GetDriverStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverID      string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverStatsRequest) Reset() {
+	*x = GetDriverStatsRequest{}
+	mi := &file_driver_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverStatsRequest) ProtoMessage() {}
+
+func (x *GetDriverStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverStatsRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetDriverStatsRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+type DriverStats struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverID string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	Offers   int32                  `protobuf:"varint,2,opt,name=offers,proto3" json:"offers,omitempty"`
+	Accepted int32                  `protobuf:"varint,3,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Declined int32                  `protobuf:"varint,4,opt,name=declined,proto3" json:"declined,omitempty"`
+	// Offers the driver did not answer in time
+	TimedOut int32 `protobuf:"varint,5,opt,name=timedOut,proto3" json:"timedOut,omitempty"`
+	// Trips the driver accepted and then cancelled
+	Cancelled int32 `protobuf:"varint,6,opt,name=cancelled,proto3" json:"cancelled,omitempty"`
+	Ratings   int32 `protobuf:"varint,7,opt,name=ratings,proto3" json:"ratings,omitempty"`
+	// Share of answered offers the driver accepted, timeouts count as declines
+	AcceptanceRate float64 `protobuf:"fixed64,8,opt,name=acceptanceRate,proto3" json:"acceptanceRate,omitempty"`
+	// Share of accepted trips the driver cancelled
+	CancellationRate float64 `protobuf:"fixed64,9,opt,name=cancellationRate,proto3" json:"cancellationRate,omitempty"`
+	// From 1 to 5, 0 until the driver is rated
+	AverageRating float64 `protobuf:"fixed64,10,opt,name=averageRating,proto3" json:"averageRating,omitempty"`
+	// From 0 to 1, dispatch favours drivers with a higher score when quality weighting is on
+	Score float64 `protobuf:"fixed64,11,opt,name=score,proto3" json:"score,omitempty"`
+	// Thresholds the driver is past, any of low_acceptance, high_cancellation or low_rating
+	Flags []string `protobuf:"bytes,12,rep,name=flags,proto3" json:"flags,omitempty"`
+	// How far back the stats go
+	WindowSeconds int64 `protobuf:"varint,13,opt,name=windowSeconds,proto3" json:"windowSeconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DriverStats) Reset() {
+	*x = DriverStats{}
+	mi := &file_driver_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriverStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriverStats) ProtoMessage() {}
+
+func (x *DriverStats) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriverStats.ProtoReflect.Descriptor instead.
+func (*DriverStats) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DriverStats) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *DriverStats) GetOffers() int32 {
+	if x != nil {
+		return x.Offers
+	}
+	return 0
+}
+
+func (x *DriverStats) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *DriverStats) GetDeclined() int32 {
+	if x != nil {
+		return x.Declined
+	}
+	return 0
+}
+
+func (x *DriverStats) GetTimedOut() int32 {
+	if x != nil {
+		return x.TimedOut
+	}
+	return 0
+}
+
+func (x *DriverStats) GetCancelled() int32 {
+	if x != nil {
+		return x.Cancelled
+	}
+	return 0
+}
+
+func (x *DriverStats) GetRatings() int32 {
+	if x != nil {
+		return x.Ratings
+	}
+	return 0
+}
+
+func (x *DriverStats) GetAcceptanceRate() float64 {
+	if x != nil {
+		return x.AcceptanceRate
+	}
+	return 0
+}
+
+func (x *DriverStats) GetCancellationRate() float64 {
+	if x != nil {
+		return x.CancellationRate
+	}
+	return 0
+}
+
+func (x *DriverStats) GetAverageRating() float64 {
+	if x != nil {
+		return x.AverageRating
+	}
+	return 0
+}
+
+func (x *DriverStats) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *DriverStats) GetFlags() []string {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *DriverStats) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
 var File_driver_proto protoreflect.FileDescriptor
 
 const file_driver_proto_rawDesc = "" +
@@ -270,7 +997,20 @@ const file_driver_proto_rawDesc = "" +
 	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12 \n" +
 	"\vpackageSlug\x18\x02 \x01(\tR\vpackageSlug\"@\n" +
 	"\x16RegisterDriverResponse\x12&\n" +
-	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\"\xda\x01\n" +
+	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\".\n" +
+	"\x10GetDriverRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\"\x9f\x01\n" +
+	"\x11GetDriverResponse\x12&\n" +
+	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x16\n" +
+	"\x06tripID\x18\x03 \x01(\tR\x06tripID\x12\x14\n" +
+	"\x05stale\x18\x04 \x01(\bR\x05stale\x12\x1e\n" +
+	"\n" +
+	"lastSeenAt\x18\x05 \x01(\x03R\n" +
+	"lastSeenAt\"\x1d\n" +
+	"\x1bListAvailableDriversRequest\"H\n" +
+	"\x1cListAvailableDriversResponse\x12(\n" +
+	"\adrivers\x18\x01 \x03(\v2\x0e.driver.DriverR\adrivers\"\x85\x02\n" +
 	"\x06Driver\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12&\n" +
@@ -278,13 +1018,57 @@ const file_driver_proto_rawDesc = "" +
 	"\bcarPlate\x18\x04 \x01(\tR\bcarPlate\x12\x18\n" +
 	"\ageohash\x18\x05 \x01(\tR\ageohash\x12 \n" +
 	"\vpackageSlug\x18\x06 \x01(\tR\vpackageSlug\x12,\n" +
-	"\blocation\x18\a \x01(\v2\x10.driver.LocationR\blocation\"D\n" +
+	"\blocation\x18\a \x01(\v2\x10.driver.LocationR\blocation\x12)\n" +
+	"\avehicle\x18\b \x01(\v2\x0f.driver.VehicleR\avehicle\"w\n" +
+	"\aVehicle\x12\x14\n" +
+	"\x05plate\x18\x01 \x01(\tR\x05plate\x12\x12\n" +
+	"\x04make\x18\x02 \x01(\tR\x04make\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12\x16\n" +
+	"\x06colour\x18\x04 \x01(\tR\x06colour\x12\x14\n" +
+	"\x05seats\x18\x05 \x01(\x05R\x05seats\"D\n" +
 	"\bLocation\x12\x1a\n" +
 	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\x02 \x01(\x01R\tlongitude2\xb3\x01\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"\xb9\x01\n" +
+	"\x19WatchNearbyDriversRequest\x12,\n" +
+	"\bviewport\x18\x01 \x01(\v2\x10.driver.ViewportR\bviewport\x12(\n" +
+	"\x06center\x18\x02 \x01(\v2\x10.driver.LocationR\x06center\x12\"\n" +
+	"\fradiusMeters\x18\x03 \x01(\x01R\fradiusMeters\x12 \n" +
+	"\vpackageSlug\x18\x04 \x01(\tR\vpackageSlug\"j\n" +
+	"\bViewport\x12.\n" +
+	"\tsouthWest\x18\x01 \x01(\v2\x10.driver.LocationR\tsouthWest\x12.\n" +
+	"\tnorthEast\x18\x02 \x01(\v2\x10.driver.LocationR\tnorthEast\"}\n" +
+	"\x13NearbyDriversUpdate\x120\n" +
+	"\bupserted\x18\x01 \x03(\v2\x14.driver.NearbyDriverR\bupserted\x12\x18\n" +
+	"\aremoved\x18\x02 \x03(\tR\aremoved\x12\x1a\n" +
+	"\bsnapshot\x18\x03 \x01(\bR\bsnapshot\"n\n" +
+	"\fNearbyDriver\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12 \n" +
+	"\vpackageSlug\x18\x02 \x01(\tR\vpackageSlug\x12,\n" +
+	"\blocation\x18\x03 \x01(\v2\x10.driver.LocationR\blocation\"3\n" +
+	"\x15GetDriverStatsRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\"\x99\x03\n" +
+	"\vDriverStats\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12\x16\n" +
+	"\x06offers\x18\x02 \x01(\x05R\x06offers\x12\x1a\n" +
+	"\baccepted\x18\x03 \x01(\x05R\baccepted\x12\x1a\n" +
+	"\bdeclined\x18\x04 \x01(\x05R\bdeclined\x12\x1a\n" +
+	"\btimedOut\x18\x05 \x01(\x05R\btimedOut\x12\x1c\n" +
+	"\tcancelled\x18\x06 \x01(\x05R\tcancelled\x12\x18\n" +
+	"\aratings\x18\a \x01(\x05R\aratings\x12&\n" +
+	"\x0eacceptanceRate\x18\b \x01(\x01R\x0eacceptanceRate\x12*\n" +
+	"\x10cancellationRate\x18\t \x01(\x01R\x10cancellationRate\x12$\n" +
+	"\raverageRating\x18\n" +
+	" \x01(\x01R\raverageRating\x12\x14\n" +
+	"\x05score\x18\v \x01(\x01R\x05score\x12\x14\n" +
+	"\x05flags\x18\f \x03(\tR\x05flags\x12$\n" +
+	"\rwindowSeconds\x18\r \x01(\x03R\rwindowSeconds2\xf6\x03\n" +
 	"\rDriverService\x12O\n" +
 	"\x0eRegisterDriver\x12\x1d.driver.RegisterDriverRequest\x1a\x1e.driver.RegisterDriverResponse\x12Q\n" +
-	"\x10UnRegisterDriver\x12\x1d.driver.RegisterDriverRequest\x1a\x1e.driver.RegisterDriverResponseB\x1cZ\x1ashared/proto/driver;driverb\x06proto3"
+	"\x10UnRegisterDriver\x12\x1d.driver.RegisterDriverRequest\x1a\x1e.driver.RegisterDriverResponse\x12@\n" +
+	"\tGetDriver\x12\x18.driver.GetDriverRequest\x1a\x19.driver.GetDriverResponse\x12V\n" +
+	"\x12WatchNearbyDrivers\x12!.driver.WatchNearbyDriversRequest\x1a\x1b.driver.NearbyDriversUpdate0\x01\x12D\n" +
+	"\x0eGetDriverStats\x12\x1d.driver.GetDriverStatsRequest\x1a\x13.driver.DriverStats\x12a\n" +
+	"\x14ListAvailableDrivers\x12#.driver.ListAvailableDriversRequest\x1a$.driver.ListAvailableDriversResponseB)Z'ride-sharing/shared/proto/driver;driverb\x06proto3"
 
 var (
 	file_driver_proto_rawDescOnce sync.Once
@@ -298,25 +1082,53 @@ func file_driver_proto_rawDescGZIP() []byte {
 	return file_driver_proto_rawDescData
 }
 
-var file_driver_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_driver_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_driver_proto_goTypes = []any{
-	(*RegisterDriverRequest)(nil),  // 0: driver.RegisterDriverRequest
-	(*RegisterDriverResponse)(nil), // 1: driver.RegisterDriverResponse
-	(*Driver)(nil),                 // 2: driver.Driver
-	(*Location)(nil),               // 3: driver.Location
+	(*RegisterDriverRequest)(nil),        // 0: driver.RegisterDriverRequest
+	(*RegisterDriverResponse)(nil),       // 1: driver.RegisterDriverResponse
+	(*GetDriverRequest)(nil),             // 2: driver.GetDriverRequest
+	(*GetDriverResponse)(nil),            // 3: driver.GetDriverResponse
+	(*ListAvailableDriversRequest)(nil),  // 4: driver.ListAvailableDriversRequest
+	(*ListAvailableDriversResponse)(nil), // 5: driver.ListAvailableDriversResponse
+	(*Driver)(nil),                       // 6: driver.Driver
+	(*Vehicle)(nil),                      // 7: driver.Vehicle
+	(*Location)(nil),                     // 8: driver.Location
+	(*WatchNearbyDriversRequest)(nil),    // 9: driver.WatchNearbyDriversRequest
+	(*Viewport)(nil),                     // 10: driver.Viewport
+	(*NearbyDriversUpdate)(nil),          // 11: driver.NearbyDriversUpdate
+	(*NearbyDriver)(nil),                 // 12: driver.NearbyDriver
+	(*GetDriverStatsRequest)(nil),        // 13: driver.GetDriverStatsRequest
+	(*DriverStats)(nil),                  // 14: driver.DriverStats
 }
 var file_driver_proto_depIdxs = []int32{
-	2, // 0: driver.RegisterDriverResponse.driver:type_name -> driver.Driver
-	3, // 1: driver.Driver.location:type_name -> driver.Location
-	0, // 2: driver.DriverService.RegisterDriver:input_type -> driver.RegisterDriverRequest
-	0, // 3: driver.DriverService.UnRegisterDriver:input_type -> driver.RegisterDriverRequest
-	1, // 4: driver.DriverService.RegisterDriver:output_type -> driver.RegisterDriverResponse
-	1, // 5: driver.DriverService.UnRegisterDriver:output_type -> driver.RegisterDriverResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	6,  // 0: driver.RegisterDriverResponse.driver:type_name -> driver.Driver
+	6,  // 1: driver.GetDriverResponse.driver:type_name -> driver.Driver
+	6,  // 2: driver.ListAvailableDriversResponse.drivers:type_name -> driver.Driver
+	8,  // 3: driver.Driver.location:type_name -> driver.Location
+	7,  // 4: driver.Driver.vehicle:type_name -> driver.Vehicle
+	10, // 5: driver.WatchNearbyDriversRequest.viewport:type_name -> driver.Viewport
+	8,  // 6: driver.WatchNearbyDriversRequest.center:type_name -> driver.Location
+	8,  // 7: driver.Viewport.southWest:type_name -> driver.Location
+	8,  // 8: driver.Viewport.northEast:type_name -> driver.Location
+	12, // 9: driver.NearbyDriversUpdate.upserted:type_name -> driver.NearbyDriver
+	8,  // 10: driver.NearbyDriver.location:type_name -> driver.Location
+	0,  // 11: driver.DriverService.RegisterDriver:input_type -> driver.RegisterDriverRequest
+	0,  // 12: driver.DriverService.UnRegisterDriver:input_type -> driver.RegisterDriverRequest
+	2,  // 13: driver.DriverService.GetDriver:input_type -> driver.GetDriverRequest
+	9,  // 14: driver.DriverService.WatchNearbyDrivers:input_type -> driver.WatchNearbyDriversRequest
+	13, // 15: driver.DriverService.GetDriverStats:input_type -> driver.GetDriverStatsRequest
+	4,  // 16: driver.DriverService.ListAvailableDrivers:input_type -> driver.ListAvailableDriversRequest
+	1,  // 17: driver.DriverService.RegisterDriver:output_type -> driver.RegisterDriverResponse
+	1,  // 18: driver.DriverService.UnRegisterDriver:output_type -> driver.RegisterDriverResponse
+	3,  // 19: driver.DriverService.GetDriver:output_type -> driver.GetDriverResponse
+	11, // 20: driver.DriverService.WatchNearbyDrivers:output_type -> driver.NearbyDriversUpdate
+	14, // 21: driver.DriverService.GetDriverStats:output_type -> driver.DriverStats
+	5,  // 22: driver.DriverService.ListAvailableDrivers:output_type -> driver.ListAvailableDriversResponse
+	17, // [17:23] is the sub-list for method output_type
+	11, // [11:17] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_driver_proto_init() }
@@ -330,7 +1142,7 @@ func file_driver_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_driver_proto_rawDesc), len(file_driver_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
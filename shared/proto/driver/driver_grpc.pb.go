@@ -19,8 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DriverService_RegisterDriver_FullMethodName   = "/driver.DriverService/RegisterDriver"
-	DriverService_UnRegisterDriver_FullMethodName = "/driver.DriverService/UnRegisterDriver"
+	DriverService_RegisterDriver_FullMethodName       = "/driver.DriverService/RegisterDriver"
+	DriverService_UnRegisterDriver_FullMethodName     = "/driver.DriverService/UnRegisterDriver"
+	DriverService_GetDriver_FullMethodName            = "/driver.DriverService/GetDriver"
+	DriverService_WatchNearbyDrivers_FullMethodName   = "/driver.DriverService/WatchNearbyDrivers"
+	DriverService_GetDriverStats_FullMethodName       = "/driver.DriverService/GetDriverStats"
+	DriverService_ListAvailableDrivers_FullMethodName = "/driver.DriverService/ListAvailableDrivers"
 )
 
 // DriverServiceClient is the client API for DriverService service.
@@ -29,6 +33,14 @@ const (
 type DriverServiceClient interface {
 	RegisterDriver(ctx context.Context, in *RegisterDriverRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error)
 	UnRegisterDriver(ctx context.Context, in *RegisterDriverRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error)
+	GetDriver(ctx context.Context, in *GetDriverRequest, opts ...grpc.CallOption) (*GetDriverResponse, error)
+	// WatchNearbyDrivers streams the available drivers around an area, for riders to see cars before booking.
+	// The first update holds every driver in the area, the next ones only what changed since the previous one.
+	WatchNearbyDrivers(ctx context.Context, in *WatchNearbyDriversRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NearbyDriversUpdate], error)
+	// GetDriverStats returns how the driver answered offers and was rated over the stats window.
+	GetDriverStats(ctx context.Context, in *GetDriverStatsRequest, opts ...grpc.CallOption) (*DriverStats, error)
+	// ListAvailableDrivers returns every driver free to take a trip, for services rebuilding their view of the supply.
+	ListAvailableDrivers(ctx context.Context, in *ListAvailableDriversRequest, opts ...grpc.CallOption) (*ListAvailableDriversResponse, error)
 }
 
 type driverServiceClient struct {
@@ -59,12 +71,69 @@ func (c *driverServiceClient) UnRegisterDriver(ctx context.Context, in *Register
 	return out, nil
 }
 
+func (c *driverServiceClient) GetDriver(ctx context.Context, in *GetDriverRequest, opts ...grpc.CallOption) (*GetDriverResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDriverResponse)
+	err := c.cc.Invoke(ctx, DriverService_GetDriver_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) WatchNearbyDrivers(ctx context.Context, in *WatchNearbyDriversRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NearbyDriversUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DriverService_ServiceDesc.Streams[0], DriverService_WatchNearbyDrivers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchNearbyDriversRequest, NearbyDriversUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DriverService_WatchNearbyDriversClient = grpc.ServerStreamingClient[NearbyDriversUpdate]
+
+func (c *driverServiceClient) GetDriverStats(ctx context.Context, in *GetDriverStatsRequest, opts ...grpc.CallOption) (*DriverStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DriverStats)
+	err := c.cc.Invoke(ctx, DriverService_GetDriverStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) ListAvailableDrivers(ctx context.Context, in *ListAvailableDriversRequest, opts ...grpc.CallOption) (*ListAvailableDriversResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAvailableDriversResponse)
+	err := c.cc.Invoke(ctx, DriverService_ListAvailableDrivers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DriverServiceServer is the server API for DriverService service.
 // All implementations must embed UnimplementedDriverServiceServer
 // for forward compatibility.
 type DriverServiceServer interface {
 	RegisterDriver(context.Context, *RegisterDriverRequest) (*RegisterDriverResponse, error)
 	UnRegisterDriver(context.Context, *RegisterDriverRequest) (*RegisterDriverResponse, error)
+	GetDriver(context.Context, *GetDriverRequest) (*GetDriverResponse, error)
+	// WatchNearbyDrivers streams the available drivers around an area, for riders to see cars before booking.
+	// The first update holds every driver in the area, the next ones only what changed since the previous one.
+	WatchNearbyDrivers(*WatchNearbyDriversRequest, grpc.ServerStreamingServer[NearbyDriversUpdate]) error
+	// GetDriverStats returns how the driver answered offers and was rated over the stats window.
+	GetDriverStats(context.Context, *GetDriverStatsRequest) (*DriverStats, error)
+	// ListAvailableDrivers returns every driver free to take a trip, for services rebuilding their view of the supply.
+	ListAvailableDrivers(context.Context, *ListAvailableDriversRequest) (*ListAvailableDriversResponse, error)
 	mustEmbedUnimplementedDriverServiceServer()
 }
 
@@ -81,6 +150,18 @@ func (UnimplementedDriverServiceServer) RegisterDriver(context.Context, *Registe
 func (UnimplementedDriverServiceServer) UnRegisterDriver(context.Context, *RegisterDriverRequest) (*RegisterDriverResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UnRegisterDriver not implemented")
 }
+func (UnimplementedDriverServiceServer) GetDriver(context.Context, *GetDriverRequest) (*GetDriverResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriver not implemented")
+}
+func (UnimplementedDriverServiceServer) WatchNearbyDrivers(*WatchNearbyDriversRequest, grpc.ServerStreamingServer[NearbyDriversUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchNearbyDrivers not implemented")
+}
+func (UnimplementedDriverServiceServer) GetDriverStats(context.Context, *GetDriverStatsRequest) (*DriverStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriverStats not implemented")
+}
+func (UnimplementedDriverServiceServer) ListAvailableDrivers(context.Context, *ListAvailableDriversRequest) (*ListAvailableDriversResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAvailableDrivers not implemented")
+}
 func (UnimplementedDriverServiceServer) mustEmbedUnimplementedDriverServiceServer() {}
 func (UnimplementedDriverServiceServer) testEmbeddedByValue()                       {}
 
@@ -138,6 +219,71 @@ func _DriverService_UnRegisterDriver_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DriverService_GetDriver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).GetDriver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_GetDriver_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).GetDriver(ctx, req.(*GetDriverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_WatchNearbyDrivers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchNearbyDriversRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DriverServiceServer).WatchNearbyDrivers(m, &grpc.GenericServerStream[WatchNearbyDriversRequest, NearbyDriversUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DriverService_WatchNearbyDriversServer = grpc.ServerStreamingServer[NearbyDriversUpdate]
+
+func _DriverService_GetDriverStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).GetDriverStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_GetDriverStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).GetDriverStats(ctx, req.(*GetDriverStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_ListAvailableDrivers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAvailableDriversRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).ListAvailableDrivers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_ListAvailableDrivers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).ListAvailableDrivers(ctx, req.(*ListAvailableDriversRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DriverService_ServiceDesc is the grpc.ServiceDesc for DriverService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,7 +299,25 @@ var DriverService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UnRegisterDriver",
 			Handler:    _DriverService_UnRegisterDriver_Handler,
 		},
+		{
+			MethodName: "GetDriver",
+			Handler:    _DriverService_GetDriver_Handler,
+		},
+		{
+			MethodName: "GetDriverStats",
+			Handler:    _DriverService_GetDriverStats_Handler,
+		},
+		{
+			MethodName: "ListAvailableDrivers",
+			Handler:    _DriverService_ListAvailableDrivers_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchNearbyDrivers",
+			Handler:       _DriverService_WatchNearbyDrivers_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "driver.proto",
 }
@@ -0,0 +1,999 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: payment.proto
+
+package payment
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	money "ride-sharing/shared/proto/money"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetDriverStatementRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverID string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	// daily or weekly, weeks go from Monday to Sunday
+	Period string `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	// Any day of the period as YYYY-MM-DD, today when empty
+	Date          string `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverStatementRequest) Reset() {
+	*x = GetDriverStatementRequest{}
+	mi := &file_payment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverStatementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverStatementRequest) ProtoMessage() {}
+
+func (x *GetDriverStatementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverStatementRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverStatementRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetDriverStatementRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *GetDriverStatementRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *GetDriverStatementRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type DriverStatement struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverID string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	Period   string                 `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	// Unix timestamps in seconds, from is inclusive and to exclusive
+	From    int64          `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	To      int64          `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`
+	Entries []*LedgerEntry `protobuf:"bytes,5,rep,name=entries,proto3" json:"entries,omitempty"`
+	Trips   int32          `protobuf:"varint,6,opt,name=trips,proto3" json:"trips,omitempty"`
+	// What riders paid for the driver's trips, before commission
+	Fares        *money.Money `protobuf:"bytes,7,opt,name=fares,proto3" json:"fares,omitempty"`
+	Commission   *money.Money `protobuf:"bytes,8,opt,name=commission,proto3" json:"commission,omitempty"`
+	TripEarnings *money.Money `protobuf:"bytes,9,opt,name=tripEarnings,proto3" json:"tripEarnings,omitempty"`
+	Tips         *money.Money `protobuf:"bytes,10,opt,name=tips,proto3" json:"tips,omitempty"`
+	Adjustments  *money.Money `protobuf:"bytes,11,opt,name=adjustments,proto3" json:"adjustments,omitempty"`
+	// Trip earnings, tips and adjustments of the period
+	Earnings      *money.Money `protobuf:"bytes,12,opt,name=earnings,proto3" json:"earnings,omitempty"`
+	Payouts       *money.Money `protobuf:"bytes,13,opt,name=payouts,proto3" json:"payouts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DriverStatement) Reset() {
+	*x = DriverStatement{}
+	mi := &file_payment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriverStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriverStatement) ProtoMessage() {}
+
+func (x *DriverStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriverStatement.ProtoReflect.Descriptor instead.
+func (*DriverStatement) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DriverStatement) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *DriverStatement) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *DriverStatement) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *DriverStatement) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *DriverStatement) GetEntries() []*LedgerEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *DriverStatement) GetTrips() int32 {
+	if x != nil {
+		return x.Trips
+	}
+	return 0
+}
+
+func (x *DriverStatement) GetFares() *money.Money {
+	if x != nil {
+		return x.Fares
+	}
+	return nil
+}
+
+func (x *DriverStatement) GetCommission() *money.Money {
+	if x != nil {
+		return x.Commission
+	}
+	return nil
+}
+
+func (x *DriverStatement) GetTripEarnings() *money.Money {
+	if x != nil {
+		return x.TripEarnings
+	}
+	return nil
+}
+
+func (x *DriverStatement) GetTips() *money.Money {
+	if x != nil {
+		return x.Tips
+	}
+	return nil
+}
+
+func (x *DriverStatement) GetAdjustments() *money.Money {
+	if x != nil {
+		return x.Adjustments
+	}
+	return nil
+}
+
+func (x *DriverStatement) GetEarnings() *money.Money {
+	if x != nil {
+		return x.Earnings
+	}
+	return nil
+}
+
+func (x *DriverStatement) GetPayouts() *money.Money {
+	if x != nil {
+		return x.Payouts
+	}
+	return nil
+}
+
+type LedgerEntry struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DriverID string                 `protobuf:"bytes,2,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	// One of trip, tip, adjustment or payout
+	Type   string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	TripID string `protobuf:"bytes,4,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	// What the entry adds to the driver's balance, negative for payouts
+	Amount *money.Money `protobuf:"bytes,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Set on trip entries, amount is the fare minus the commission
+	Fare        *money.Money `protobuf:"bytes,6,opt,name=fare,proto3" json:"fare,omitempty"`
+	Commission  *money.Money `protobuf:"bytes,7,opt,name=commission,proto3" json:"commission,omitempty"`
+	Description string       `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	// Unix timestamp in seconds
+	CreatedAt     int64 `protobuf:"varint,9,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LedgerEntry) Reset() {
+	*x = LedgerEntry{}
+	mi := &file_payment_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LedgerEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LedgerEntry) ProtoMessage() {}
+
+func (x *LedgerEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LedgerEntry.ProtoReflect.Descriptor instead.
+func (*LedgerEntry) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LedgerEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetAmount() *money.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *LedgerEntry) GetFare() *money.Money {
+	if x != nil {
+		return x.Fare
+	}
+	return nil
+}
+
+func (x *LedgerEntry) GetCommission() *money.Money {
+	if x != nil {
+		return x.Commission
+	}
+	return nil
+}
+
+func (x *LedgerEntry) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type ExportDriverStatementResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Csv           []byte                 `protobuf:"bytes,2,opt,name=csv,proto3" json:"csv,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportDriverStatementResponse) Reset() {
+	*x = ExportDriverStatementResponse{}
+	mi := &file_payment_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportDriverStatementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportDriverStatementResponse) ProtoMessage() {}
+
+func (x *ExportDriverStatementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportDriverStatementResponse.ProtoReflect.Descriptor instead.
+func (*ExportDriverStatementResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ExportDriverStatementResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ExportDriverStatementResponse) GetCsv() []byte {
+	if x != nil {
+		return x.Csv
+	}
+	return nil
+}
+
+type RecordTipRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TripID        string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	Amount        *money.Money           `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordTipRequest) Reset() {
+	*x = RecordTipRequest{}
+	mi := &file_payment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordTipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordTipRequest) ProtoMessage() {}
+
+func (x *RecordTipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordTipRequest.ProtoReflect.Descriptor instead.
+func (*RecordTipRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RecordTipRequest) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *RecordTipRequest) GetAmount() *money.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+type RecordAdjustmentRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverID string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	// Positive to credit the driver, negative to debit
+	Amount        *money.Money `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason        string       `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordAdjustmentRequest) Reset() {
+	*x = RecordAdjustmentRequest{}
+	mi := &file_payment_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordAdjustmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordAdjustmentRequest) ProtoMessage() {}
+
+func (x *RecordAdjustmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordAdjustmentRequest.ProtoReflect.Descriptor instead.
+func (*RecordAdjustmentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RecordAdjustmentRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *RecordAdjustmentRequest) GetAmount() *money.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *RecordAdjustmentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type PayoutDriverRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverID      string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayoutDriverRequest) Reset() {
+	*x = PayoutDriverRequest{}
+	mi := &file_payment_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayoutDriverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayoutDriverRequest) ProtoMessage() {}
+
+func (x *PayoutDriverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayoutDriverRequest.ProtoReflect.Descriptor instead.
+func (*PayoutDriverRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PayoutDriverRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+type Payout struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DriverID string                 `protobuf:"bytes,2,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	Amount   *money.Money           `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Identifies the transfer at the payout processor
+	Reference     string `protobuf:"bytes,4,opt,name=reference,proto3" json:"reference,omitempty"`
+	CreatedAt     int64  `protobuf:"varint,5,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payout) Reset() {
+	*x = Payout{}
+	mi := &file_payment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payout) ProtoMessage() {}
+
+func (x *Payout) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payout.ProtoReflect.Descriptor instead.
+func (*Payout) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Payout) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Payout) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *Payout) GetAmount() *money.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Payout) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *Payout) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type GetTripReceiptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TripID        string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	UserID        string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripReceiptRequest) Reset() {
+	*x = GetTripReceiptRequest{}
+	mi := &file_payment_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripReceiptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripReceiptRequest) ProtoMessage() {}
+
+func (x *GetTripReceiptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripReceiptRequest.ProtoReflect.Descriptor instead.
+func (*GetTripReceiptRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTripReceiptRequest) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *GetTripReceiptRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+type TripReceipt struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TripID   string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	UserID   string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	DriverID string                 `protobuf:"bytes,3,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	Amount   *money.Money           `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Breakdown of amount, empty when the payment was charged as a single item
+	LineItems []*ReceiptLineItem `protobuf:"bytes,5,rep,name=lineItems,proto3" json:"lineItems,omitempty"`
+	// Unix timestamps in seconds
+	CreatedAt     int64 `protobuf:"varint,6,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	PaidAt        int64 `protobuf:"varint,7,opt,name=paidAt,proto3" json:"paidAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TripReceipt) Reset() {
+	*x = TripReceipt{}
+	mi := &file_payment_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TripReceipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TripReceipt) ProtoMessage() {}
+
+func (x *TripReceipt) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TripReceipt.ProtoReflect.Descriptor instead.
+func (*TripReceipt) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TripReceipt) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *TripReceipt) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *TripReceipt) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *TripReceipt) GetAmount() *money.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *TripReceipt) GetLineItems() []*ReceiptLineItem {
+	if x != nil {
+		return x.LineItems
+	}
+	return nil
+}
+
+func (x *TripReceipt) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *TripReceipt) GetPaidAt() int64 {
+	if x != nil {
+		return x.PaidAt
+	}
+	return 0
+}
+
+type ReceiptLineItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Amount        *money.Money           `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiptLineItem) Reset() {
+	*x = ReceiptLineItem{}
+	mi := &file_payment_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiptLineItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiptLineItem) ProtoMessage() {}
+
+func (x *ReceiptLineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiptLineItem.ProtoReflect.Descriptor instead.
+func (*ReceiptLineItem) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReceiptLineItem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ReceiptLineItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ReceiptLineItem) GetAmount() *money.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+var File_payment_proto protoreflect.FileDescriptor
+
+const file_payment_proto_rawDesc = "" +
+	"\n" +
+	"\rpayment.proto\x12\apayment\x1a\vmoney.proto\"c\n" +
+	"\x19GetDriverStatementRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12\x16\n" +
+	"\x06period\x18\x02 \x01(\tR\x06period\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\"\xd7\x03\n" +
+	"\x0fDriverStatement\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12\x16\n" +
+	"\x06period\x18\x02 \x01(\tR\x06period\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12.\n" +
+	"\aentries\x18\x05 \x03(\v2\x14.payment.LedgerEntryR\aentries\x12\x14\n" +
+	"\x05trips\x18\x06 \x01(\x05R\x05trips\x12\"\n" +
+	"\x05fares\x18\a \x01(\v2\f.money.MoneyR\x05fares\x12,\n" +
+	"\n" +
+	"commission\x18\b \x01(\v2\f.money.MoneyR\n" +
+	"commission\x120\n" +
+	"\ftripEarnings\x18\t \x01(\v2\f.money.MoneyR\ftripEarnings\x12 \n" +
+	"\x04tips\x18\n" +
+	" \x01(\v2\f.money.MoneyR\x04tips\x12.\n" +
+	"\vadjustments\x18\v \x01(\v2\f.money.MoneyR\vadjustments\x12(\n" +
+	"\bearnings\x18\f \x01(\v2\f.money.MoneyR\bearnings\x12&\n" +
+	"\apayouts\x18\r \x01(\v2\f.money.MoneyR\apayouts\"\x9b\x02\n" +
+	"\vLedgerEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bdriverID\x18\x02 \x01(\tR\bdriverID\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06tripID\x18\x04 \x01(\tR\x06tripID\x12$\n" +
+	"\x06amount\x18\x05 \x01(\v2\f.money.MoneyR\x06amount\x12 \n" +
+	"\x04fare\x18\x06 \x01(\v2\f.money.MoneyR\x04fare\x12,\n" +
+	"\n" +
+	"commission\x18\a \x01(\v2\f.money.MoneyR\n" +
+	"commission\x12 \n" +
+	"\vdescription\x18\b \x01(\tR\vdescription\x12\x1c\n" +
+	"\tcreatedAt\x18\t \x01(\x03R\tcreatedAt\"M\n" +
+	"\x1dExportDriverStatementResponse\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x10\n" +
+	"\x03csv\x18\x02 \x01(\fR\x03csv\"P\n" +
+	"\x10RecordTipRequest\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12$\n" +
+	"\x06amount\x18\x02 \x01(\v2\f.money.MoneyR\x06amount\"s\n" +
+	"\x17RecordAdjustmentRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12$\n" +
+	"\x06amount\x18\x02 \x01(\v2\f.money.MoneyR\x06amount\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"1\n" +
+	"\x13PayoutDriverRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\"\x96\x01\n" +
+	"\x06Payout\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bdriverID\x18\x02 \x01(\tR\bdriverID\x12$\n" +
+	"\x06amount\x18\x03 \x01(\v2\f.money.MoneyR\x06amount\x12\x1c\n" +
+	"\treference\x18\x04 \x01(\tR\treference\x12\x1c\n" +
+	"\tcreatedAt\x18\x05 \x01(\x03R\tcreatedAt\"G\n" +
+	"\x15GetTripReceiptRequest\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\"\xed\x01\n" +
+	"\vTripReceipt\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12\x1a\n" +
+	"\bdriverID\x18\x03 \x01(\tR\bdriverID\x12$\n" +
+	"\x06amount\x18\x04 \x01(\v2\f.money.MoneyR\x06amount\x126\n" +
+	"\tlineItems\x18\x05 \x03(\v2\x18.payment.ReceiptLineItemR\tlineItems\x12\x1c\n" +
+	"\tcreatedAt\x18\x06 \x01(\x03R\tcreatedAt\x12\x16\n" +
+	"\x06paidAt\x18\a \x01(\x03R\x06paidAt\"m\n" +
+	"\x0fReceiptLineItem\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12$\n" +
+	"\x06amount\x18\x03 \x01(\v2\f.money.MoneyR\x06amount2\xda\x03\n" +
+	"\x0ePaymentService\x12R\n" +
+	"\x12GetDriverStatement\x12\".payment.GetDriverStatementRequest\x1a\x18.payment.DriverStatement\x12c\n" +
+	"\x15ExportDriverStatement\x12\".payment.GetDriverStatementRequest\x1a&.payment.ExportDriverStatementResponse\x12<\n" +
+	"\tRecordTip\x12\x19.payment.RecordTipRequest\x1a\x14.payment.LedgerEntry\x12J\n" +
+	"\x10RecordAdjustment\x12 .payment.RecordAdjustmentRequest\x1a\x14.payment.LedgerEntry\x12=\n" +
+	"\fPayoutDriver\x12\x1c.payment.PayoutDriverRequest\x1a\x0f.payment.Payout\x12F\n" +
+	"\x0eGetTripReceipt\x12\x1e.payment.GetTripReceiptRequest\x1a\x14.payment.TripReceiptB+Z)ride-sharing/shared/proto/payment;paymentb\x06proto3"
+
+var (
+	file_payment_proto_rawDescOnce sync.Once
+	file_payment_proto_rawDescData []byte
+)
+
+func file_payment_proto_rawDescGZIP() []byte {
+	file_payment_proto_rawDescOnce.Do(func() {
+		file_payment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_payment_proto_rawDesc), len(file_payment_proto_rawDesc)))
+	})
+	return file_payment_proto_rawDescData
+}
+
+var file_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_payment_proto_goTypes = []any{
+	(*GetDriverStatementRequest)(nil),     // 0: payment.GetDriverStatementRequest
+	(*DriverStatement)(nil),               // 1: payment.DriverStatement
+	(*LedgerEntry)(nil),                   // 2: payment.LedgerEntry
+	(*ExportDriverStatementResponse)(nil), // 3: payment.ExportDriverStatementResponse
+	(*RecordTipRequest)(nil),              // 4: payment.RecordTipRequest
+	(*RecordAdjustmentRequest)(nil),       // 5: payment.RecordAdjustmentRequest
+	(*PayoutDriverRequest)(nil),           // 6: payment.PayoutDriverRequest
+	(*Payout)(nil),                        // 7: payment.Payout
+	(*GetTripReceiptRequest)(nil),         // 8: payment.GetTripReceiptRequest
+	(*TripReceipt)(nil),                   // 9: payment.TripReceipt
+	(*ReceiptLineItem)(nil),               // 10: payment.ReceiptLineItem
+	(*money.Money)(nil),                   // 11: money.Money
+}
+var file_payment_proto_depIdxs = []int32{
+	2,  // 0: payment.DriverStatement.entries:type_name -> payment.LedgerEntry
+	11, // 1: payment.DriverStatement.fares:type_name -> money.Money
+	11, // 2: payment.DriverStatement.commission:type_name -> money.Money
+	11, // 3: payment.DriverStatement.tripEarnings:type_name -> money.Money
+	11, // 4: payment.DriverStatement.tips:type_name -> money.Money
+	11, // 5: payment.DriverStatement.adjustments:type_name -> money.Money
+	11, // 6: payment.DriverStatement.earnings:type_name -> money.Money
+	11, // 7: payment.DriverStatement.payouts:type_name -> money.Money
+	11, // 8: payment.LedgerEntry.amount:type_name -> money.Money
+	11, // 9: payment.LedgerEntry.fare:type_name -> money.Money
+	11, // 10: payment.LedgerEntry.commission:type_name -> money.Money
+	11, // 11: payment.RecordTipRequest.amount:type_name -> money.Money
+	11, // 12: payment.RecordAdjustmentRequest.amount:type_name -> money.Money
+	11, // 13: payment.Payout.amount:type_name -> money.Money
+	11, // 14: payment.TripReceipt.amount:type_name -> money.Money
+	10, // 15: payment.TripReceipt.lineItems:type_name -> payment.ReceiptLineItem
+	11, // 16: payment.ReceiptLineItem.amount:type_name -> money.Money
+	0,  // 17: payment.PaymentService.GetDriverStatement:input_type -> payment.GetDriverStatementRequest
+	0,  // 18: payment.PaymentService.ExportDriverStatement:input_type -> payment.GetDriverStatementRequest
+	4,  // 19: payment.PaymentService.RecordTip:input_type -> payment.RecordTipRequest
+	5,  // 20: payment.PaymentService.RecordAdjustment:input_type -> payment.RecordAdjustmentRequest
+	6,  // 21: payment.PaymentService.PayoutDriver:input_type -> payment.PayoutDriverRequest
+	8,  // 22: payment.PaymentService.GetTripReceipt:input_type -> payment.GetTripReceiptRequest
+	1,  // 23: payment.PaymentService.GetDriverStatement:output_type -> payment.DriverStatement
+	3,  // 24: payment.PaymentService.ExportDriverStatement:output_type -> payment.ExportDriverStatementResponse
+	2,  // 25: payment.PaymentService.RecordTip:output_type -> payment.LedgerEntry
+	2,  // 26: payment.PaymentService.RecordAdjustment:output_type -> payment.LedgerEntry
+	7,  // 27: payment.PaymentService.PayoutDriver:output_type -> payment.Payout
+	9,  // 28: payment.PaymentService.GetTripReceipt:output_type -> payment.TripReceipt
+	23, // [23:29] is the sub-list for method output_type
+	17, // [17:23] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_payment_proto_init() }
+func file_payment_proto_init() {
+	if File_payment_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_payment_proto_rawDesc), len(file_payment_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_payment_proto_goTypes,
+		DependencyIndexes: file_payment_proto_depIdxs,
+		MessageInfos:      file_payment_proto_msgTypes,
+	}.Build()
+	File_payment_proto = out.File
+	file_payment_proto_goTypes = nil
+	file_payment_proto_depIdxs = nil
+}
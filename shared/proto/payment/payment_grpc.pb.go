@@ -0,0 +1,315 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: payment.proto
+
+package payment
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PaymentService_GetDriverStatement_FullMethodName    = "/payment.PaymentService/GetDriverStatement"
+	PaymentService_ExportDriverStatement_FullMethodName = "/payment.PaymentService/ExportDriverStatement"
+	PaymentService_RecordTip_FullMethodName             = "/payment.PaymentService/RecordTip"
+	PaymentService_RecordAdjustment_FullMethodName      = "/payment.PaymentService/RecordAdjustment"
+	PaymentService_PayoutDriver_FullMethodName          = "/payment.PaymentService/PayoutDriver"
+	PaymentService_GetTripReceipt_FullMethodName        = "/payment.PaymentService/GetTripReceipt"
+)
+
+// PaymentServiceClient is the client API for PaymentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PaymentServiceClient interface {
+	GetDriverStatement(ctx context.Context, in *GetDriverStatementRequest, opts ...grpc.CallOption) (*DriverStatement, error)
+	ExportDriverStatement(ctx context.Context, in *GetDriverStatementRequest, opts ...grpc.CallOption) (*ExportDriverStatementResponse, error)
+	RecordTip(ctx context.Context, in *RecordTipRequest, opts ...grpc.CallOption) (*LedgerEntry, error)
+	RecordAdjustment(ctx context.Context, in *RecordAdjustmentRequest, opts ...grpc.CallOption) (*LedgerEntry, error)
+	// PayoutDriver pays the driver's whole balance out
+	PayoutDriver(ctx context.Context, in *PayoutDriverRequest, opts ...grpc.CallOption) (*Payout, error)
+	// GetTripReceipt returns the receipt of a paid trip to the rider who paid it
+	GetTripReceipt(ctx context.Context, in *GetTripReceiptRequest, opts ...grpc.CallOption) (*TripReceipt, error)
+}
+
+type paymentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPaymentServiceClient(cc grpc.ClientConnInterface) PaymentServiceClient {
+	return &paymentServiceClient{cc}
+}
+
+func (c *paymentServiceClient) GetDriverStatement(ctx context.Context, in *GetDriverStatementRequest, opts ...grpc.CallOption) (*DriverStatement, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DriverStatement)
+	err := c.cc.Invoke(ctx, PaymentService_GetDriverStatement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ExportDriverStatement(ctx context.Context, in *GetDriverStatementRequest, opts ...grpc.CallOption) (*ExportDriverStatementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportDriverStatementResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ExportDriverStatement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) RecordTip(ctx context.Context, in *RecordTipRequest, opts ...grpc.CallOption) (*LedgerEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LedgerEntry)
+	err := c.cc.Invoke(ctx, PaymentService_RecordTip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) RecordAdjustment(ctx context.Context, in *RecordAdjustmentRequest, opts ...grpc.CallOption) (*LedgerEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LedgerEntry)
+	err := c.cc.Invoke(ctx, PaymentService_RecordAdjustment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) PayoutDriver(ctx context.Context, in *PayoutDriverRequest, opts ...grpc.CallOption) (*Payout, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Payout)
+	err := c.cc.Invoke(ctx, PaymentService_PayoutDriver_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetTripReceipt(ctx context.Context, in *GetTripReceiptRequest, opts ...grpc.CallOption) (*TripReceipt, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TripReceipt)
+	err := c.cc.Invoke(ctx, PaymentService_GetTripReceipt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PaymentServiceServer is the server API for PaymentService service.
+// All implementations must embed UnimplementedPaymentServiceServer
+// for forward compatibility.
+type PaymentServiceServer interface {
+	GetDriverStatement(context.Context, *GetDriverStatementRequest) (*DriverStatement, error)
+	ExportDriverStatement(context.Context, *GetDriverStatementRequest) (*ExportDriverStatementResponse, error)
+	RecordTip(context.Context, *RecordTipRequest) (*LedgerEntry, error)
+	RecordAdjustment(context.Context, *RecordAdjustmentRequest) (*LedgerEntry, error)
+	// PayoutDriver pays the driver's whole balance out
+	PayoutDriver(context.Context, *PayoutDriverRequest) (*Payout, error)
+	// GetTripReceipt returns the receipt of a paid trip to the rider who paid it
+	GetTripReceipt(context.Context, *GetTripReceiptRequest) (*TripReceipt, error)
+	mustEmbedUnimplementedPaymentServiceServer()
+}
+
+// UnimplementedPaymentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPaymentServiceServer struct{}
+
+func (UnimplementedPaymentServiceServer) GetDriverStatement(context.Context, *GetDriverStatementRequest) (*DriverStatement, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriverStatement not implemented")
+}
+func (UnimplementedPaymentServiceServer) ExportDriverStatement(context.Context, *GetDriverStatementRequest) (*ExportDriverStatementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportDriverStatement not implemented")
+}
+func (UnimplementedPaymentServiceServer) RecordTip(context.Context, *RecordTipRequest) (*LedgerEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordTip not implemented")
+}
+func (UnimplementedPaymentServiceServer) RecordAdjustment(context.Context, *RecordAdjustmentRequest) (*LedgerEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordAdjustment not implemented")
+}
+func (UnimplementedPaymentServiceServer) PayoutDriver(context.Context, *PayoutDriverRequest) (*Payout, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PayoutDriver not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetTripReceipt(context.Context, *GetTripReceiptRequest) (*TripReceipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTripReceipt not implemented")
+}
+func (UnimplementedPaymentServiceServer) mustEmbedUnimplementedPaymentServiceServer() {}
+func (UnimplementedPaymentServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafePaymentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PaymentServiceServer will
+// result in compilation errors.
+type UnsafePaymentServiceServer interface {
+	mustEmbedUnimplementedPaymentServiceServer()
+}
+
+func RegisterPaymentServiceServer(s grpc.ServiceRegistrar, srv PaymentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPaymentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PaymentService_ServiceDesc, srv)
+}
+
+func _PaymentService_GetDriverStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetDriverStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetDriverStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetDriverStatement(ctx, req.(*GetDriverStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ExportDriverStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ExportDriverStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ExportDriverStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ExportDriverStatement(ctx, req.(*GetDriverStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_RecordTip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordTipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).RecordTip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_RecordTip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).RecordTip(ctx, req.(*RecordTipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_RecordAdjustment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordAdjustmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).RecordAdjustment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_RecordAdjustment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).RecordAdjustment(ctx, req.(*RecordAdjustmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_PayoutDriver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayoutDriverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).PayoutDriver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_PayoutDriver_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).PayoutDriver(ctx, req.(*PayoutDriverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetTripReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTripReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetTripReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetTripReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetTripReceipt(ctx, req.(*GetTripReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PaymentService_ServiceDesc is the grpc.ServiceDesc for PaymentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PaymentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "payment.PaymentService",
+	HandlerType: (*PaymentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDriverStatement",
+			Handler:    _PaymentService_GetDriverStatement_Handler,
+		},
+		{
+			MethodName: "ExportDriverStatement",
+			Handler:    _PaymentService_ExportDriverStatement_Handler,
+		},
+		{
+			MethodName: "RecordTip",
+			Handler:    _PaymentService_RecordTip_Handler,
+		},
+		{
+			MethodName: "RecordAdjustment",
+			Handler:    _PaymentService_RecordAdjustment_Handler,
+		},
+		{
+			MethodName: "PayoutDriver",
+			Handler:    _PaymentService_PayoutDriver_Handler,
+		},
+		{
+			MethodName: "GetTripReceipt",
+			Handler:    _PaymentService_GetTripReceipt_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "payment.proto",
+}
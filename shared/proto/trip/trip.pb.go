@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
+	money "ride-sharing/shared/proto/money"
 	sync "sync"
 	unsafe "unsafe"
 )
@@ -26,6 +27,8 @@ type PreviewTripRequest struct {
 	UserID        string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
 	StartLocation *Coordinate            `protobuf:"bytes,2,opt,name=startLocation,proto3" json:"startLocation,omitempty"`
 	EndLocation   *Coordinate            `protobuf:"bytes,3,opt,name=endLocation,proto3" json:"endLocation,omitempty"`
+	// Optional promo code discounted in the ride fares
+	PromoCode     string `protobuf:"bytes,4,opt,name=promoCode,proto3" json:"promoCode,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -81,6 +84,13 @@ func (x *PreviewTripRequest) GetEndLocation() *Coordinate {
 	return nil
 }
 
+func (x *PreviewTripRequest) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
 type PreviewTripResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TripID        string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
@@ -90,21 +100,483 @@ type PreviewTripResponse struct {
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PreviewTripResponse) Reset() {
-	*x = PreviewTripResponse{}
-	mi := &file_trip_proto_msgTypes[1]
+func (x *PreviewTripResponse) Reset() {
+	*x = PreviewTripResponse{}
+	mi := &file_trip_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewTripResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewTripResponse) ProtoMessage() {}
+
+func (x *PreviewTripResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewTripResponse.ProtoReflect.Descriptor instead.
+func (*PreviewTripResponse) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PreviewTripResponse) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *PreviewTripResponse) GetRoute() *Route {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+func (x *PreviewTripResponse) GetRideFares() []*RideFare {
+	if x != nil {
+		return x.RideFares
+	}
+	return nil
+}
+
+type CreateTripRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RideFareID    string                 `protobuf:"bytes,1,opt,name=rideFareID,proto3" json:"rideFareID,omitempty"`
+	UserID        string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTripRequest) Reset() {
+	*x = CreateTripRequest{}
+	mi := &file_trip_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTripRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTripRequest) ProtoMessage() {}
+
+func (x *CreateTripRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTripRequest.ProtoReflect.Descriptor instead.
+func (*CreateTripRequest) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTripRequest) GetRideFareID() string {
+	if x != nil {
+		return x.RideFareID
+	}
+	return ""
+}
+
+func (x *CreateTripRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+type CreateTripResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TripID        string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	Trip          *Trip                  `protobuf:"bytes,2,opt,name=trip,proto3" json:"trip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTripResponse) Reset() {
+	*x = CreateTripResponse{}
+	mi := &file_trip_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTripResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTripResponse) ProtoMessage() {}
+
+func (x *CreateTripResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTripResponse.ProtoReflect.Descriptor instead.
+func (*CreateTripResponse) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateTripResponse) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *CreateTripResponse) GetTrip() *Trip {
+	if x != nil {
+		return x.Trip
+	}
+	return nil
+}
+
+type CancelTripRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TripID string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	// ID of the rider or driver cancelling the trip
+	UserID string `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	// "rider" or "driver"
+	Role          string `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelTripRequest) Reset() {
+	*x = CancelTripRequest{}
+	mi := &file_trip_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTripRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTripRequest) ProtoMessage() {}
+
+func (x *CancelTripRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTripRequest.ProtoReflect.Descriptor instead.
+func (*CancelTripRequest) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CancelTripRequest) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *CancelTripRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *CancelTripRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *CancelTripRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type CancelTripResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Trip            *Trip                  `protobuf:"bytes,1,opt,name=trip,proto3" json:"trip,omitempty"`
+	CancellationFee *money.Money           `protobuf:"bytes,3,opt,name=cancellationFee,proto3" json:"cancellationFee,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CancelTripResponse) Reset() {
+	*x = CancelTripResponse{}
+	mi := &file_trip_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTripResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTripResponse) ProtoMessage() {}
+
+func (x *CancelTripResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTripResponse.ProtoReflect.Descriptor instead.
+func (*CancelTripResponse) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CancelTripResponse) GetTrip() *Trip {
+	if x != nil {
+		return x.Trip
+	}
+	return nil
+}
+
+func (x *CancelTripResponse) GetCancellationFee() *money.Money {
+	if x != nil {
+		return x.CancellationFee
+	}
+	return nil
+}
+
+type RateTripRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TripID string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	// ID of the rider of the trip, only they can rate its driver
+	UserID string `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	// From 1 to 5 stars
+	Rating        int32  `protobuf:"varint,3,opt,name=rating,proto3" json:"rating,omitempty"`
+	Comment       string `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateTripRequest) Reset() {
+	*x = RateTripRequest{}
+	mi := &file_trip_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateTripRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateTripRequest) ProtoMessage() {}
+
+func (x *RateTripRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateTripRequest.ProtoReflect.Descriptor instead.
+func (*RateTripRequest) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RateTripRequest) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *RateTripRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *RateTripRequest) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *RateTripRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type RateTripResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trip          *Trip                  `protobuf:"bytes,1,opt,name=trip,proto3" json:"trip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateTripResponse) Reset() {
+	*x = RateTripResponse{}
+	mi := &file_trip_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateTripResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateTripResponse) ProtoMessage() {}
+
+func (x *RateTripResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateTripResponse.ProtoReflect.Descriptor instead.
+func (*RateTripResponse) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RateTripResponse) GetTrip() *Trip {
+	if x != nil {
+		return x.Trip
+	}
+	return nil
+}
+
+type UpdatePickupRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TripID string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	// ID of the rider of the trip, only they can move its pickup
+	UserID        string      `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	Pickup        *Coordinate `protobuf:"bytes,3,opt,name=pickup,proto3" json:"pickup,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePickupRequest) Reset() {
+	*x = UpdatePickupRequest{}
+	mi := &file_trip_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePickupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePickupRequest) ProtoMessage() {}
+
+func (x *UpdatePickupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePickupRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePickupRequest) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdatePickupRequest) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *UpdatePickupRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *UpdatePickupRequest) GetPickup() *Coordinate {
+	if x != nil {
+		return x.Pickup
+	}
+	return nil
+}
+
+type AddStopRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TripID string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	// ID of the rider of the trip, only they can add stops to it
+	UserID        string      `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	Location      *Coordinate `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddStopRequest) Reset() {
+	*x = AddStopRequest{}
+	mi := &file_trip_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PreviewTripResponse) String() string {
+func (x *AddStopRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PreviewTripResponse) ProtoMessage() {}
+func (*AddStopRequest) ProtoMessage() {}
 
-func (x *PreviewTripResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[1]
+func (x *AddStopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -115,55 +587,54 @@ func (x *PreviewTripResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PreviewTripResponse.ProtoReflect.Descriptor instead.
-func (*PreviewTripResponse) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use AddStopRequest.ProtoReflect.Descriptor instead.
+func (*AddStopRequest) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *PreviewTripResponse) GetTripID() string {
+func (x *AddStopRequest) GetTripID() string {
 	if x != nil {
 		return x.TripID
 	}
 	return ""
 }
 
-func (x *PreviewTripResponse) GetRoute() *Route {
+func (x *AddStopRequest) GetUserID() string {
 	if x != nil {
-		return x.Route
+		return x.UserID
 	}
-	return nil
+	return ""
 }
 
-func (x *PreviewTripResponse) GetRideFares() []*RideFare {
+func (x *AddStopRequest) GetLocation() *Coordinate {
 	if x != nil {
-		return x.RideFares
+		return x.Location
 	}
 	return nil
 }
 
-type CreateTripRequest struct {
+type UpdateTripResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RideFareID    string                 `protobuf:"bytes,1,opt,name=rideFareID,proto3" json:"rideFareID,omitempty"`
-	UserID        string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	Trip          *Trip                  `protobuf:"bytes,1,opt,name=trip,proto3" json:"trip,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTripRequest) Reset() {
-	*x = CreateTripRequest{}
-	mi := &file_trip_proto_msgTypes[2]
+func (x *UpdateTripResponse) Reset() {
+	*x = UpdateTripResponse{}
+	mi := &file_trip_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTripRequest) String() string {
+func (x *UpdateTripResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTripRequest) ProtoMessage() {}
+func (*UpdateTripResponse) ProtoMessage() {}
 
-func (x *CreateTripRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[2]
+func (x *UpdateTripResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -174,48 +645,94 @@ func (x *CreateTripRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTripRequest.ProtoReflect.Descriptor instead.
-func (*CreateTripRequest) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use UpdateTripResponse.ProtoReflect.Descriptor instead.
+func (*UpdateTripResponse) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *CreateTripRequest) GetRideFareID() string {
+func (x *UpdateTripResponse) GetTrip() *Trip {
 	if x != nil {
-		return x.RideFareID
+		return x.Trip
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateTripRequest) GetUserID() string {
+type ListPackagesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Either the service area slug or a location inside the area, defaults to the default area
+	ServiceArea   string      `protobuf:"bytes,1,opt,name=serviceArea,proto3" json:"serviceArea,omitempty"`
+	Location      *Coordinate `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPackagesRequest) Reset() {
+	*x = ListPackagesRequest{}
+	mi := &file_trip_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPackagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPackagesRequest) ProtoMessage() {}
+
+func (x *ListPackagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[11]
 	if x != nil {
-		return x.UserID
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPackagesRequest.ProtoReflect.Descriptor instead.
+func (*ListPackagesRequest) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListPackagesRequest) GetServiceArea() string {
+	if x != nil {
+		return x.ServiceArea
 	}
 	return ""
 }
 
-type CreateTripResponse struct {
+func (x *ListPackagesRequest) GetLocation() *Coordinate {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type ListPackagesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TripID        string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
-	Trip          *Trip                  `protobuf:"bytes,2,opt,name=trip,proto3" json:"trip,omitempty"`
+	ServiceArea   string                 `protobuf:"bytes,1,opt,name=serviceArea,proto3" json:"serviceArea,omitempty"`
+	Packages      []*CarPackage          `protobuf:"bytes,2,rep,name=packages,proto3" json:"packages,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTripResponse) Reset() {
-	*x = CreateTripResponse{}
-	mi := &file_trip_proto_msgTypes[3]
+func (x *ListPackagesResponse) Reset() {
+	*x = ListPackagesResponse{}
+	mi := &file_trip_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTripResponse) String() string {
+func (x *ListPackagesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTripResponse) ProtoMessage() {}
+func (*ListPackagesResponse) ProtoMessage() {}
 
-func (x *CreateTripResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[3]
+func (x *ListPackagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -226,21 +743,122 @@ func (x *CreateTripResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTripResponse.ProtoReflect.Descriptor instead.
-func (*CreateTripResponse) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ListPackagesResponse.ProtoReflect.Descriptor instead.
+func (*ListPackagesResponse) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *CreateTripResponse) GetTripID() string {
+func (x *ListPackagesResponse) GetServiceArea() string {
 	if x != nil {
-		return x.TripID
+		return x.ServiceArea
 	}
 	return ""
 }
 
-func (x *CreateTripResponse) GetTrip() *Trip {
+func (x *ListPackagesResponse) GetPackages() []*CarPackage {
 	if x != nil {
-		return x.Trip
+		return x.Packages
+	}
+	return nil
+}
+
+type CarPackage struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Slug        string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	DisplayName string                 `protobuf:"bytes,2,opt,name=displayName,proto3" json:"displayName,omitempty"`
+	// Tax applied on top of the fare, e.g. 8.5 for 8.5%
+	TaxRatePercent float64      `protobuf:"fixed64,9,opt,name=taxRatePercent,proto3" json:"taxRatePercent,omitempty"`
+	BaseFare       *money.Money `protobuf:"bytes,10,opt,name=baseFare,proto3" json:"baseFare,omitempty"`
+	PricePerKm     *money.Money `protobuf:"bytes,11,opt,name=pricePerKm,proto3" json:"pricePerKm,omitempty"`
+	PricePerMinute *money.Money `protobuf:"bytes,12,opt,name=pricePerMinute,proto3" json:"pricePerMinute,omitempty"`
+	MinimumFare    *money.Money `protobuf:"bytes,13,opt,name=minimumFare,proto3" json:"minimumFare,omitempty"`
+	BookingFee     *money.Money `protobuf:"bytes,14,opt,name=bookingFee,proto3" json:"bookingFee,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CarPackage) Reset() {
+	*x = CarPackage{}
+	mi := &file_trip_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarPackage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarPackage) ProtoMessage() {}
+
+func (x *CarPackage) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarPackage.ProtoReflect.Descriptor instead.
+func (*CarPackage) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CarPackage) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *CarPackage) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *CarPackage) GetTaxRatePercent() float64 {
+	if x != nil {
+		return x.TaxRatePercent
+	}
+	return 0
+}
+
+func (x *CarPackage) GetBaseFare() *money.Money {
+	if x != nil {
+		return x.BaseFare
+	}
+	return nil
+}
+
+func (x *CarPackage) GetPricePerKm() *money.Money {
+	if x != nil {
+		return x.PricePerKm
+	}
+	return nil
+}
+
+func (x *CarPackage) GetPricePerMinute() *money.Money {
+	if x != nil {
+		return x.PricePerMinute
+	}
+	return nil
+}
+
+func (x *CarPackage) GetMinimumFare() *money.Money {
+	if x != nil {
+		return x.MinimumFare
+	}
+	return nil
+}
+
+func (x *CarPackage) GetBookingFee() *money.Money {
+	if x != nil {
+		return x.BookingFee
 	}
 	return nil
 }
@@ -255,7 +873,7 @@ type Coordinate struct {
 
 func (x *Coordinate) Reset() {
 	*x = Coordinate{}
-	mi := &file_trip_proto_msgTypes[4]
+	mi := &file_trip_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -267,7 +885,7 @@ func (x *Coordinate) String() string {
 func (*Coordinate) ProtoMessage() {}
 
 func (x *Coordinate) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[4]
+	mi := &file_trip_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -280,7 +898,7 @@ func (x *Coordinate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Coordinate.ProtoReflect.Descriptor instead.
 func (*Coordinate) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{4}
+	return file_trip_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *Coordinate) GetLatitude() float64 {
@@ -306,7 +924,7 @@ type Geometry struct {
 
 func (x *Geometry) Reset() {
 	*x = Geometry{}
-	mi := &file_trip_proto_msgTypes[5]
+	mi := &file_trip_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -318,7 +936,7 @@ func (x *Geometry) String() string {
 func (*Geometry) ProtoMessage() {}
 
 func (x *Geometry) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[5]
+	mi := &file_trip_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -331,7 +949,7 @@ func (x *Geometry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Geometry.ProtoReflect.Descriptor instead.
 func (*Geometry) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{5}
+	return file_trip_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *Geometry) GetCoordinates() []*Coordinate {
@@ -352,7 +970,7 @@ type Route struct {
 
 func (x *Route) Reset() {
 	*x = Route{}
-	mi := &file_trip_proto_msgTypes[6]
+	mi := &file_trip_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -364,7 +982,7 @@ func (x *Route) String() string {
 func (*Route) ProtoMessage() {}
 
 func (x *Route) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[6]
+	mi := &file_trip_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -377,7 +995,7 @@ func (x *Route) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Route.ProtoReflect.Descriptor instead.
 func (*Route) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{6}
+	return file_trip_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *Route) GetGeometry() []*Geometry {
@@ -402,18 +1020,28 @@ func (x *Route) GetDuration() float64 {
 }
 
 type RideFare struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserID            string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
-	PackageSlug       string                 `protobuf:"bytes,3,opt,name=packageSlug,proto3" json:"packageSlug,omitempty"`
-	TotalPriceInCents float64                `protobuf:"fixed64,4,opt,name=totalPriceInCents,proto3" json:"totalPriceInCents,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserID      string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	PackageSlug string                 `protobuf:"bytes,3,opt,name=packageSlug,proto3" json:"packageSlug,omitempty"`
+	// Unix timestamp in seconds after which the fare can no longer be used to start a trip
+	ExpiresAt int64 `protobuf:"varint,5,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	// Breakdown of the total price, the amounts add up to totalPrice
+	LineItems  []*FareLineItem `protobuf:"bytes,7,rep,name=lineItems,proto3" json:"lineItems,omitempty"`
+	TotalPrice *money.Money    `protobuf:"bytes,8,opt,name=totalPrice,proto3" json:"totalPrice,omitempty"`
+	// Surge multiplier quoted with the fare, already included in totalPrice
+	SurgeMultiplier float64 `protobuf:"fixed64,9,opt,name=surgeMultiplier,proto3" json:"surgeMultiplier,omitempty"`
+	// Promo code discounted in the fare, if any
+	PromoCode string `protobuf:"bytes,10,opt,name=promoCode,proto3" json:"promoCode,omitempty"`
+	// Slug of the service area the fare was priced in
+	ServiceArea   string `protobuf:"bytes,11,opt,name=serviceArea,proto3" json:"serviceArea,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RideFare) Reset() {
 	*x = RideFare{}
-	mi := &file_trip_proto_msgTypes[7]
+	mi := &file_trip_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -425,7 +1053,7 @@ func (x *RideFare) String() string {
 func (*RideFare) ProtoMessage() {}
 
 func (x *RideFare) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[7]
+	mi := &file_trip_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -438,7 +1066,7 @@ func (x *RideFare) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RideFare.ProtoReflect.Descriptor instead.
 func (*RideFare) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{7}
+	return file_trip_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *RideFare) GetId() string {
@@ -462,28 +1090,130 @@ func (x *RideFare) GetPackageSlug() string {
 	return ""
 }
 
-func (x *RideFare) GetTotalPriceInCents() float64 {
+func (x *RideFare) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *RideFare) GetLineItems() []*FareLineItem {
+	if x != nil {
+		return x.LineItems
+	}
+	return nil
+}
+
+func (x *RideFare) GetTotalPrice() *money.Money {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return nil
+}
+
+func (x *RideFare) GetSurgeMultiplier() float64 {
 	if x != nil {
-		return x.TotalPriceInCents
+		return x.SurgeMultiplier
 	}
 	return 0
 }
 
+func (x *RideFare) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *RideFare) GetServiceArea() string {
+	if x != nil {
+		return x.ServiceArea
+	}
+	return ""
+}
+
+type FareLineItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of base, distance, time, minimum_fare, surge, booking_fee, discount or tax
+	Type        string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Negative for discounts
+	Amount        *money.Money `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FareLineItem) Reset() {
+	*x = FareLineItem{}
+	mi := &file_trip_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FareLineItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FareLineItem) ProtoMessage() {}
+
+func (x *FareLineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FareLineItem.ProtoReflect.Descriptor instead.
+func (*FareLineItem) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FareLineItem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *FareLineItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *FareLineItem) GetAmount() *money.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
 type Trip struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	SelectedFare  *RideFare              `protobuf:"bytes,2,opt,name=selectedFare,proto3" json:"selectedFare,omitempty"`
-	Route         *Route                 `protobuf:"bytes,3,opt,name=route,proto3" json:"route,omitempty"`
-	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
-	UserID        string                 `protobuf:"bytes,5,opt,name=userID,proto3" json:"userID,omitempty"`
-	Driver        *TripDriver            `protobuf:"bytes,6,opt,name=driver,proto3" json:"driver,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SelectedFare *RideFare              `protobuf:"bytes,2,opt,name=selectedFare,proto3" json:"selectedFare,omitempty"`
+	Route        *Route                 `protobuf:"bytes,3,opt,name=route,proto3" json:"route,omitempty"`
+	Status       string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	UserID       string                 `protobuf:"bytes,5,opt,name=userID,proto3" json:"userID,omitempty"`
+	Driver       *TripDriver            `protobuf:"bytes,6,opt,name=driver,proto3" json:"driver,omitempty"`
+	Pickup       *Coordinate            `protobuf:"bytes,7,opt,name=pickup,proto3" json:"pickup,omitempty"`
+	// Stars the rider rated the driver with, 0 until the trip is rated
+	Rating int32 `protobuf:"varint,8,opt,name=rating,proto3" json:"rating,omitempty"`
+	// Stops the rider added on the way to the destination, in order
+	Stops         []*Coordinate `protobuf:"bytes,9,rep,name=stops,proto3" json:"stops,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Trip) Reset() {
 	*x = Trip{}
-	mi := &file_trip_proto_msgTypes[8]
+	mi := &file_trip_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -495,7 +1225,7 @@ func (x *Trip) String() string {
 func (*Trip) ProtoMessage() {}
 
 func (x *Trip) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[8]
+	mi := &file_trip_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -508,7 +1238,7 @@ func (x *Trip) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Trip.ProtoReflect.Descriptor instead.
 func (*Trip) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{8}
+	return file_trip_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *Trip) GetId() string {
@@ -553,6 +1283,27 @@ func (x *Trip) GetDriver() *TripDriver {
 	return nil
 }
 
+func (x *Trip) GetPickup() *Coordinate {
+	if x != nil {
+		return x.Pickup
+	}
+	return nil
+}
+
+func (x *Trip) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Trip) GetStops() []*Coordinate {
+	if x != nil {
+		return x.Stops
+	}
+	return nil
+}
+
 // Static driver object that is used to store the driver information
 type TripDriver struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
@@ -566,7 +1317,7 @@ type TripDriver struct {
 
 func (x *TripDriver) Reset() {
 	*x = TripDriver{}
-	mi := &file_trip_proto_msgTypes[9]
+	mi := &file_trip_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -578,7 +1329,7 @@ func (x *TripDriver) String() string {
 func (*TripDriver) ProtoMessage() {}
 
 func (x *TripDriver) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[9]
+	mi := &file_trip_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -591,7 +1342,7 @@ func (x *TripDriver) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TripDriver.ProtoReflect.Descriptor instead.
 func (*TripDriver) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{9}
+	return file_trip_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *TripDriver) GetId() string {
@@ -627,11 +1378,12 @@ var File_trip_proto protoreflect.FileDescriptor
 const file_trip_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"trip.proto\x12\x04trip\"\x98\x01\n" +
+	"trip.proto\x12\x04trip\x1a\vmoney.proto\"\xb6\x01\n" +
 	"\x12PreviewTripRequest\x12\x16\n" +
 	"\x06userID\x18\x01 \x01(\tR\x06userID\x126\n" +
 	"\rstartLocation\x18\x02 \x01(\v2\x10.trip.CoordinateR\rstartLocation\x122\n" +
-	"\vendLocation\x18\x03 \x01(\v2\x10.trip.CoordinateR\vendLocation\"~\n" +
+	"\vendLocation\x18\x03 \x01(\v2\x10.trip.CoordinateR\vendLocation\x12\x1c\n" +
+	"\tpromoCode\x18\x04 \x01(\tR\tpromoCode\"~\n" +
 	"\x13PreviewTripResponse\x12\x16\n" +
 	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12!\n" +
 	"\x05route\x18\x02 \x01(\v2\v.trip.RouteR\x05route\x12,\n" +
@@ -644,7 +1396,56 @@ const file_trip_proto_rawDesc = "" +
 	"\x12CreateTripResponse\x12\x16\n" +
 	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x1e\n" +
 	"\x04trip\x18\x02 \x01(\v2\n" +
-	".trip.TripR\x04trip\"F\n" +
+	".trip.TripR\x04trip\"o\n" +
+	"\x11CancelTripRequest\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"r\n" +
+	"\x12CancelTripResponse\x12\x1e\n" +
+	"\x04trip\x18\x01 \x01(\v2\n" +
+	".trip.TripR\x04trip\x126\n" +
+	"\x0fcancellationFee\x18\x03 \x01(\v2\f.money.MoneyR\x0fcancellationFeeJ\x04\b\x02\x10\x03\"s\n" +
+	"\x0fRateTripRequest\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12\x16\n" +
+	"\x06rating\x18\x03 \x01(\x05R\x06rating\x12\x18\n" +
+	"\acomment\x18\x04 \x01(\tR\acomment\"2\n" +
+	"\x10RateTripResponse\x12\x1e\n" +
+	"\x04trip\x18\x01 \x01(\v2\n" +
+	".trip.TripR\x04trip\"o\n" +
+	"\x13UpdatePickupRequest\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12(\n" +
+	"\x06pickup\x18\x03 \x01(\v2\x10.trip.CoordinateR\x06pickup\"n\n" +
+	"\x0eAddStopRequest\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12,\n" +
+	"\blocation\x18\x03 \x01(\v2\x10.trip.CoordinateR\blocation\"4\n" +
+	"\x12UpdateTripResponse\x12\x1e\n" +
+	"\x04trip\x18\x01 \x01(\v2\n" +
+	".trip.TripR\x04trip\"e\n" +
+	"\x13ListPackagesRequest\x12 \n" +
+	"\vserviceArea\x18\x01 \x01(\tR\vserviceArea\x12,\n" +
+	"\blocation\x18\x02 \x01(\v2\x10.trip.CoordinateR\blocation\"f\n" +
+	"\x14ListPackagesResponse\x12 \n" +
+	"\vserviceArea\x18\x01 \x01(\tR\vserviceArea\x12,\n" +
+	"\bpackages\x18\x02 \x03(\v2\x10.trip.CarPackageR\bpackages\"\xdc\x02\n" +
+	"\n" +
+	"CarPackage\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12 \n" +
+	"\vdisplayName\x18\x02 \x01(\tR\vdisplayName\x12&\n" +
+	"\x0etaxRatePercent\x18\t \x01(\x01R\x0etaxRatePercent\x12(\n" +
+	"\bbaseFare\x18\n" +
+	" \x01(\v2\f.money.MoneyR\bbaseFare\x12,\n" +
+	"\n" +
+	"pricePerKm\x18\v \x01(\v2\f.money.MoneyR\n" +
+	"pricePerKm\x124\n" +
+	"\x0epricePerMinute\x18\f \x01(\v2\f.money.MoneyR\x0epricePerMinute\x12.\n" +
+	"\vminimumFare\x18\r \x01(\v2\f.money.MoneyR\vminimumFare\x12,\n" +
+	"\n" +
+	"bookingFee\x18\x0e \x01(\v2\f.money.MoneyR\n" +
+	"bookingFeeJ\x04\b\x03\x10\t\"F\n" +
 	"\n" +
 	"Coordinate\x12\x1a\n" +
 	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
@@ -654,29 +1455,50 @@ const file_trip_proto_rawDesc = "" +
 	"\x05Route\x12*\n" +
 	"\bgeometry\x18\x01 \x03(\v2\x0e.trip.GeometryR\bgeometry\x12\x1a\n" +
 	"\bdistance\x18\x02 \x01(\x01R\bdistance\x12\x1a\n" +
-	"\bduration\x18\x03 \x01(\x01R\bduration\"\x82\x01\n" +
+	"\bduration\x18\x03 \x01(\x01R\bduration\"\xc8\x02\n" +
 	"\bRideFare\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
 	"\x06userID\x18\x02 \x01(\tR\x06userID\x12 \n" +
-	"\vpackageSlug\x18\x03 \x01(\tR\vpackageSlug\x12,\n" +
-	"\x11totalPriceInCents\x18\x04 \x01(\x01R\x11totalPriceInCents\"\xc7\x01\n" +
+	"\vpackageSlug\x18\x03 \x01(\tR\vpackageSlug\x12\x1c\n" +
+	"\texpiresAt\x18\x05 \x01(\x03R\texpiresAt\x120\n" +
+	"\tlineItems\x18\a \x03(\v2\x12.trip.FareLineItemR\tlineItems\x12,\n" +
+	"\n" +
+	"totalPrice\x18\b \x01(\v2\f.money.MoneyR\n" +
+	"totalPrice\x12(\n" +
+	"\x0fsurgeMultiplier\x18\t \x01(\x01R\x0fsurgeMultiplier\x12\x1c\n" +
+	"\tpromoCode\x18\n" +
+	" \x01(\tR\tpromoCode\x12 \n" +
+	"\vserviceArea\x18\v \x01(\tR\vserviceAreaJ\x04\b\x04\x10\x05J\x04\b\x06\x10\a\"p\n" +
+	"\fFareLineItem\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12$\n" +
+	"\x06amount\x18\x04 \x01(\v2\f.money.MoneyR\x06amountJ\x04\b\x03\x10\x04\"\xb1\x02\n" +
 	"\x04Trip\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x122\n" +
 	"\fselectedFare\x18\x02 \x01(\v2\x0e.trip.RideFareR\fselectedFare\x12!\n" +
 	"\x05route\x18\x03 \x01(\v2\v.trip.RouteR\x05route\x12\x16\n" +
 	"\x06status\x18\x04 \x01(\tR\x06status\x12\x16\n" +
 	"\x06userID\x18\x05 \x01(\tR\x06userID\x12(\n" +
-	"\x06driver\x18\x06 \x01(\v2\x10.trip.TripDriverR\x06driver\"t\n" +
+	"\x06driver\x18\x06 \x01(\v2\x10.trip.TripDriverR\x06driver\x12(\n" +
+	"\x06pickup\x18\a \x01(\v2\x10.trip.CoordinateR\x06pickup\x12\x16\n" +
+	"\x06rating\x18\b \x01(\x05R\x06rating\x12&\n" +
+	"\x05stops\x18\t \x03(\v2\x10.trip.CoordinateR\x05stops\"t\n" +
 	"\n" +
 	"TripDriver\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12&\n" +
 	"\x0eprofilePicture\x18\x03 \x01(\tR\x0eprofilePicture\x12\x1a\n" +
-	"\bcarPlate\x18\x04 \x01(\tR\bcarPlate2\x92\x01\n" +
+	"\bcarPlate\x18\x04 \x01(\tR\bcarPlate2\xd5\x03\n" +
 	"\vTripService\x12B\n" +
 	"\vPreviewTrip\x12\x18.trip.PreviewTripRequest\x1a\x19.trip.PreviewTripResponse\x12?\n" +
 	"\n" +
-	"CreateTrip\x12\x17.trip.CreateTripRequest\x1a\x18.trip.CreateTripResponseB\x18Z\x16shared/proto/trip;tripb\x06proto3"
+	"CreateTrip\x12\x17.trip.CreateTripRequest\x1a\x18.trip.CreateTripResponse\x12?\n" +
+	"\n" +
+	"CancelTrip\x12\x17.trip.CancelTripRequest\x1a\x18.trip.CancelTripResponse\x129\n" +
+	"\bRateTrip\x12\x15.trip.RateTripRequest\x1a\x16.trip.RateTripResponse\x12C\n" +
+	"\fUpdatePickup\x12\x19.trip.UpdatePickupRequest\x1a\x18.trip.UpdateTripResponse\x129\n" +
+	"\aAddStop\x12\x14.trip.AddStopRequest\x1a\x18.trip.UpdateTripResponse\x12E\n" +
+	"\fListPackages\x12\x19.trip.ListPackagesRequest\x1a\x1a.trip.ListPackagesResponseB%Z#ride-sharing/shared/proto/trip;tripb\x06proto3"
 
 var (
 	file_trip_proto_rawDescOnce sync.Once
@@ -690,39 +1512,79 @@ func file_trip_proto_rawDescGZIP() []byte {
 	return file_trip_proto_rawDescData
 }
 
-var file_trip_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_trip_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_trip_proto_goTypes = []any{
-	(*PreviewTripRequest)(nil),  // 0: trip.PreviewTripRequest
-	(*PreviewTripResponse)(nil), // 1: trip.PreviewTripResponse
-	(*CreateTripRequest)(nil),   // 2: trip.CreateTripRequest
-	(*CreateTripResponse)(nil),  // 3: trip.CreateTripResponse
-	(*Coordinate)(nil),          // 4: trip.Coordinate
-	(*Geometry)(nil),            // 5: trip.Geometry
-	(*Route)(nil),               // 6: trip.Route
-	(*RideFare)(nil),            // 7: trip.RideFare
-	(*Trip)(nil),                // 8: trip.Trip
-	(*TripDriver)(nil),          // 9: trip.TripDriver
+	(*PreviewTripRequest)(nil),   // 0: trip.PreviewTripRequest
+	(*PreviewTripResponse)(nil),  // 1: trip.PreviewTripResponse
+	(*CreateTripRequest)(nil),    // 2: trip.CreateTripRequest
+	(*CreateTripResponse)(nil),   // 3: trip.CreateTripResponse
+	(*CancelTripRequest)(nil),    // 4: trip.CancelTripRequest
+	(*CancelTripResponse)(nil),   // 5: trip.CancelTripResponse
+	(*RateTripRequest)(nil),      // 6: trip.RateTripRequest
+	(*RateTripResponse)(nil),     // 7: trip.RateTripResponse
+	(*UpdatePickupRequest)(nil),  // 8: trip.UpdatePickupRequest
+	(*AddStopRequest)(nil),       // 9: trip.AddStopRequest
+	(*UpdateTripResponse)(nil),   // 10: trip.UpdateTripResponse
+	(*ListPackagesRequest)(nil),  // 11: trip.ListPackagesRequest
+	(*ListPackagesResponse)(nil), // 12: trip.ListPackagesResponse
+	(*CarPackage)(nil),           // 13: trip.CarPackage
+	(*Coordinate)(nil),           // 14: trip.Coordinate
+	(*Geometry)(nil),             // 15: trip.Geometry
+	(*Route)(nil),                // 16: trip.Route
+	(*RideFare)(nil),             // 17: trip.RideFare
+	(*FareLineItem)(nil),         // 18: trip.FareLineItem
+	(*Trip)(nil),                 // 19: trip.Trip
+	(*TripDriver)(nil),           // 20: trip.TripDriver
+	(*money.Money)(nil),          // 21: money.Money
 }
 var file_trip_proto_depIdxs = []int32{
-	4,  // 0: trip.PreviewTripRequest.startLocation:type_name -> trip.Coordinate
-	4,  // 1: trip.PreviewTripRequest.endLocation:type_name -> trip.Coordinate
-	6,  // 2: trip.PreviewTripResponse.route:type_name -> trip.Route
-	7,  // 3: trip.PreviewTripResponse.rideFares:type_name -> trip.RideFare
-	8,  // 4: trip.CreateTripResponse.trip:type_name -> trip.Trip
-	4,  // 5: trip.Geometry.coordinates:type_name -> trip.Coordinate
-	5,  // 6: trip.Route.geometry:type_name -> trip.Geometry
-	7,  // 7: trip.Trip.selectedFare:type_name -> trip.RideFare
-	6,  // 8: trip.Trip.route:type_name -> trip.Route
-	9,  // 9: trip.Trip.driver:type_name -> trip.TripDriver
-	0,  // 10: trip.TripService.PreviewTrip:input_type -> trip.PreviewTripRequest
-	2,  // 11: trip.TripService.CreateTrip:input_type -> trip.CreateTripRequest
-	1,  // 12: trip.TripService.PreviewTrip:output_type -> trip.PreviewTripResponse
-	3,  // 13: trip.TripService.CreateTrip:output_type -> trip.CreateTripResponse
-	12, // [12:14] is the sub-list for method output_type
-	10, // [10:12] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	14, // 0: trip.PreviewTripRequest.startLocation:type_name -> trip.Coordinate
+	14, // 1: trip.PreviewTripRequest.endLocation:type_name -> trip.Coordinate
+	16, // 2: trip.PreviewTripResponse.route:type_name -> trip.Route
+	17, // 3: trip.PreviewTripResponse.rideFares:type_name -> trip.RideFare
+	19, // 4: trip.CreateTripResponse.trip:type_name -> trip.Trip
+	19, // 5: trip.CancelTripResponse.trip:type_name -> trip.Trip
+	21, // 6: trip.CancelTripResponse.cancellationFee:type_name -> money.Money
+	19, // 7: trip.RateTripResponse.trip:type_name -> trip.Trip
+	14, // 8: trip.UpdatePickupRequest.pickup:type_name -> trip.Coordinate
+	14, // 9: trip.AddStopRequest.location:type_name -> trip.Coordinate
+	19, // 10: trip.UpdateTripResponse.trip:type_name -> trip.Trip
+	14, // 11: trip.ListPackagesRequest.location:type_name -> trip.Coordinate
+	13, // 12: trip.ListPackagesResponse.packages:type_name -> trip.CarPackage
+	21, // 13: trip.CarPackage.baseFare:type_name -> money.Money
+	21, // 14: trip.CarPackage.pricePerKm:type_name -> money.Money
+	21, // 15: trip.CarPackage.pricePerMinute:type_name -> money.Money
+	21, // 16: trip.CarPackage.minimumFare:type_name -> money.Money
+	21, // 17: trip.CarPackage.bookingFee:type_name -> money.Money
+	14, // 18: trip.Geometry.coordinates:type_name -> trip.Coordinate
+	15, // 19: trip.Route.geometry:type_name -> trip.Geometry
+	18, // 20: trip.RideFare.lineItems:type_name -> trip.FareLineItem
+	21, // 21: trip.RideFare.totalPrice:type_name -> money.Money
+	21, // 22: trip.FareLineItem.amount:type_name -> money.Money
+	17, // 23: trip.Trip.selectedFare:type_name -> trip.RideFare
+	16, // 24: trip.Trip.route:type_name -> trip.Route
+	20, // 25: trip.Trip.driver:type_name -> trip.TripDriver
+	14, // 26: trip.Trip.pickup:type_name -> trip.Coordinate
+	14, // 27: trip.Trip.stops:type_name -> trip.Coordinate
+	0,  // 28: trip.TripService.PreviewTrip:input_type -> trip.PreviewTripRequest
+	2,  // 29: trip.TripService.CreateTrip:input_type -> trip.CreateTripRequest
+	4,  // 30: trip.TripService.CancelTrip:input_type -> trip.CancelTripRequest
+	6,  // 31: trip.TripService.RateTrip:input_type -> trip.RateTripRequest
+	8,  // 32: trip.TripService.UpdatePickup:input_type -> trip.UpdatePickupRequest
+	9,  // 33: trip.TripService.AddStop:input_type -> trip.AddStopRequest
+	11, // 34: trip.TripService.ListPackages:input_type -> trip.ListPackagesRequest
+	1,  // 35: trip.TripService.PreviewTrip:output_type -> trip.PreviewTripResponse
+	3,  // 36: trip.TripService.CreateTrip:output_type -> trip.CreateTripResponse
+	5,  // 37: trip.TripService.CancelTrip:output_type -> trip.CancelTripResponse
+	7,  // 38: trip.TripService.RateTrip:output_type -> trip.RateTripResponse
+	10, // 39: trip.TripService.UpdatePickup:output_type -> trip.UpdateTripResponse
+	10, // 40: trip.TripService.AddStop:output_type -> trip.UpdateTripResponse
+	12, // 41: trip.TripService.ListPackages:output_type -> trip.ListPackagesResponse
+	35, // [35:42] is the sub-list for method output_type
+	28, // [28:35] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
 }
 
 func init() { file_trip_proto_init() }
@@ -736,7 +1598,7 @@ func file_trip_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_trip_proto_rawDesc), len(file_trip_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
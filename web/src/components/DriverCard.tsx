@@ -33,6 +33,12 @@ export const DriverCard = ({ driver, packageSlug }: { driver?: Driver | null, pa
           </p>
         )}
 
+        {driver.vehicle && (
+          <p className="text-sm text-gray-600">
+            {driver.vehicle.colour} {driver.vehicle.make} {driver.vehicle.model}
+          </p>
+        )}
+
         {packageSlug && (
           <p className="text-sm">
             <span className="font-mono">{packageSlug}</span> driver
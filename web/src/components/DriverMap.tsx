@@ -5,6 +5,7 @@ import { MapContainer, Marker, Popup, TileLayer } from 'react-leaflet'
 import L from 'leaflet';
 import { MapClickHandler } from './MapClickHandler';
 import { useMemo, useState } from "react";
+import { useSearchParams } from "next/navigation";
 import { useRef } from "react";
 import { CarPackageSlug, Coordinate } from "../types";
 import { DriverTripOverview } from "./DriverTripOverview";
@@ -38,7 +39,9 @@ const destinationMarker = new L.Icon({
 
 export const DriverMap = ({ packageSlug }: { packageSlug: CarPackageSlug }) => {
   const mapRef = useRef<L.Map>(null)
-  const userID = useMemo(() => crypto.randomUUID(), [])
+  const searchParams = useSearchParams()
+  // Drivers need a profile in the driver registry, the demo profiles are seeded in development
+  const userID = useMemo(() => searchParams.get("driverID") ?? `demo-driver-${packageSlug}`, [searchParams, packageSlug])
   const [riderLocation, setRiderLocation] = useState<Coordinate>(START_LOCATION)
 
   const driverGeohash = useMemo(() =>
@@ -105,6 +108,22 @@ export const DriverMap = ({ packageSlug }: { packageSlug: CarPackageSlug }) => {
     resetTripStatus()
   }
 
+  const handleTripProgress = (type: TripEvents.DriverTripArrive | TripEvents.DriverTripStart | TripEvents.DriverTripEnd) => {
+    if (!requestedTrip || !requestedTrip.id) {
+      alert("No trip ID found")
+      return
+    }
+
+    sendMessage({
+      type,
+      data: {
+        tripID: requestedTrip.id,
+      }
+    })
+
+    setTripStatus(type)
+  }
+
   const parsedRoute = useMemo(() =>
     requestedTrip?.route?.geometry[0]?.coordinates
       .map((coord) => [coord?.longitude, coord?.latitude] as [number, number])
@@ -180,6 +199,10 @@ export const DriverMap = ({ packageSlug }: { packageSlug: CarPackageSlug }) => {
             status={tripStatus}
             onAcceptTrip={handleAcceptTrip}
             onDeclineTrip={handleDeclineTrip}
+            onArriveAtPickup={() => handleTripProgress(TripEvents.DriverTripArrive)}
+            onStartTrip={() => handleTripProgress(TripEvents.DriverTripStart)}
+            onEndTrip={() => handleTripProgress(TripEvents.DriverTripEnd)}
+            onFinish={resetTripStatus}
           />
         </div>
       </div>
@@ -7,10 +7,14 @@ interface DriverTripOverviewProps {
   trip?: Trip | null,
   status?: TripEvents | null,
   onAcceptTrip?: () => void,
-  onDeclineTrip?: () => void
+  onDeclineTrip?: () => void,
+  onArriveAtPickup?: () => void,
+  onStartTrip?: () => void,
+  onEndTrip?: () => void,
+  onFinish?: () => void
 }
 
-export const DriverTripOverview = ({ trip, status, onAcceptTrip, onDeclineTrip }: DriverTripOverviewProps) => {
+export const DriverTripOverview = ({ trip, status, onAcceptTrip, onDeclineTrip, onArriveAtPickup, onStartTrip, onEndTrip, onFinish }: DriverTripOverviewProps) => {
   if (!trip) {
     return (
       <TripOverviewCard
@@ -34,25 +38,69 @@ export const DriverTripOverview = ({ trip, status, onAcceptTrip, onDeclineTrip }
     )
   }
 
+  const tripDetails = (
+    <div className="flex flex-col gap-2">
+      <h3 className="text-lg font-bold">Trip details</h3>
+      <p className="text-sm text-gray-500">
+        Trip ID: {trip.id}
+        <br />
+        Rider ID: {trip.userID}
+      </p>
+    </div>
+  )
+
   if (status === TripEvents.DriverTripAccept) {
     return (
       <TripOverviewCard
         title="All set!"
-        description="You can now start the trip"
+        description="Drive to the pickup and let the rider know when you are there"
+      >
+        <div className="flex flex-col gap-4">
+          {tripDetails}
+          <Button onClick={onArriveAtPickup}>Arrived at pickup</Button>
+        </div>
+      </TripOverviewCard>
+    )
+  }
+
+  if (status === TripEvents.DriverTripArrive) {
+    return (
+      <TripOverviewCard
+        title="Waiting for the rider"
+        description="Start the trip once the rider is in the car"
+      >
+        <div className="flex flex-col gap-4">
+          {tripDetails}
+          <Button onClick={onStartTrip}>Start trip</Button>
+        </div>
+      </TripOverviewCard>
+    )
+  }
+
+  if (status === TripEvents.DriverTripStart) {
+    return (
+      <TripOverviewCard
+        title="On the way"
+        description="End the trip at the destination, the rider is then asked to pay"
       >
         <div className="flex flex-col gap-4">
-          <div className="flex flex-col gap-2">
-            <h3 className="text-lg font-bold">Trip details</h3>
-            <p className="text-sm text-gray-500">
-              Trip ID: {trip.id}
-              <br />
-              Rider ID: {trip.userID}
-            </p>
-          </div>
+          {tripDetails}
+          <Button onClick={onEndTrip}>End trip</Button>
         </div>
       </TripOverviewCard>
     )
   }
 
+  if (status === TripEvents.DriverTripEnd) {
+    return (
+      <TripOverviewCard
+        title="Trip completed!"
+        description="The rider has been asked to pay for the trip"
+      >
+        <Button onClick={onFinish}>Look for another trip</Button>
+      </TripOverviewCard>
+    )
+  }
+
   return null
 }
\ No newline at end of file
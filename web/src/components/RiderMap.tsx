@@ -1,10 +1,8 @@
 'use client';
 
-import Image from 'next/image';
 import { useRiderStreamConnection } from '../hooks/useRiderStreamConnection';
-import { MapContainer, Marker, Popup, Rectangle, TileLayer } from 'react-leaflet'
+import { MapContainer, Marker, Popup, TileLayer } from 'react-leaflet'
 import L from 'leaflet';
-import { getGeohashBounds } from '../utils/geohash';
 import { useMemo, useRef, useState } from 'react';
 import { MapClickHandler } from './MapClickHandler';
 import { Button } from './ui/button';
@@ -12,7 +10,7 @@ import { RouteFare, RequestRideProps, TripPreview, HTTPTripStartResponse } from
 import { RoutingControl } from "./RoutingControl";
 import { API_URL } from '../constants';
 import { RiderTripOverview } from './RiderTripOverview';
-import { BackendEndpoints, HTTPTripPreviewRequestPayload, HTTPTripPreviewResponse, HTTPTripStartRequestPayload } from '../contracts';
+import { BackendEndpoints, HTTPTripPreviewRequestPayload, HTTPTripPreviewResponse, HTTPTripRatingRequestPayload, HTTPTripStartRequestPayload, tripRatingEndpoint } from '../contracts';
 
 const userMarker = new L.Icon({
     iconUrl: "https://upload.wikimedia.org/wikipedia/commons/thumb/e/ed/Map_pin_icon.svg/176px-Map_pin_icon.svg.png",
@@ -138,6 +136,24 @@ export default function RiderMap({ onRouteSelected }: RiderMapProps) {
         return data
     }
 
+    const handleRateTrip = async (rating: number) => {
+        if (!trip?.tripID) {
+            return false
+        }
+
+        const payload = {
+            userID: userID,
+            rating: rating,
+        } as HTTPTripRatingRequestPayload
+
+        const response = await fetch(`${API_URL}${tripRatingEndpoint(trip.tripID)}`, {
+            method: 'POST',
+            body: JSON.stringify(payload),
+        })
+
+        return response.ok
+    }
+
     const handleCancelTrip = () => {
         setTrip(null)
         setDestination(null)
@@ -163,43 +179,15 @@ export default function RiderMap({ onRouteSelected }: RiderMapProps) {
                     />
                     <Marker position={[location.latitude, location.longitude]} icon={userMarker} />
 
-                    {/* Render geohash grid cells */}
-                    {drivers?.map((driver) => (
-                        <Rectangle
-                            key={`grid-${driver?.geohash}`}
-                            bounds={getGeohashBounds(driver?.geohash) as L.LatLngBoundsExpression}
-                            pathOptions={{
-                                color: '#3388ff',
-                                weight: 1,
-                                fillOpacity: 0.1
-                            }}
-                        >
-                            <Popup>Geohash: {driver?.geohash}</Popup>
-                        </Rectangle>
-                    ))}
-
-                    {/* Render driver markers */}
+                    {/* Render the cars around the rider, their positions are approximate */}
                     {drivers?.map((driver) => (
                         <Marker
-                            key={driver?.id}
-                            position={[driver?.location?.latitude, driver?.location?.longitude]}
+                            key={driver.id}
+                            position={[driver.location.latitude, driver.location.longitude]}
                             icon={driverMarker}
                         >
                             <Popup>
-                                Driver ID: {driver?.id}
-                                <br />
-                                Geohash: {driver?.geohash}
-                                <br />
-                                Name: {driver?.name}
-                                <br />
-                                Car Plate: {driver?.carPlate}
-                                <br />
-                                <Image
-                                    src={driver?.profilePicture}
-                                    alt={`${driver?.name}'s profile picture`}
-                                    width={100}
-                                    height={100}
-                                />
+                                Package: {driver.packageSlug}
                             </Popup>
                         </Marker>
                     ))}
@@ -231,6 +219,7 @@ export default function RiderMap({ onRouteSelected }: RiderMapProps) {
                     paymentSession={paymentSession}
                     onPackageSelect={handleStartTrip}
                     onCancel={handleCancelTrip}
+                    onRate={handleRateTrip}
                 />
             </div>
         </div>
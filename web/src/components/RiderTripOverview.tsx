@@ -3,10 +3,12 @@ import { DriverList } from "./DriversList"
 import { Card } from "./ui/card"
 import { Button } from "./ui/button"
 import { convertMetersToKilometers, convertSecondsToMinutes } from "../utils/math"
+import { formatMoney } from "../utils/money"
 import { Skeleton } from "./ui/skeleton"
 import { TripOverviewCard } from "./TripOverviewCard"
 import { StripePaymentButton } from "./StripePaymentButton"
 import { DriverCard } from "./DriverCard"
+import { TripRating } from "./TripRating"
 import { TripEvents, PaymentEventSessionCreatedData } from "../contracts"
 
 interface TripOverviewProps {
@@ -16,6 +18,7 @@ interface TripOverviewProps {
   paymentSession?: PaymentEventSessionCreatedData | null;
   onPackageSelect: (carPackage: RouteFare) => void;
   onCancel: () => void;
+  onRate: (rating: number) => Promise<boolean>;
 }
 
 export const RiderTripOverview = ({
@@ -25,6 +28,7 @@ export const RiderTripOverview = ({
   paymentSession,
   onPackageSelect,
   onCancel,
+  onRate,
 }: TripOverviewProps) => {
   if (!trip) {
     return (
@@ -45,7 +49,7 @@ export const RiderTripOverview = ({
           <DriverCard driver={assignedDriver} />
 
           <div className="text-sm text-gray-500">
-            <p>Amount: {paymentSession.amount} {paymentSession.currency}</p>
+            <p>Amount: {formatMoney(paymentSession.amount)}</p>
             <p>Trip ID: {paymentSession.tripID}</p>
           </div>
           <StripePaymentButton paymentSession={paymentSession} />
@@ -89,6 +93,7 @@ export const RiderTripOverview = ({
         title="Trip completed!"
         description="Your trip is completed, thank you for using our service!"
       >
+        <TripRating onRate={onRate} />
         <Button variant="outline" className="w-full" onClick={onCancel}>
           Go back
         </Button>
@@ -1,6 +1,7 @@
 import { PaymentEventSessionCreatedData } from "../contracts"
 import { Button } from "./ui/button"
 import { loadStripe } from "@stripe/stripe-js"
+import { formatMoney } from "../utils/money"
 
 interface StripePaymentButtonProps {
   paymentSession: PaymentEventSessionCreatedData
@@ -47,7 +48,7 @@ export const StripePaymentButton = ({
       disabled={isLoading}
       className="w-full"
     >
-      {isLoading ? "Loading..." : `Pay ${paymentSession.amount} ${paymentSession.currency}`}
+      {isLoading ? "Loading..." : `Pay ${formatMoney(paymentSession.amount)}`}
     </Button>
   )
 } 
\ No newline at end of file
@@ -0,0 +1,45 @@
+import { useState } from "react"
+import { Button } from "./ui/button"
+
+interface TripRatingProps {
+  onRate: (rating: number) => Promise<boolean>;
+}
+
+const STARS = [1, 2, 3, 4, 5]
+
+export const TripRating = ({ onRate }: TripRatingProps) => {
+  const [rating, setRating] = useState(0)
+  const [status, setStatus] = useState<"idle" | "sending" | "sent" | "failed">("idle")
+
+  const handleRate = async (stars: number) => {
+    setRating(stars)
+    setStatus("sending")
+    setStatus(await onRate(stars) ? "sent" : "failed")
+  }
+
+  if (status === "sent") {
+    return <p className="text-sm text-gray-700 text-center mb-4">Thanks for rating your driver!</p>
+  }
+
+  return (
+    <div className="flex flex-col items-center gap-2 mb-4">
+      <p className="text-sm font-medium text-gray-700">How was your driver?</p>
+      <div className="flex gap-1">
+        {STARS.map((stars) => (
+          <Button
+            key={stars}
+            variant="ghost"
+            size="icon"
+            disabled={status === "sending"}
+            aria-label={`${stars} star${stars > 1 ? "s" : ""}`}
+            className={stars <= rating ? "text-yellow-500" : "text-gray-300"}
+            onClick={() => handleRate(stars)}
+          >
+            ★
+          </Button>
+        ))}
+      </div>
+      {status === "failed" && <p className="text-sm text-red-500">Failed to send your rating, please try again</p>}
+    </div>
+  )
+}
@@ -1,4 +1,4 @@
-import { Coordinate, Driver, Route, RouteFare, Trip } from "./types";
+import { Coordinate, Driver, Money, NearbyDriver, Route, RouteFare, Trip } from "./types";
 
 
 // These are the endpoints the API Gateway must have for the frontend to work correctly
@@ -9,38 +9,89 @@ export enum BackendEndpoints {
   WS_RIDERS = "/riders",
 }
 
+export const tripRatingEndpoint = (tripID: string) => `/trip/${tripID}/rating`
+
 export enum TripEvents {
   NoDriversFound = "trip.event.no_drivers_found",
   DriverAssigned = "trip.event.driver_assigned",
   Completed = "trip.event.completed",
   Cancelled = "trip.event.cancelled",
+  Updated = "trip.event.updated",
   Created = "trip.event.created",
   DriverLocation = "driver.cmd.location",
   DriverTripRequest = "driver.cmd.trip_request",
   DriverTripAccept = "driver.cmd.trip_accept",
   DriverTripDecline = "driver.cmd.trip_decline",
+  DriverTripArrive = "driver.cmd.trip_arrive",
+  DriverTripStart = "driver.cmd.trip_start",
+  DriverTripEnd = "driver.cmd.trip_end",
   DriverRegister = "driver.cmd.register",
   PaymentSessionCreated = "payment.event.session_created",
+  NearbyDrivers = "driver.event.nearby",
+  CommandAck = "rider.event.command_ack",
+  CommandError = "rider.event.command_error",
+}
+
+export enum RiderCommands {
+  WatchNearbyDrivers = "rider.cmd.watch_nearby_drivers",
+  UnwatchNearbyDrivers = "rider.cmd.unwatch_nearby_drivers",
+  TripCancel = "rider.cmd.trip_cancel",
+  UpdatePickup = "rider.cmd.update_pickup",
+  AddStop = "rider.cmd.add_stop",
+  RateDriver = "rider.cmd.rate_driver",
+  RequestReceipt = "rider.cmd.request_receipt",
 }
 
-// Messages sent from the server to the client via the websocket
-export type ServerWsMessage =
+// Messages sent from the server to the client via the websocket.
+// seq increases with every message of a user, reconnecting with the last one seen replays what was missed.
+export type ServerWsMessage = { seq?: number } & (
   | PaymentSessionCreatedRequest
   | DriverAssignedRequest
-  | DriverLocationRequest
+  | NearbyDriversRequest
   | DriverTripRequest
   | DriverRegisterRequest
   | TripCreatedRequest
-  | NoDriversFoundRequest;
+  | TripUpdatedRequest
+  | CommandAckRequest
+  | CommandErrorRequest
+  | NoDriversFoundRequest
+);
 
 // Messages sent from the client to the server via the websocket
-export type ClientWsMessage = DriverResponseToTripResponse
+export type ClientWsMessage = DriverResponseToTripResponse | DriverTripProgressCommand
 
 interface TripCreatedRequest {
   type: TripEvents.Created;
   data: Trip;
 }
 
+// Sent to the driver when the rider moves the pickup or adds a stop
+interface TripUpdatedRequest {
+  type: TripEvents.Updated;
+  data: Trip;
+}
+
+// Every rider command is answered by an ack or an error echoing its requestID
+interface CommandAckRequest {
+  type: TripEvents.CommandAck;
+  data: {
+    requestID?: string;
+    command: RiderCommands;
+    result?: unknown;
+  };
+}
+
+interface CommandErrorRequest {
+  type: TripEvents.CommandError;
+  data: {
+    requestID?: string;
+    command: string;
+    // gRPC status code, e.g. InvalidArgument or FailedPrecondition
+    code: string;
+    message: string;
+  };
+}
+
 interface NoDriversFoundRequest {
   type: TripEvents.NoDriversFound;
 }
@@ -57,8 +108,7 @@ interface DriverTripRequest {
 export interface PaymentEventSessionCreatedData {
   tripID: string;
   sessionID: string;
-  amount: number;
-  currency: string;
+  amount: Money;
 }
 
 interface PaymentSessionCreatedRequest {
@@ -71,11 +121,36 @@ interface DriverAssignedRequest {
   data: Trip;
 }
 
-interface DriverLocationRequest {
-  type: TripEvents.DriverLocation;
-  data: Driver[];
+// Updates of the cars around the rider, the first one of a watch is a snapshot replacing the known cars
+interface NearbyDriversRequest {
+  type: TripEvents.NearbyDrivers;
+  data: {
+    upserted?: NearbyDriver[];
+    removed?: string[];
+    snapshot?: boolean;
+  };
 }
 
+// Riders watch either a viewport or a circle around a point
+export interface WatchNearbyDriversCommand {
+  type: RiderCommands.WatchNearbyDrivers;
+  data: {
+    viewport?: { southWest: Coordinate; northEast: Coordinate };
+    center?: Coordinate;
+    radiusMeters?: number;
+    packageSlug?: string;
+  };
+}
+
+// Commands riders send about their trip, answered by a command ack or error
+export type RiderTripCommand = { requestID?: string } & (
+  | { type: RiderCommands.TripCancel; data: { tripID: string; reason?: string } }
+  | { type: RiderCommands.UpdatePickup; data: { tripID: string; pickup: Coordinate } }
+  | { type: RiderCommands.AddStop; data: { tripID: string; location: Coordinate } }
+  | { type: RiderCommands.RateDriver; data: { tripID: string; rating: number; comment?: string } }
+  | { type: RiderCommands.RequestReceipt; data: { tripID: string } }
+);
+
 interface DriverResponseToTripResponse {
   type: TripEvents.DriverTripAccept | TripEvents.DriverTripDecline;
   data: {
@@ -85,6 +160,14 @@ interface DriverResponseToTripResponse {
   };
 }
 
+// Sent by the assigned driver as the trip goes on, ending it charges the rider
+interface DriverTripProgressCommand {
+  type: TripEvents.DriverTripArrive | TripEvents.DriverTripStart | TripEvents.DriverTripEnd;
+  data: {
+    tripID: string;
+  };
+}
+
 export interface HTTPTripPreviewResponse {
   route: Route;
   rideFares: RouteFare[];
@@ -95,6 +178,13 @@ export interface HTTPTripStartRequestPayload {
   userID: string;
 }
 
+export interface HTTPTripRatingRequestPayload {
+  userID: string;
+  // From 1 to 5 stars
+  rating: number;
+  comment?: string;
+}
+
 export interface HTTPTripPreviewRequestPayload {
   userID: string;
   pickup: Coordinate;
@@ -3,6 +3,9 @@ import { WEBSOCKET_URL } from "../constants";
 import { Trip, Driver, CarPackageSlug } from '../types';
 import { ServerWsMessage, TripEvents, isValidWsMessage, isValidTripEvent, ClientWsMessage, BackendEndpoints } from '../contracts';
 
+const LOCATION_HEARTBEAT_MS = 30_000;
+const RECONNECT_DELAY_MS = 2000;
+
 interface useDriverConnectionProps {
   location: {
     latitude: number;
@@ -28,59 +31,89 @@ export const useDriverStreamConnection = ({
   useEffect(() => {
     if (!userID) return;
 
-    const websocket = new WebSocket(`${WEBSOCKET_URL}${BackendEndpoints.WS_DRIVERS}?userID=${userID}&packageSlug=${packageSlug}`);
-    setWs(websocket);
-
-    websocket.onopen = () => {
-      if (location) {
+    let websocket: WebSocket;
+    let closed = false;
+    let reconnectTimeout: ReturnType<typeof setTimeout> | undefined;
+    // Sequence number of the last message received, sent back on reconnect to get the ones missed meanwhile
+    let lastSeq: number | undefined;
+    // Drivers that stop reporting their location are no longer offered trips,
+    // so keep reporting it even when it does not change
+    let locationInterval: ReturnType<typeof setInterval> | undefined;
+
+    const connect = () => {
+      const seqParam = lastSeq !== undefined ? `&lastSeq=${lastSeq}` : '';
+      websocket = new WebSocket(`${WEBSOCKET_URL}${BackendEndpoints.WS_DRIVERS}?userID=${userID}&packageSlug=${packageSlug}${seqParam}`);
+      setWs(websocket);
+
+      const sendLocation = () => {
+        if (location && websocket.readyState === WebSocket.OPEN) {
+          websocket.send(JSON.stringify({
+            type: TripEvents.DriverLocation,
+            data: {
+              location,
+              geohash,
+            }
+          }));
+        }
+      };
+
+      websocket.onopen = () => {
         // Send initial location
-        websocket.send(JSON.stringify({
-          type: TripEvents.DriverLocation,
-          data: {
-            location,
-            geohash,
-          }
-        }));
-      }
+        sendLocation();
+        locationInterval = setInterval(sendLocation, LOCATION_HEARTBEAT_MS);
+      };
+
+      websocket.onmessage = (event) => {
+        const message = JSON.parse(event.data) as ServerWsMessage;
+
+        if (!message || !isValidWsMessage(message)) {
+          setError(`Unknown message type "${message}", allowed types are: ${Object.values(TripEvents).join(', ')}`);
+          return;
+        }
+
+        if (message.seq !== undefined && (lastSeq === undefined || message.seq > lastSeq)) {
+          lastSeq = message.seq;
+        }
+
+        switch (message.type) {
+          case TripEvents.DriverTripRequest:
+            const trip = (message.data?.trip) ?? message.data;
+            setRequestedTrip(trip);
+            break;
+          case TripEvents.DriverRegister:
+            setDriver(message.data);
+            break;
+        }
+
+
+        if (isValidTripEvent(message.type)) {
+          setTripStatus(message.type);
+        } else {
+          setError(`Unknown message type "${message.type}", allowed types are: ${Object.values(TripEvents).join(', ')}`);
+        }
+      };
+
+      websocket.onclose = () => {
+        clearInterval(locationInterval);
+        console.log('WebSocket closed');
+        if (!closed) {
+          reconnectTimeout = setTimeout(connect, RECONNECT_DELAY_MS);
+        }
+      };
+
+      websocket.onerror = (event) => {
+        setError('WebSocket error occurred');
+        console.error('WebSocket error:', event);
+      };
     };
 
-    websocket.onmessage = (event) => {
-      const message = JSON.parse(event.data) as ServerWsMessage;
-
-      if (!message || !isValidWsMessage(message)) {
-        setError(`Unknown message type "${message}", allowed types are: ${Object.values(TripEvents).join(', ')}`);
-        return;
-      }
-
-      switch (message.type) {
-        case TripEvents.DriverTripRequest:
-          const trip = (message.data?.trip) ?? message.data;
-          setRequestedTrip(trip);
-          break;
-        case TripEvents.DriverRegister:
-          setDriver(message.data);
-          break;
-      }
-
-
-      if (isValidTripEvent(message.type)) {
-        setTripStatus(message.type);
-      } else {
-        setError(`Unknown message type "${message.type}", allowed types are: ${Object.values(TripEvents).join(', ')}`);
-      }
-    };
-
-    websocket.onclose = () => {
-      console.log('WebSocket closed');
-    };
-
-    websocket.onerror = (event) => {
-      setError('WebSocket error occurred');
-      console.error('WebSocket error:', event);
-    };
+    connect();
 
     return () => {
+      clearInterval(locationInterval);
       console.log('Closing WebSocket');
+      closed = true;
+      clearTimeout(reconnectTimeout);
       if (websocket.readyState === WebSocket.OPEN) {
         websocket.close();
       }
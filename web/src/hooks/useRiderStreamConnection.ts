@@ -1,11 +1,15 @@
 import { useEffect, useState } from 'react';
 import { WEBSOCKET_URL } from "../constants";
 import { Trip } from '../types';
-import { Driver, Coordinate } from '../types';
-import { PaymentEventSessionCreatedData, TripEvents, ServerWsMessage, isValidWsMessage, BackendEndpoints } from '../contracts';
+import { NearbyDriver, Coordinate } from '../types';
+import { PaymentEventSessionCreatedData, TripEvents, ServerWsMessage, isValidWsMessage, BackendEndpoints, RiderCommands, WatchNearbyDriversCommand } from '../contracts';
+
+// Radius around the rider in which cars are shown
+const NEARBY_DRIVERS_RADIUS_METERS = 3000;
+const RECONNECT_DELAY_MS = 2000;
 
 export function useRiderStreamConnection(location: Coordinate, userID: string) {
-  const [drivers, setDrivers] = useState<Driver[]>([]);
+  const [drivers, setDrivers] = useState<NearbyDriver[]>([]);
   const [tripStatus, setTripStatus] = useState<TripEvents | null>(null);
   const [paymentSession, setPaymentSession] = useState<PaymentEventSessionCreatedData | null>(null);
   const [assignedDriver, setAssignedDriver] = useState<Trip["driver"] | null>(null);
@@ -14,60 +18,89 @@ export function useRiderStreamConnection(location: Coordinate, userID: string) {
   useEffect(() => {
     if (!userID) return;
 
-    const ws = new WebSocket(`${WEBSOCKET_URL}${BackendEndpoints.WS_RIDERS}?userID=${userID}`);
+    let ws: WebSocket;
+    let closed = false;
+    let reconnectTimeout: ReturnType<typeof setTimeout> | undefined;
+    // Sequence number of the last message received, sent back on reconnect to get the ones missed meanwhile
+    let lastSeq: number | undefined;
 
-    ws.onopen = () => {
-      // Send initial location
-      if (location) {
-        ws.send(JSON.stringify({
-          type: TripEvents.DriverLocation,
-          data: {
-            location,
-          }
-        }));
-      }
-    };
+    const connect = () => {
+      const seqParam = lastSeq !== undefined ? `&lastSeq=${lastSeq}` : '';
+      ws = new WebSocket(`${WEBSOCKET_URL}${BackendEndpoints.WS_RIDERS}?userID=${userID}${seqParam}`);
 
-    ws.onmessage = (event) => {
-      const message = JSON.parse(event.data) as ServerWsMessage;
+      ws.onopen = () => {
+        // Watch the cars around the rider's location
+        if (location) {
+          const command: WatchNearbyDriversCommand = {
+            type: RiderCommands.WatchNearbyDrivers,
+            data: {
+              center: location,
+              radiusMeters: NEARBY_DRIVERS_RADIUS_METERS,
+            }
+          };
+          ws.send(JSON.stringify(command));
+        }
+      };
 
-      if (!message || !isValidWsMessage(message)) {
-        setError(`Unknown message type "${message}", allowed types are: ${Object.values(TripEvents).join(', ')}`);
-        return;
-      }
+      ws.onmessage = (event) => {
+        const message = JSON.parse(event.data) as ServerWsMessage;
 
-      switch (message.type) {
-        case TripEvents.DriverLocation:
-          setDrivers(message.data);
-          break;
-        case TripEvents.PaymentSessionCreated:
-          setPaymentSession(message.data);
-          setTripStatus(message.type);
-          break;
-        case TripEvents.DriverAssigned:
-          setAssignedDriver(message.data.driver);
-          setTripStatus(message.type);
-          break;
-        case TripEvents.Created:
-          setTripStatus(message.type);
-          break;
-        case TripEvents.NoDriversFound:
-          setTripStatus(message.type);
-          break;
-      }
-    };
+        if (!message || !isValidWsMessage(message)) {
+          setError(`Unknown message type "${message}", allowed types are: ${Object.values(TripEvents).join(', ')}`);
+          return;
+        }
 
-    ws.onclose = () => {
-      console.log('WebSocket closed');
-    };
+        if (message.seq !== undefined && (lastSeq === undefined || message.seq > lastSeq)) {
+          lastSeq = message.seq;
+        }
+
+        switch (message.type) {
+          case TripEvents.NearbyDrivers: {
+            const { upserted = [], removed = [], snapshot } = message.data;
+            setDrivers((previous) => {
+              const byID = new Map(snapshot ? [] : previous.map((driver) => [driver.id, driver]));
+              removed.forEach((id) => byID.delete(id));
+              upserted.forEach((driver) => byID.set(driver.id, driver));
+              return Array.from(byID.values());
+            });
+            break;
+          }
+          case TripEvents.PaymentSessionCreated:
+            setPaymentSession(message.data);
+            setTripStatus(message.type);
+            break;
+          case TripEvents.DriverAssigned:
+            setAssignedDriver(message.data.driver);
+            setTripStatus(message.type);
+            break;
+          case TripEvents.Created:
+            setTripStatus(message.type);
+            break;
+          case TripEvents.NoDriversFound:
+            setTripStatus(message.type);
+            break;
+        }
+      };
+
+      ws.onclose = () => {
+        console.log('WebSocket closed');
+        if (!closed) {
+          reconnectTimeout = setTimeout(connect, RECONNECT_DELAY_MS);
+        }
+      };
 
-    ws.onerror = (event) => {
-      setError('WebSocket error occurred');
-      console.error('WebSocket error:', event);
+      ws.onerror = (event) => {
+        setError('WebSocket error occurred');
+        console.error('WebSocket error:', event);
+      };
     };
 
+    connect();
+
     return () => {
       console.log('Closing WebSocket');
+      closed = true;
+      clearTimeout(reconnectTimeout);
       if (ws.readyState === WebSocket.OPEN) {
         ws.close();
       }
@@ -37,11 +37,26 @@ export interface RouteFare {
     id: string,
     packageSlug: CarPackageSlug,
     basePrice: number,
-    totalPriceInCents?: number,
+    totalPrice?: Money,
+    lineItems?: FareLineItem[],
+    surgeMultiplier?: number,
+    promoCode?: string,
     expiresAt: Date,
     route: Route,
 }
 
+export interface FareLineItem {
+    type: "base" | "distance" | "time" | "minimum_fare" | "surge" | "booking_fee" | "discount" | "tax" | "cancellation_fee",
+    description: string,
+    amount: Money,
+}
+
+// Amount in the minor units of the currency, e.g. cents for USD
+export interface Money {
+    amount: number,
+    currency: string,
+}
+
 
 export interface HTTPTripStartResponse {
     tripID: string;
@@ -63,4 +78,21 @@ export interface Driver {
     name: string;
     profilePicture: string;
     carPlate: string;
+    vehicle?: Vehicle;
+}
+
+export interface Vehicle {
+    plate: string;
+    make: string;
+    model: string;
+    colour: string;
+    seats: number;
+}
+
+// NearbyDriver is a car shown to riders before booking, its position is approximate
+// and its id only identifies it for as long as the rider watches
+export interface NearbyDriver {
+    id: string,
+    packageSlug: CarPackageSlug,
+    location: Coordinate,
 }
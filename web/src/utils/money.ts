@@ -0,0 +1,9 @@
+import { Money } from "../types"
+
+export function formatMoney(money: Money) {
+  const formatter = new Intl.NumberFormat("en-US", { style: "currency", currency: money.currency })
+  // Intl knows how many decimals the minor unit of each currency has
+  const { maximumFractionDigits = 2 } = formatter.resolvedOptions()
+
+  return formatter.format(money.amount / Math.pow(10, maximumFractionDigits))
+}